@@ -0,0 +1,165 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// achievementToast is the number of ticks the name of an achievement is
+// shown after it was earned.
+const achievementToast = 30
+
+// Achievement is a goal that is unlocked once it was reached in a game.
+type Achievement struct {
+	ID          string
+	Name        string
+	Description string
+	// Reached tells whether game reached the goal. It is checked after every
+	// tick and once the game is over.
+	Reached func(game *Game) bool
+}
+
+// AchievementList are the achievements that can be earned.
+var AchievementList = []Achievement{
+	{"first-win", "First win", "Win a game.", func(game *Game) bool {
+		return game.Over() && game.WonGame()
+	}},
+	{"length-100", "Centipede", "Reach a length of 100.", func(game *Game) bool {
+		return game.Length() >= 100
+	}},
+	{"no-wrap", "Straight and narrow", "Win a game without wrapping around the edges.", func(game *Game) bool {
+		return game.Over() && game.WonGame() && !game.hasWrapped
+	}},
+	{"time-attack-500", "Against the clock", "Score 500 points in time attack.", func(game *Game) bool {
+		return game.TimeAttack && game.Score() >= 500
+	}},
+	{"perfect-10", "Perfectionist", "Eat 10 food perfectly in one game.", func(game *Game) bool {
+		return game.Perfects() >= 10
+	}},
+	{"daily", "Daily routine", "Finish a daily challenge.", func(game *Game) bool {
+		return game.Over() && game.Daily
+	}},
+	{"score-1000", "Four digits", "Score 1000 points.", func(game *Game) bool {
+		return game.Score() >= 1000
+	}},
+}
+
+// Achievements are the achievements unlocked so far, kept as json in the file
+// at Path.
+type Achievements struct {
+	Path string
+	// Unlocked holds when each unlocked achievement was earned by its ID.
+	Unlocked map[string]time.Time
+}
+
+// LoadAchievements reads the achievements unlocked so far from the file at
+// path. A missing file means none are unlocked.
+func LoadAchievements(path string) (*Achievements, error) {
+	achievements := &Achievements{Path: path, Unlocked: map[string]time.Time{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return achievements, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &achievements.Unlocked); err != nil {
+		return nil, fmt.Errorf("cannot read achievements %s: %w", path, err)
+	}
+	return achievements, nil
+}
+
+// Save writes the unlocked achievements to Path, creating its directory if
+// needed.
+func (achievements *Achievements) Save() error {
+	if err := os.MkdirAll(filepath.Dir(achievements.Path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(achievements.Unlocked, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(achievements.Path, data, 0644)
+}
+
+// CheckAchievements unlocks the achievements the game has reached by now and
+// saves them. The last one unlocked is shown for achievementToast ticks.
+func (game *Game) CheckAchievements() {
+	if game.Achievements == nil {
+		return
+	}
+	unlocked := false
+	for _, achievement := range AchievementList {
+		if _, ok := game.Achievements.Unlocked[achievement.ID]; ok || !achievement.Reached(game) {
+			continue
+		}
+		game.Achievements.Unlocked[achievement.ID] = game.Clock.Now()
+		game.earned = append(game.earned, achievement)
+		game.toastLeft = achievementToast
+		unlocked = true
+	}
+	if unlocked {
+		game.achievementErr = game.Achievements.Save()
+	}
+}
+
+// DrawAchievements draws the list of all achievements in place of the board,
+// the unlocked ones with the date they were earned.
+func (renderer *CanvasRenderer) DrawAchievements(game *Game) {
+	renderer.DrawBorder(game)
+	lines := []string{fmt.Sprintf("Achievements: %d/%d", len(game.Achievements.Unlocked), len(AchievementList))}
+	for _, achievement := range AchievementList {
+		status := "locked"
+		if date, ok := game.Achievements.Unlocked[achievement.ID]; ok {
+			status = date.Format(time.DateOnly)
+		}
+		lines = append(lines, fmt.Sprintf("%-20s %-10s %s", achievement.Name, status, achievement.Description))
+	}
+	lines = append(lines, "Back? t", "Play Again? y/n")
+	for index, line := range lines {
+		renderer.Canvas.DrawText(2, 1+index, line, blackWhiteStyle)
+	}
+}
+
+// ToggleAchievements switches between the game over screen and the list of
+// achievements once the game is over.
+func (game *Game) ToggleAchievements() {
+	if game.Achievements == nil {
+		return
+	}
+	game.achievementsShown = !game.achievementsShown
+	game.breakdownShown = false
+	game.heatmapShown = false
+	if !game.achievementsShown {
+		game.Renderer.DrawBoard(game)
+	}
+	game.Renderer.DrawGameOver(game, game.WonGame())
+	game.Renderer.Show()
+}
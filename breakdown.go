@@ -0,0 +1,92 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import "fmt"
+
+// BreakdownSummary returns the lines summing up the last game below the
+// score breakdown: the moves and time survived, the average efficiency of the
+// paths to the food, the highest combo and the personal best.
+func (game *Game) BreakdownSummary() []string {
+	lines := []string{fmt.Sprintf("Moves: %d Time: %s", game.Tick(), formatClock(game.survived))}
+	breakdown := game.Breakdown()
+	if len(breakdown) > 0 {
+		efficiency, combo := 0.0, 1
+		for _, food := range breakdown {
+			efficiency += float64(min(food.Distance, food.Steps)) / float64(max(food.Steps, 1))
+			combo = max(combo, food.Combo)
+		}
+		lines = append(lines, fmt.Sprintf("Efficiency: %.0f%%", 100*efficiency/float64(len(breakdown))))
+		if combo > 1 {
+			lines = append(lines, fmt.Sprintf("Max combo: x%d", combo))
+		}
+	}
+	switch {
+	case game.highScores == nil:
+	case game.personalBest < 0:
+		lines = append(lines, "First high score!")
+	case game.Score() > game.personalBest:
+		lines = append(lines, fmt.Sprintf("New best, %d above %d!", game.Score()-game.personalBest, game.personalBest))
+	default:
+		lines = append(lines, fmt.Sprintf("Best: %d, %d to go", game.personalBest, game.personalBest-game.Score()))
+	}
+	return lines
+}
+
+// DrawBreakdown draws the score breakdown of the last game in place of the
+// board, followed by the BreakdownSummary. Foods that do not fit into the
+// view are summarized in one row.
+func (renderer *CanvasRenderer) DrawBreakdown(game *Game) {
+	renderer.DrawBorder(game)
+	_, height := game.ViewSize()
+	breakdown := game.Breakdown()
+	summary := game.BreakdownSummary()
+	lines := []string{fmt.Sprintf("%-3s %5s %4s %4s", "#", "steps", "best", "pts")}
+	rows := height - 4 - len(summary)
+	for index, food := range breakdown {
+		if len(lines) == rows-1 && index < len(breakdown)-1 {
+			lines = append(lines, fmt.Sprintf("... %d more", len(breakdown)-index))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%-3d %5d %4d %4d", index+1, food.Steps, food.Distance, food.Points))
+	}
+	lines = append(lines, fmt.Sprintf("Score: %d", game.Score()))
+	lines = append(lines, summary...)
+	lines = append(lines, "Back? b", "Play Again? y/n")
+	for index, line := range lines {
+		renderer.Canvas.DrawText(2, 1+index, line, blackWhiteStyle)
+	}
+}
+
+// ToggleBreakdown switches between the game over screen and the score
+// breakdown once the game is over.
+func (game *Game) ToggleBreakdown() {
+	game.breakdownShown = !game.breakdownShown
+	game.achievementsShown = false
+	game.heatmapShown = false
+	if !game.breakdownShown {
+		game.Renderer.DrawBoard(game)
+	}
+	game.Renderer.DrawGameOver(game, game.WonGame())
+	game.Renderer.Show()
+}
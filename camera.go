@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// Camera is the visible window of a grid that does not fit onto the screen.
+type Camera struct {
+	X          int
+	Y          int
+	Width      int
+	Height     int
+	GridWidth  int
+	GridHeight int
+}
+
+// Follow centers the camera on head while keeping it within the grid.
+func (camera *Camera) Follow(head engine.Pos) {
+	camera.X = clampInt(head.X-camera.Width/2, 0, camera.GridWidth-camera.Width)
+	camera.Y = clampInt(head.Y-camera.Height/2, 0, camera.GridHeight-camera.Height)
+}
+
+// Visible reports whether the cell at x, y is shown. Cells of the border are
+// shown if the camera reaches the edge of the grid they are next to.
+func (camera *Camera) Visible(x, y int) bool {
+	visibleX := (x >= camera.X && x < camera.X+camera.Width) ||
+		(x == -1 && camera.X == 0) ||
+		(x == camera.GridWidth && camera.X+camera.Width == camera.GridWidth)
+	visibleY := (y >= camera.Y && y < camera.Y+camera.Height) ||
+		(y == -1 && camera.Y == 0) ||
+		(y == camera.GridHeight && camera.Y+camera.Height == camera.GridHeight)
+	return visibleX && visibleY
+}
+
+// CameraCanvas draws the cells visible through Camera, moved so that the
+// top-left cell of the camera is drawn at the top-left of the board.
+type CameraCanvas struct {
+	Canvas
+	Camera *Camera
+}
+
+func (canvas CameraCanvas) DrawCell(x, y int, kind CellKind, style tcell.Style) {
+	camera := canvas.Camera
+	if camera.Width < 1 || camera.Height < 1 {
+		canvas.Canvas.DrawCell(x, y, kind, style)
+		return
+	}
+	if !camera.Visible(x, y) {
+		return
+	}
+	canvas.Canvas.DrawCell(x-camera.X, y-camera.Y, kind, style)
+}
+
+func (canvas CameraCanvas) DrawRunes(x, y int, left, right rune, style tcell.Style) {
+	camera := canvas.Camera
+	if camera.Width < 1 || camera.Height < 1 {
+		canvas.Canvas.DrawRunes(x, y, left, right, style)
+		return
+	}
+	if !camera.Visible(x, y) {
+		return
+	}
+	canvas.Canvas.DrawRunes(x-camera.X, y-camera.Y, left, right, style)
+}
+
+// UpdateCamera sizes the camera to the part of the grid that fits onto the
+// screen and centers it on the head. If the whole grid fits the camera is
+// turned off.
+func (game *Game) UpdateCamera() {
+	if game.Screen == nil {
+		return
+	}
+	screenWidth, screenHeight := game.Screen.Size()
+	width := minInt(game.XDim, (screenWidth-2*game.Padding-3)/2)
+	height := minInt(game.YDim, screenHeight-2*game.Padding-2)
+	if width >= game.XDim && height >= game.YDim || width < 1 || height < 1 {
+		game.Camera = Camera{}
+		return
+	}
+	game.Camera = Camera{Width: width, Height: height, GridWidth: game.XDim, GridHeight: game.YDim}
+	game.Camera.Follow(game.Head())
+}
+
+// ViewSize returns the number of columns and rows of the grid that are shown.
+func (game *Game) ViewSize() (int, int) {
+	if game.Camera.Width > 0 && game.Camera.Height > 0 {
+		return game.Camera.Width, game.Camera.Height
+	}
+	return game.XDim, game.YDim
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func clampInt(x, low, high int) int {
+	if x < low {
+		return low
+	}
+	if x > high {
+		return high
+	}
+	return x
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/q713/snail/engine"
+)
+
+func TestCameraFollow(t *testing.T) {
+	tests := []struct {
+		name string
+		head engine.Pos
+		x, y int
+	}{
+		{"centered", engine.Pos{X: 20, Y: 15}, 15, 11},
+		{"top-left", engine.Pos{X: 2, Y: 1}, 0, 0},
+		{"bottom-right", engine.Pos{X: 39, Y: 29}, 30, 22},
+		{"left edge", engine.Pos{X: 4, Y: 15}, 0, 11},
+		{"right edge", engine.Pos{X: 36, Y: 15}, 30, 11},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			camera := Camera{Width: 10, Height: 8, GridWidth: 40, GridHeight: 30}
+			camera.Follow(test.head)
+			if camera.X != test.x || camera.Y != test.y {
+				t.Errorf("camera at %d,%d, want %d,%d", camera.X, camera.Y, test.x, test.y)
+			}
+			if !camera.Visible(test.head.X, test.head.Y) {
+				t.Errorf("head %v is not visible", test.head)
+			}
+		})
+	}
+}
+
+func TestCameraCanvas(t *testing.T) {
+	camera := &Camera{X: 5, Y: 3, Width: 4, Height: 2, GridWidth: 9, GridHeight: 5}
+	recording := &recordingCanvas{}
+	canvas := CameraCanvas{Canvas: recording, Camera: camera}
+	for _, pos := range []engine.Pos{{X: 4, Y: 3}, {X: 5, Y: 3}, {X: 8, Y: 4}, {X: 9, Y: 4}, {X: 6, Y: 5}, {X: 6, Y: 2}} {
+		canvas.DrawCell(pos.X, pos.Y, CellFood, tcell.StyleDefault)
+	}
+	// the right and bottom border are shown because the camera reaches them
+	want := []string{"cell 0,0 **", "cell 3,1 **", "cell 4,1 **", "cell 1,2 **"}
+	if len(recording.calls) != len(want) {
+		t.Fatalf("draw calls %q, want %q", recording.calls, want)
+	}
+	for i := range want {
+		if recording.calls[i] != want[i] {
+			t.Errorf("draw call %d is %q, want %q", i, recording.calls[i], want[i])
+		}
+	}
+}
+
+func TestHarnessCamera(t *testing.T) {
+	// 38 of the 60 columns fit onto the screen of 80 columns
+	h := newHarness(t, WithSize(60, 10))
+	for i := 0; i < 15; i++ {
+		var head engine.Pos
+		var camera Camera
+		h.do(func(game *Game) {
+			head = game.Head()
+			camera = game.Camera
+		})
+		wantX := clampInt(head.X-19, 0, 60-38)
+		if camera.Width != 38 || camera.Height != 10 || camera.X != wantX || camera.Y != 0 {
+			t.Fatalf("head %v: camera %+v, want 38x10 at %d,0", head, camera, wantX)
+		}
+		if got := h.cell(head.X-camera.X, head.Y); got != "@@" {
+			t.Fatalf("head %v: %q drawn at column %d of the window, want @@", head, got, head.X-camera.X)
+		}
+		h.tick()
+	}
+}
@@ -0,0 +1,252 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/q713/snail/engine"
+)
+
+// CampaignLevel is a built-in level of the campaign. It is completed by
+// eating Foods food.
+type CampaignLevel struct {
+	Name  string
+	Map   string
+	Foods int
+	Delay time.Duration
+}
+
+// CampaignLevels are the levels of the campaign in the order they are
+// played, every level is a bit harder than the one before.
+var CampaignLevels = []CampaignLevel{
+	{Name: "Open Field", Foods: 5, Delay: 150 * time.Millisecond, Map: `
+....................
+....................
+....................
+....................
+....................
+.........S..........
+....................
+....................
+....................
+....................
+....................
+....................`},
+	{Name: "Box", Foods: 8, Delay: 140 * time.Millisecond, Map: `
+####################
+#..................#
+#..................#
+#..................#
+#..................#
+#........S.........#
+#..................#
+#..................#
+#..................#
+#..................#
+#..................#
+####################`},
+	{Name: "Pillars", Foods: 10, Delay: 130 * time.Millisecond, Map: `
+####################
+#..................#
+#..##..........##..#
+#..##..........##..#
+#..................#
+#........S.........#
+#..................#
+#..................#
+#..##..........##..#
+#..##..........##..#
+#..................#
+####################`},
+	{Name: "Portals", Foods: 12, Delay: 120 * time.Millisecond, Map: `
+####################
+#.........#........#
+#.1.......#.....2..#
+#.........#........#
+#.........#........#
+#....S....#........#
+#.........#........#
+#.........#........#
+#..2......#......1.#
+#.........#........#
+#.........#........#
+####################`},
+	{Name: "Maze", Foods: 15, Delay: 110 * time.Millisecond, Map: `
+####################
+#........#.........#
+#.######.#.#######.#
+#.#......#.......#.#
+#.#.#########.##.#.#
+#.....S............#
+#.#.##.######.##.#.#
+#.#....#.........#.#
+#.####.#.#######.#.#
+#......#...........#
+#..................#
+####################`},
+}
+
+// Campaign plays the CampaignLevels in order. A level is repeated until it
+// is completed, the progress is kept in a json file so a campaign can be
+// continued later.
+type Campaign struct {
+	Path   string
+	levels []*engine.Level
+	// Level is the index of the level played next
+	Level int
+	// Scores are the scores of the completed levels
+	Scores []int
+}
+
+// campaignProgress is the content of the progress file.
+type campaignProgress struct {
+	Level  int
+	Scores []int
+}
+
+// LoadCampaign parses the CampaignLevels and continues the campaign stored at
+// path. A missing file starts a new campaign.
+func LoadCampaign(path string) (*Campaign, error) {
+	campaign := &Campaign{Path: path}
+	for _, level := range CampaignLevels {
+		parsed, err := engine.ParseLevel(strings.NewReader(strings.TrimPrefix(level.Map, "\n")))
+		if err != nil {
+			return nil, fmt.Errorf("level %s: %w", level.Name, err)
+		}
+		campaign.levels = append(campaign.levels, parsed)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return campaign, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var progress campaignProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if progress.Level < 0 || progress.Level > len(CampaignLevels) || len(progress.Scores) != progress.Level {
+		return nil, fmt.Errorf("%s: invalid campaign progress", path)
+	}
+	campaign.Level = progress.Level
+	campaign.Scores = progress.Scores
+	return campaign, nil
+}
+
+// Current returns the level played next.
+func (campaign *Campaign) Current() CampaignLevel {
+	return CampaignLevels[campaign.Level]
+}
+
+// Complete reports whether every level was completed.
+func (campaign *Campaign) Complete() bool {
+	return campaign.Level >= len(CampaignLevels)
+}
+
+// Total returns the sum of the scores of the completed levels.
+func (campaign *Campaign) Total() int {
+	total := 0
+	for _, score := range campaign.Scores {
+		total += score
+	}
+	return total
+}
+
+// Setup configures game for the level played next. A complete campaign
+// starts over.
+func (campaign *Campaign) Setup(game *Game) {
+	if campaign.Complete() {
+		campaign.Level = 0
+		campaign.Scores = nil
+	}
+	level := campaign.Current()
+	game.Apply(WithLevel(campaign.levels[campaign.Level]), WithDelay(level.Delay))
+	game.config.ObjectiveFoods = level.Foods
+	if game.Screen != nil {
+		game.UpdateDimensions(game.config.XDim, game.config.YDim)
+	}
+}
+
+// Finish records the outcome of the game on the current level. A won level
+// is completed and the progress is saved.
+func (campaign *Campaign) Finish(game *Game, won bool) error {
+	if !won {
+		return nil
+	}
+	campaign.Scores = append(campaign.Scores, game.Score())
+	campaign.Level += 1
+	data, err := json.MarshalIndent(campaignProgress{Level: campaign.Level, Scores: campaign.Scores}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(campaign.Path, data, 0644)
+}
+
+// DrawCampaign draws the screen between two levels of the campaign: the
+// retry screen of a failed level, the transition to the next level or the
+// summary of a complete campaign.
+func (renderer *CanvasRenderer) DrawCampaign(game *Game, won bool) {
+	campaign := game.Campaign
+	var texts []string
+	switch {
+	case !won:
+		level := campaign.Current()
+		texts = []string{
+			fmt.Sprintf("Level %d failed: %s", campaign.Level+1, level.Name),
+			fmt.Sprintf("You ate %d of %d food.", game.FoodsEaten(), level.Foods),
+			"Retry? y/n",
+		}
+	case !campaign.Complete():
+		completed := CampaignLevels[campaign.Level-1]
+		next := campaign.Current()
+		texts = []string{
+			fmt.Sprintf("Level %d complete: %s", campaign.Level, completed.Name),
+			fmt.Sprintf("You reached a score of %d points.", game.Score()),
+			fmt.Sprintf("Next: Level %d, %s", campaign.Level+1, next.Name),
+			"Continue? y/n",
+		}
+	default:
+		texts = []string{"Campaign complete!"}
+		for index, level := range CampaignLevels {
+			texts = append(texts, fmt.Sprintf("%d. %-12s %5d", index+1, level.Name, campaign.Scores[index]))
+		}
+		texts = append(texts, fmt.Sprintf("   %-12s %5d", "Total", campaign.Total()), "Play again? y/n")
+	}
+	width, height := game.ViewSize()
+	top := height/2 + 1 - len(texts)/2
+	for index, text := range texts {
+		col := width - len(text)/2 + 1
+		renderer.Canvas.DrawText(col, top+index, text, blackWhiteStyle)
+	}
+	if game.campaignErr != nil {
+		text := "Campaign progress not saved!"
+		renderer.Canvas.DrawText(width-len(text)/2+1, top+len(texts), text, blackWhiteStyle)
+	}
+}
@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"hash/fnv"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// dailyObstacles is the number of obstacles of the daily challenge if no
+// obstacles are set.
+const dailyObstacles = 10
+
+// dailyDimensions and dailyDelay are the size of the board and the delay of
+// every daily challenge, so the results of a day compare.
+const (
+	dailyDimensions = DefaultDimensions
+	dailyDelay      = DefaultDelay
+)
+
+// defaultDailyLedger is the name of the ledger in the data directory the
+// results of the daily challenge are appended to if no ledger is set, see
+// DataPath and ledgerPath.
+const defaultDailyLedger = "snail.csv"
+
+// DailySeed returns the seed of the daily challenge of the day of date in
+// UTC, so everyone plays the same board on the same day.
+func DailySeed(date time.Time) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("snail daily " + date.UTC().Format(time.DateOnly)))
+	return int64(hash.Sum64() >> 1)
+}
+
+// ledgerPath returns the path of the ledger of the game. Daily challenges and
+// mirrored games are tracked apart in a ledger with the mode added to its
+// name, as in ledger-daily.csv or ledger-lr.csv.
+func (game *Game) ledgerPath() string {
+	ext := filepath.Ext(game.LedgerPath)
+	path := strings.TrimSuffix(game.LedgerPath, ext)
+	if game.Daily {
+		path += "-daily"
+	}
+	if game.Mirror != MirrorOff {
+		path += "-" + game.Mirror.String()
+	}
+	return path + ext
+}
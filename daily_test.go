@@ -0,0 +1,64 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailySeed(t *testing.T) {
+	morning := time.Date(2023, 5, 1, 0, 30, 0, 0, time.UTC)
+	evening := time.Date(2023, 5, 1, 23, 30, 0, 0, time.UTC)
+	if DailySeed(morning) != DailySeed(evening) {
+		t.Error("the seed changes within a day")
+	}
+	if DailySeed(morning) == DailySeed(morning.AddDate(0, 0, 1)) {
+		t.Error("two days have the same seed")
+	}
+	if DailySeed(morning) < 0 {
+		t.Error("the seed is negative")
+	}
+}
+
+func TestLedgerPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		daily  bool
+		mirror Mirror
+		want   string
+	}{
+		{"scores.csv", false, MirrorOff, "scores.csv"},
+		{"scores.csv", true, MirrorOff, "scores-daily.csv"},
+		{"scores.csv", false, MirrorHorizontal, "scores-lr.csv"},
+		{"dir/scores.csv", true, MirrorBoth, "dir/scores-daily-all.csv"},
+		{"scores", true, MirrorOff, "scores-daily"},
+	}
+	for _, test := range tests {
+		game := NewGame(WithOutputs(Outputs{LedgerPath: test.path}), WithControls(Controls{Mirror: test.mirror}))
+		game.Daily = test.daily
+		if got := game.ledgerPath(); got != test.want {
+			t.Errorf("ledgerPath(%q, daily %v, mirror %s) = %q, want %q", test.path, test.daily, test.mirror, got, test.want)
+		}
+	}
+}
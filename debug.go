@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// defaultDebugPath is the file -debug without a value writes to. The log
+// never goes to the terminal, that would corrupt the screen.
+const defaultDebugPath = "snail-debug.log"
+
+// DebugFlag is the value of -debug. It is a boolean flag that optionally
+// takes the path of the log file, as in -debug or -debug=ticks.log.
+type DebugFlag struct {
+	Path string
+}
+
+func (debug *DebugFlag) String() string {
+	if debug == nil {
+		return ""
+	}
+	return debug.Path
+}
+
+func (debug *DebugFlag) Set(value string) error {
+	switch value {
+	case "true":
+		debug.Path = defaultDebugPath
+	case "false":
+		debug.Path = ""
+	default:
+		debug.Path = value
+	}
+	return nil
+}
+
+func (debug *DebugFlag) IsBoolFlag() bool {
+	return true
+}
+
+// OpenDebugLogger returns a logger that writes structured debug logs as json
+// lines to the file at path, which is truncated.
+func OpenDebugLogger(path string) (*slog.Logger, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	handler := slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return slog.New(handler), nil
+}
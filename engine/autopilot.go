@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// Autopilot steers the snail along the shortest safe path to the food. With
+// an ErrorRate above zero it now and then picks a safe but suboptimal move,
+// which makes for more natural and longer lasting play in watch mode.
+type Autopilot struct {
+	// ErrorRate is the probability in [0, 1] of picking a suboptimal move.
+	ErrorRate float64
+}
+
+// NextDirection returns the direction the snail should move in next. Moves
+// that run into the body or off the board are never picked if a safe one
+// exists.
+func (pilot *Autopilot) NextDirection(game *Game) Velocity {
+	best := game.snail.Direction
+	bestDistance := -1
+	var safe []Velocity
+	var distances []int
+	for _, dir := range Directions {
+		if !game.IsValidNewDir(dir) {
+			continue
+		}
+		next, ok := game.SafeNextPos(dir)
+		if !ok {
+			continue
+		}
+		distance := game.PathLength(next, game.food)
+		if distance < 0 {
+			// food is unreachable from here, but the move is still safe
+			distance = game.XDim * game.YDim
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best = dir
+			bestDistance = distance
+		}
+		safe = append(safe, dir)
+		distances = append(distances, distance)
+	}
+	// moves as good as the best one are no mistake
+	var others []Velocity
+	for index, dir := range safe {
+		if distances[index] > bestDistance {
+			others = append(others, dir)
+		}
+	}
+	if len(others) > 0 && pilot.ErrorRate > 0 && game.rng.Float64() < pilot.ErrorRate {
+		return others[game.rng.Intn(len(others))]
+	}
+	return best
+}
+
+// SafeNextPos returns the cell the head moves to in direction dir and whether
+// that move neither leaves the board nor runs into the body, a rival or an
+// obstacle.
+func (game *Game) SafeNextPos(dir Velocity) (Pos, bool) {
+	next, wrapped := game.NextPos(game.snail.GetHead(), dir)
+	if wrapped && game.Bounds != BoundsWrap {
+		return next, false
+	}
+	body := game.snail.Body
+	if !game.Growing() {
+		// the tail moves out of the way
+		body = body[1:]
+	}
+	for _, rival := range game.rivals {
+		if game.CheckCollisions(next, rival.Snail.Body) {
+			return next, false
+		}
+	}
+	return next, !game.CheckCollisions(next, body) && !game.CheckCollisions(next, game.obstacles)
+}
+
+// PathLength returns the number of moves on the shortest path from start to
+// target that does not cross the body or an obstacle, or -1 if there is no
+// such path.
+func (game *Game) PathLength(start, target Pos) int {
+	type step struct {
+		pos      Pos
+		distance int
+	}
+	visited := make([]bool, game.XDim*game.YDim*game.layers())
+	// the body and obstacles are never entered, so they are treated as
+	// already visited
+	for _, pos := range game.snail.Body {
+		visited[game.cellIndex(pos)] = true
+	}
+	for _, pos := range game.obstacles {
+		visited[game.cellIndex(pos)] = true
+	}
+	visited[game.cellIndex(start)] = true
+	queue := []step{{pos: start}}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur.pos == target {
+			return cur.distance
+		}
+		for _, next := range game.Adjacent(cur.pos) {
+			if visited[game.cellIndex(next)] {
+				continue
+			}
+			visited[game.cellIndex(next)] = true
+			queue = append(queue, step{pos: next, distance: cur.distance + 1})
+		}
+	}
+	return -1
+}
+
+// Adjacent returns the cells reachable from p with a single move. Cells
+// beyond the edges are only included when the snail wraps around them.
+func (game *Game) Adjacent(p Pos) []Pos {
+	if game.layers() > 1 {
+		// only NextPos knows about the ladders to the other layers
+		adjacent := make([]Pos, 0, len(Directions))
+		for _, dir := range Directions {
+			if next, wrapped := game.NextPos(p, dir); !wrapped || game.Bounds == BoundsWrap {
+				adjacent = append(adjacent, next)
+			}
+		}
+		return adjacent
+	}
+	if game.Bounds == BoundsWrap {
+		return Neighbors(p, game.XDim, game.YDim)
+	}
+	adjacent := make([]Pos, 0, len(Directions))
+	for _, dir := range Directions {
+		next := Pos{X: p.X + dir.X, Y: p.Y + dir.Y, Layer: p.Layer}
+		if next.X >= 0 && next.X < game.XDim && next.Y >= 0 && next.Y < game.YDim {
+			adjacent = append(adjacent, next)
+		}
+	}
+	return adjacent
+}
@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "testing"
+
+func TestAutopilotNextDirection(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      []Pos
+		dir       Velocity
+		food      Pos
+		obstacles []Pos
+		errorRate float64
+		// want are the directions that may be picked
+		want []Velocity
+	}{
+		{"shortest", []Pos{{X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}}, EastDir, Pos{X: 2, Y: 2}, nil, 0, []Velocity{NorthDir}},
+		{"straight", []Pos{{X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}}, EastDir, Pos{X: 6, Y: 5}, nil, 0, []Velocity{EastDir}},
+		{"around an obstacle", []Pos{{X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}}, EastDir, Pos{X: 4, Y: 5},
+			[]Pos{{X: 3, Y: 5}}, 0, []Velocity{NorthDir, SouthDir}},
+		{"mistake", []Pos{{X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}}, EastDir, Pos{X: 2, Y: 2}, nil, 1, []Velocity{EastDir, SouthDir}},
+		{"no mistake between equal moves", []Pos{{X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}}, EastDir, Pos{X: 4, Y: 5},
+			[]Pos{{X: 3, Y: 5}}, 1, []Velocity{NorthDir}},
+		{"mistake only when safe", []Pos{{X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}}, EastDir, Pos{X: 2, Y: 2},
+			[]Pos{{X: 3, Y: 5}, {X: 2, Y: 6}}, 1, []Velocity{NorthDir}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				game, err := New(Config{XDim: 10, YDim: 10, Seed: seed})
+				if err != nil {
+					t.Fatal(err)
+				}
+				game.snail = Snail{Body: test.body, Direction: test.dir}
+				game.food = test.food
+				game.obstacles = test.obstacles
+				pilot := Autopilot{ErrorRate: test.errorRate}
+				got := pilot.NextDirection(game)
+				allowed := false
+				for _, dir := range test.want {
+					allowed = allowed || got.Equals(dir)
+				}
+				if !allowed {
+					t.Fatalf("seed %d: picked %v, want one of %v", seed, got, test.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package engine implements the rules of snail without drawing anything, so
+// the game can be played by other frontends and driven from tests.
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoFreeCell is returned when there are not enough free cells left to
+	// place food or obstacles on.
+	ErrNoFreeCell = errors.New("not enough free cells left")
+	// ErrNoSteps is returned when a score is calculated before any step was made.
+	ErrNoSteps = errors.New("cannot calculate score when no steps were made")
+)
+
+type Velocity struct {
+	X int
+	Y int
+}
+
+func (me *Velocity) Equals(other Velocity) bool {
+	return me.X == other.X && me.Y == other.Y
+}
+
+var NorthDir = Velocity{X: 0, Y: -1}
+var SouthDir = Velocity{X: 0, Y: 1}
+var EastDir = Velocity{X: 1, Y: 0}
+var WestDir = Velocity{X: -1, Y: 0}
+
+// Bounds decides what happens when the snail reaches an edge of the grid.
+type Bounds int
+
+const (
+	// BoundsWrap lets the snail leave the grid and enter it on the opposite edge.
+	BoundsWrap Bounds = iota
+	// BoundsBounce turns the snail along the wall instead of leaving the grid.
+	BoundsBounce
+	// BoundsWalls ends the game when the snail runs into an edge.
+	BoundsWalls
+)
+
+var boundsNames = map[string]Bounds{
+	"wrap":   BoundsWrap,
+	"bounce": BoundsBounce,
+	"walls":  BoundsWalls,
+}
+
+// ParseBounds returns the Bounds for the given name.
+func ParseBounds(name string) (Bounds, error) {
+	if bounds, ok := boundsNames[name]; ok {
+		return bounds, nil
+	}
+	return BoundsWrap, fmt.Errorf("unknown bounds mode %q", name)
+}
+
+func (bounds Bounds) String() string {
+	for name, value := range boundsNames {
+		if value == bounds {
+			return name
+		}
+	}
+	return fmt.Sprintf("Bounds(%d)", int(bounds))
+}
+
+// EatRule decides when the head of the snail reaches the food.
+type EatRule int
+
+const (
+	// EatExact eats the food when the head moves onto its cell.
+	EatExact EatRule = iota
+	// EatTouch eats the food as soon as the head is on or next to its cell,
+	// which matches what a cell that is drawn two columns wide looks like.
+	EatTouch
+)
+
+var eatRuleNames = map[string]EatRule{
+	"exact": EatExact,
+	"touch": EatTouch,
+}
+
+// ParseEatRule returns the EatRule for the given name.
+func ParseEatRule(name string) (EatRule, error) {
+	if rule, ok := eatRuleNames[name]; ok {
+		return rule, nil
+	}
+	return EatExact, fmt.Errorf("unknown eat rule %q", name)
+}
+
+// GrowthRule decides when the snail grows.
+type GrowthRule int
+
+const (
+	// GrowOnEat grows the snail by Growth segments per eaten food.
+	GrowOnEat GrowthRule = iota
+	// GrowTrail never moves the tail, so every visited cell stays part of
+	// the snail and becomes a wall for the rest of the game.
+	GrowTrail
+)
+
+var growthRuleNames = map[string]GrowthRule{
+	"eat":   GrowOnEat,
+	"trail": GrowTrail,
+}
+
+// ParseGrowthRule returns the GrowthRule for the given name.
+func ParseGrowthRule(name string) (GrowthRule, error) {
+	if rule, ok := growthRuleNames[name]; ok {
+		return rule, nil
+	}
+	return GrowOnEat, fmt.Errorf("unknown growth rule %q", name)
+}
+
+// ScoreRule decides what the snail scores points for.
+type ScoreRule int
+
+const (
+	// ScoreFood awards points per eaten food, more the faster it is reached.
+	ScoreFood ScoreRule = iota
+	// ScoreSurvival awards a point per tick survived, food is worth nothing.
+	ScoreSurvival
+)
+
+var scoreRuleNames = map[string]ScoreRule{
+	"food":     ScoreFood,
+	"survival": ScoreSurvival,
+}
+
+// ParseScoreRule returns the ScoreRule for the given name.
+func ParseScoreRule(name string) (ScoreRule, error) {
+	if rule, ok := scoreRuleNames[name]; ok {
+		return rule, nil
+	}
+	return ScoreFood, fmt.Errorf("unknown score rule %q", name)
+}
+
+type Pos struct {
+	X int
+	Y int
+	// Layer is the layer of a grid with stacked layers the cell is on.
+	Layer int `json:",omitempty"`
+}
+
+// Directions lists the four movement directions in the order used by Neighbors.
+var Directions = [4]Velocity{NorthDir, EastDir, SouthDir, WestDir}
+
+// Neighbor returns the cell next to p in direction dir on a w x h grid,
+// wrapping around its edges.
+func Neighbor(p Pos, dir Velocity, w, h int) Pos {
+	return Pos{
+		X:     ((p.X+dir.X)%w + w) % w,
+		Y:     ((p.Y+dir.Y)%h + h) % h,
+		Layer: p.Layer,
+	}
+}
+
+// Neighbors returns the four wrap-aware cells adjacent to p on a w x h grid,
+// in the order of Directions.
+func Neighbors(p Pos, w, h int) []Pos {
+	neighbors := make([]Pos, 0, len(Directions))
+	for _, dir := range Directions {
+		neighbors = append(neighbors, Neighbor(p, dir, w, h))
+	}
+	return neighbors
+}
+
+// ValidateBody checks that every segment of body lies on a w x h grid and is
+// adjacent to the segment before it.
+func ValidateBody(body []Pos, w, h int) error {
+	game := Game{Config: Config{XDim: w, YDim: h}}
+	return game.ValidateBody(body)
+}
+
+// ValidateBody is like the function ValidateBody on the grid of the game,
+// segments may also follow each other through a portal.
+func (game *Game) ValidateBody(body []Pos) error {
+	for index, pos := range body {
+		if pos.X < 0 || pos.X >= game.XDim || pos.Y < 0 || pos.Y >= game.YDim || pos.Layer < 0 || pos.Layer >= game.layers() {
+			return fmt.Errorf("segment %d at %v is outside of the grid", index, pos)
+		}
+		if index == 0 {
+			continue
+		}
+		adjacent := false
+		for _, dir := range Directions {
+			if next, _ := game.NextPos(body[index-1], dir); next == pos {
+				adjacent = true
+				break
+			}
+		}
+		if !adjacent {
+			return fmt.Errorf("segment %d at %v is not adjacent to %v", index, pos, body[index-1])
+		}
+	}
+	return nil
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNeighbors(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  Pos
+		// want is ordered north, east, south, west
+		want []Pos
+	}{
+		{"center", Pos{X: 2, Y: 2}, []Pos{{X: 2, Y: 1}, {X: 3, Y: 2}, {X: 2, Y: 3}, {X: 1, Y: 2}}},
+		{"top left", Pos{X: 0, Y: 0}, []Pos{{X: 0, Y: 3}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 4, Y: 0}}},
+		{"top right", Pos{X: 4, Y: 0}, []Pos{{X: 4, Y: 3}, {X: 0, Y: 0}, {X: 4, Y: 1}, {X: 3, Y: 0}}},
+		{"bottom left", Pos{X: 0, Y: 3}, []Pos{{X: 0, Y: 2}, {X: 1, Y: 3}, {X: 0, Y: 0}, {X: 4, Y: 3}}},
+		{"bottom right", Pos{X: 4, Y: 3}, []Pos{{X: 4, Y: 2}, {X: 0, Y: 3}, {X: 4, Y: 0}, {X: 3, Y: 3}}},
+		{"bottom edge", Pos{X: 2, Y: 3}, []Pos{{X: 2, Y: 2}, {X: 3, Y: 3}, {X: 2, Y: 0}, {X: 1, Y: 3}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := Neighbors(test.pos, 5, 4)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Neighbors(%v) = %v, want %v", test.pos, got, test.want)
+			}
+			seen := map[Pos]bool{}
+			for _, pos := range got {
+				seen[pos] = true
+			}
+			if len(seen) != 4 {
+				t.Errorf("Neighbors(%v) = %v, want four distinct cells", test.pos, got)
+			}
+		})
+	}
+}
+
+func TestValidateBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  []Pos
+		valid bool
+	}{
+		{"straight", []Pos{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}, true},
+		{"corner", []Pos{{X: 1, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}}, true},
+		{"wrapped", []Pos{{X: 3, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 0}}, true},
+		{"wrapped vertically", []Pos{{X: 2, Y: 3}, {X: 2, Y: 0}}, true},
+		{"gap", []Pos{{X: 1, Y: 1}, {X: 3, Y: 1}}, false},
+		{"diagonal", []Pos{{X: 1, Y: 1}, {X: 2, Y: 2}}, false},
+		{"outside", []Pos{{X: 4, Y: 0}, {X: 5, Y: 0}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateBody(test.body, 5, 4)
+			if valid := err == nil; valid != test.valid {
+				t.Errorf("ValidateBody(%v) = %v, want valid %v", test.body, err, test.valid)
+			}
+		})
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	// body covers all cells of a 5x2 grid but the one at 0, 1
+	body := []Pos{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 1}, {X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}}
+	tests := []struct {
+		name string
+		run  func(game *Game) error
+		want error
+	}{
+		{"no steps", func(game *Game) error {
+			_, err := game.scorer.CalculateScore(3)
+			return err
+		}, ErrNoSteps},
+		{"no free cell", func(game *Game) error {
+			game.snail.Body = append(append([]Pos(nil), body...), Pos{X: 0, Y: 1})
+			return game.CreateFood()
+		}, ErrNoFreeCell},
+		{"not enough free cells", func(game *Game) error {
+			game.snail.Body = body
+			_, err := game.Allocate(2, nil)
+			return err
+		}, ErrNoFreeCell},
+		{"one free cell", func(game *Game) error {
+			game.snail.Body = body
+			return game.CreateFood()
+		}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 5, YDim: 2, Seed: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			game.scorer.ResetSteps()
+			err = test.run(game)
+			if test.want == nil && err != nil || test.want != nil && !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestBoardFullWins(t *testing.T) {
+	game, err := New(Config{XDim: 5, YDim: 2, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the head eats the food on the last free cell
+	game.snail = Snail{Body: []Pos{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0},
+		{X: 4, Y: 1}, {X: 3, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}}, Direction: WestDir}
+	game.food = Pos{X: 0, Y: 1}
+	game.scorer.Step()
+	running, err := game.Step()
+	if err != nil {
+		t.Fatalf("Step = %v, want a won game", err)
+	}
+	if running {
+		t.Fatal("the game goes on on a full board")
+	}
+	if !game.EndGame() || game.Outcome() != "won" {
+		t.Errorf("outcome %q, want won", game.Outcome())
+	}
+}
@@ -0,0 +1,191 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// EventKind is a random event that interrupts the game now and then, see
+// EventInterval.
+type EventKind int
+
+const (
+	// EventReshuffle moves the food to another cell.
+	EventReshuffle EventKind = iota
+	// EventSurge speeds the game up for a while.
+	EventSurge
+	// EventWall raises a short wall for a while.
+	EventWall
+	// EventInvert inverts the direction keys for a while.
+	EventInvert
+)
+
+// Events are the kinds of events picked from at random.
+var Events = []EventKind{EventReshuffle, EventSurge, EventWall, EventInvert}
+
+var eventNames = map[EventKind]string{
+	EventReshuffle: "Reshuffle",
+	EventSurge:     "Surge",
+	EventWall:      "Wall",
+	EventInvert:    "Inverted",
+}
+
+func (kind EventKind) String() string {
+	return eventNames[kind]
+}
+
+// eventTicks are the number of ticks an event lasts. A reshuffle happens at
+// once, its ticks are only how long it is announced.
+var eventTicks = map[EventKind]int{
+	EventReshuffle: 5,
+	EventSurge:     20,
+	EventWall:      30,
+	EventInvert:    15,
+}
+
+// eventWallLength is the number of cells of the wall of an EventWall.
+const eventWallLength = 4
+
+// eventWallMargin is the number of moves between the head and the closest
+// cell of the wall of an EventWall when it is raised.
+const eventWallMargin = 4
+
+// Event is the random event going on in a game.
+type Event struct {
+	Kind      EventKind
+	TicksLeft int
+	// Walls are the cells of the wall of an EventWall.
+	Walls []Pos `json:",omitempty"`
+}
+
+// RunEvents ends the current event once its time ran out and starts a random
+// new one when it is due. Events are due every EventInterval ticks on
+// average, but never overlap.
+func (game *Game) RunEvents() error {
+	if game.EventInterval < 1 {
+		return nil
+	}
+	if game.event != nil {
+		game.event.TicksLeft -= 1
+		if game.event.TicksLeft > 0 {
+			return nil
+		}
+		game.endEvent()
+		game.scheduleEvent()
+		return nil
+	}
+	if game.nextEvent == 0 {
+		game.scheduleEvent()
+	}
+	if game.tick < game.nextEvent {
+		return nil
+	}
+	return game.startEvent(Events[game.rng.Intn(len(Events))])
+}
+
+// scheduleEvent picks the tick of the next event, between half and one and a
+// half EventInterval ticks from now.
+func (game *Game) scheduleEvent() {
+	game.nextEvent = game.tick + game.EventInterval/2 + game.rng.Intn(game.EventInterval) + 1
+}
+
+// startEvent starts an event of the given kind.
+func (game *Game) startEvent(kind EventKind) error {
+	event := &Event{Kind: kind, TicksLeft: eventTicks[kind]}
+	switch kind {
+	case EventReshuffle:
+		moved, err := game.RelocateFood()
+		if err != nil {
+			return err
+		}
+		if moved {
+			// the food is scored from where it was moved to
+			game.scorer.ResetSteps()
+			game.scorer.OldHeadPos = game.snail.GetHead()
+			game.scorer.OldFoodPos = game.food
+		}
+	case EventWall:
+		event.Walls = game.raiseWall()
+		game.obstacles = append(game.obstacles, event.Walls...)
+	}
+	game.event = event
+	game.Debug("event started", "event", kind.String(), "ticks", event.TicksLeft)
+	return nil
+}
+
+// raiseWall returns up to eventWallLength free cells in a straight line at
+// least eventWallMargin moves away from the head, or none if there is no
+// room for a wall.
+func (game *Game) raiseWall() []Pos {
+	taken := map[Pos]bool{game.food: true}
+	for _, pos := range game.Occupied() {
+		taken[pos] = true
+	}
+	allowed := func(pos Pos) bool {
+		return !taken[pos] && game.Distance(game.snail.GetHead(), pos) >= eventWallMargin
+	}
+	cells, err := game.Allocate(1, allowed)
+	if err != nil {
+		return nil
+	}
+	dir := Directions[game.rng.Intn(len(Directions))]
+	walls := cells
+	for len(walls) < eventWallLength {
+		last := walls[len(walls)-1]
+		next := Pos{X: last.X + dir.X, Y: last.Y + dir.Y, Layer: last.Layer}
+		if next.X < 0 || next.X >= game.XDim || next.Y < 0 || next.Y >= game.YDim || !allowed(next) {
+			break
+		}
+		walls = append(walls, next)
+	}
+	return walls
+}
+
+// endEvent undoes what the current event changed on the board.
+func (game *Game) endEvent() {
+	if len(game.event.Walls) > 0 {
+		walls := make(map[Pos]bool, len(game.event.Walls))
+		for _, pos := range game.event.Walls {
+			walls[pos] = true
+		}
+		kept := game.obstacles[:0]
+		for _, pos := range game.obstacles {
+			if !walls[pos] {
+				kept = append(kept, pos)
+			}
+		}
+		game.obstacles = kept
+	}
+	game.Debug("event ended", "event", game.event.Kind.String())
+	game.event = nil
+}
+
+// Event returns the event going on, if any.
+func (game *Game) Event() (Event, bool) {
+	if game.event == nil {
+		return Event{}, false
+	}
+	return *game.event, true
+}
+
+// EventActive reports whether an event of the given kind is going on.
+func (game *Game) EventActive(kind EventKind) bool {
+	return game.event != nil && game.event.Kind == kind
+}
@@ -0,0 +1,112 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "errors"
+
+// CreateFood places the food on the next cell of the food script or, without
+// a script, on a random free cell.
+func (game *Game) CreateFood() error {
+	return game.placeFood(nil)
+}
+
+// RelocateFood moves the food to another cell and reports whether it moved.
+// The food stays where it is if there is no other free cell.
+func (game *Game) RelocateFood() (bool, error) {
+	old := game.food
+	err := game.placeFood(func(pos Pos) bool { return pos != old })
+	if errors.Is(err, ErrNoFreeCell) {
+		game.foodAge = 0
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// placeFood places the food like CreateFood but only on cells for which
+// allowed holds, if it is set.
+func (game *Game) placeFood(allowed func(Pos) bool) error {
+	if pos, ok := game.NextScriptedFood(); ok {
+		return game.setFood(pos)
+	}
+	guarded := game.FoodMinMoves > 0 || game.EatRule == EatTouch || (game.Bounds == BoundsWalls && game.FoodWallMargin > 0)
+	guardedAllowed := allowed
+	if guarded {
+		guardedAllowed = func(pos Pos) bool {
+			return (allowed == nil || allowed(pos)) && game.IsGuardedFoodCell(pos)
+		}
+	}
+	cells, err := game.Allocate(1, guardedAllowed)
+	if errors.Is(err, ErrNoFreeCell) && guarded {
+		// the guards must not make placing food impossible on a crowded board
+		cells, err = game.Allocate(1, allowed)
+	}
+	if err != nil {
+		return err
+	}
+	return game.setFood(cells[0])
+}
+
+// setFood places the food on pos or where the Script moves it.
+func (game *Game) setFood(pos Pos) error {
+	pos, err := game.scriptFood(pos)
+	if err != nil {
+		return err
+	}
+	game.food = pos
+	game.foodAge = 0
+	game.wanderAge = 0
+	game.Debug("food placed", "x", pos.X, "y", pos.Y)
+	return nil
+}
+
+// IsGuardedFoodCell reports whether pos is far enough from the head to place
+// food on it. Cells that are less than FoodMinMoves moves away from the head
+// and, with lethal walls, cells closer than FoodWallMargin to an edge are
+// excluded. With the touch eat rule cells touching the head are excluded.
+func (game *Game) IsGuardedFoodCell(pos Pos) bool {
+	if game.Bounds == BoundsWalls && game.WallDistance(pos) < game.FoodWallMargin {
+		return false
+	}
+	minMoves := game.FoodMinMoves
+	if game.EatRule == EatTouch && minMoves < 2 {
+		minMoves = 2
+	}
+	return game.Distance(game.snail.GetHead(), pos) >= minMoves
+}
+
+// WallDistance returns the number of cells between pos and the closest edge.
+func (game *Game) WallDistance(pos Pos) int {
+	return min(pos.X, game.XDim-1-pos.X, pos.Y, game.YDim-1-pos.Y)
+}
+
+// Distance returns the number of moves needed to get from a to b, taking
+// wrapping around the edges into account.
+func (game *Game) Distance(a, b Pos) int {
+	dx := abs(a.X - b.X)
+	dy := abs(a.Y - b.Y)
+	if game.Bounds == BoundsWrap {
+		dx = min(dx, game.XDim-dx)
+		dy = min(dy, game.YDim-dy)
+	}
+	return dx + dy
+}
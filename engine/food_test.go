@@ -0,0 +1,171 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoodMinMoves(t *testing.T) {
+	tests := []struct {
+		name     string
+		minMoves int
+		bounds   Bounds
+	}{
+		{"two moves", 2, BoundsWrap},
+		{"three moves", 3, BoundsWrap},
+		{"two moves with walls", 2, BoundsWalls},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 50; seed++ {
+				game, err := New(Config{XDim: 7, YDim: 7, Seed: seed, FoodMinMoves: test.minMoves, Bounds: test.bounds})
+				if err != nil {
+					t.Fatal(err)
+				}
+				head := game.Head()
+				// the cell the snail is about to enter is one move away
+				forward, _ := game.NextPos(head, game.Snail().Direction)
+				for i := 0; i < 20; i++ {
+					if err := game.CreateFood(); err != nil {
+						t.Fatal(err)
+					}
+					food := game.Food()
+					if food == forward {
+						t.Fatalf("seed %d: food in front of the head at %v", seed, food)
+					}
+					if distance := game.Distance(head, food); distance < test.minMoves {
+						t.Fatalf("seed %d: food at %v is %d moves from the head, want at least %d", seed, food, distance, test.minMoves)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFoodWallMargin(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		margin int
+		bounds Bounds
+		// want is the smallest distance of the food to an edge
+		want int
+	}{
+		{"one cell", 10, 1, BoundsWalls, 1},
+		{"two cells", 10, 2, BoundsWalls, 2},
+		{"no margin", 10, 0, BoundsWalls, 0},
+		// the center of a 5x5 board is covered by the snail
+		{"no cell left", 5, 2, BoundsWalls, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				game, err := New(Config{XDim: test.size, YDim: test.size, Seed: seed, Bounds: test.bounds, FoodWallMargin: test.margin})
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < 20; i++ {
+					if err := game.CreateFood(); err != nil {
+						t.Fatal(err)
+					}
+					if distance := game.WallDistance(game.Food()); distance < test.want {
+						t.Fatalf("seed %d: food at %v is %d cells from an edge, want at least %d", seed, game.Food(), distance, test.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFoodTTL(t *testing.T) {
+	tests := []struct {
+		ttl, penalty int
+		// wantMoves are the ticks the food moves on, every food stays for ttl
+		// ticks and moves before the move of the next one
+		wantMoves []int
+		wantScore int
+	}{
+		{0, 5, nil, 100},
+		{4, 0, []int{5, 9, 13, 17}, 100},
+		{6, 5, []int{7, 13, 19}, 85},
+		{6, 40, []int{7, 13, 19}, 0},
+	}
+	for _, test := range tests {
+		// the snail circles along row 5 and never reaches the food on row 0
+		var script []Pos
+		for x := 0; x < 10; x++ {
+			script = append(script, Pos{X: x, Y: 0})
+		}
+		game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, FoodTTL: test.ttl, FoodTTLPenalty: test.penalty, FoodScript: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+		game.scorer.Score = 100
+		var moves []int
+		for tick := 1; tick <= 20; tick++ {
+			food := game.Food()
+			if _, err := game.Step(); err != nil {
+				t.Fatal(err)
+			}
+			if game.Food() != food {
+				moves = append(moves, tick)
+			}
+		}
+		if !reflect.DeepEqual(moves, test.wantMoves) || game.Score() != test.wantScore || game.FoodsEaten() != 0 {
+			t.Errorf("ttl %d penalty %d: food moved on ticks %v with score %d and %d foods eaten, want %v with score %d and none",
+				test.ttl, test.penalty, moves, game.Score(), game.FoodsEaten(), test.wantMoves, test.wantScore)
+		}
+	}
+}
+
+func TestFoodWanderTTL(t *testing.T) {
+	// the snail circles along row 5, the food wanders on the top rows and
+	// still expires every 6 ticks
+	var script []Pos
+	for x := 0; x < 10; x++ {
+		script = append(script, Pos{X: x, Y: 0})
+	}
+	game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, FoodTTL: 6, FoodTTLPenalty: 5, FoodWander: 2, FoodScript: script})
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.scorer.Score = 100
+	wanders := 0
+	for tick := 1; tick <= 20; tick++ {
+		if _, err := game.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if game.recording.Ticks[len(game.recording.Ticks)-1].Wandered {
+			wanders += 1
+		}
+	}
+	if wanders == 0 || game.Score() != 85 || game.FoodsEaten() != 0 {
+		t.Errorf("food wandered %d times with score %d and %d foods eaten, want some wandering with score 85 and none",
+			wanders, game.Score(), game.FoodsEaten())
+	}
+	if game.scriptIndex != 4 {
+		t.Errorf("%d scripted foods were placed, want 4", game.scriptIndex)
+	}
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReadFoodScript parses a food script: one position per line given as the
+// column and row separated by whitespace. Empty lines are ignored.
+func ReadFoodScript(r io.Reader) ([]Pos, error) {
+	var script []Pos
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line += 1
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected two fields", line)
+		}
+		x, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		y, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		script = append(script, Pos{X: x, Y: y})
+	}
+	return script, scanner.Err()
+}
+
+// ReadFoodScriptFile reads the food script stored at path.
+func ReadFoodScriptFile(path string) ([]Pos, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadFoodScript(file)
+}
+
+// NextScriptedFood returns the next position of the food script. Positions
+// outside of the grid or on an occupied cell are skipped. It returns false
+// once the script is used up.
+func (game *Game) NextScriptedFood() (Pos, bool) {
+	for game.scriptIndex < len(game.FoodScript) {
+		pos := game.FoodScript[game.scriptIndex]
+		game.scriptIndex += 1
+		if pos.X < 0 || pos.X >= game.XDim || pos.Y < 0 || pos.Y >= game.YDim {
+			continue
+		}
+		if !game.CheckCollisions(pos, game.Occupied()) {
+			return pos, true
+		}
+	}
+	return Pos{}, false
+}
+
+// UpcomingFood returns up to count positions of the food script that follow
+// the current food.
+func (game *Game) UpcomingFood(count int) []Pos {
+	upcoming := game.FoodScript[game.scriptIndex:]
+	if count < len(upcoming) {
+		upcoming = upcoming[:count]
+	}
+	return upcoming
+}
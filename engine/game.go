@@ -0,0 +1,656 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"time"
+)
+
+// DefaultGrowth is the number of segments the snail grows per eaten food if
+// Config.Growth is not set.
+const DefaultGrowth = 1
+
+// NoGrowth as Config.Growth keeps the snail at its length when it eats.
+const NoGrowth = -1
+
+// Config holds the rules a game is played with.
+type Config struct {
+	// XDim and YDim are the number of columns and rows of the grid.
+	XDim int
+	YDim int
+	// Bounds decides what happens at the edges of the grid.
+	Bounds Bounds
+	// EatRule decides when the head reaches the food.
+	EatRule EatRule
+	// ScoreWeight scales the points per food, zero uses DefaultScoreWeight.
+	ScoreWeight int
+	// FoodMinMoves is the minimum number of moves between the head and new food.
+	FoodMinMoves int
+	// FoodWallMargin is the minimum distance of food to lethal walls.
+	FoodWallMargin int
+	// Seed seeds the random placement, zero picks a new seed.
+	Seed int64
+	// Replay, if set, is played back instead of taking inputs.
+	Replay *InputLog
+	// Growth is the number of segments the snail grows per eaten food, zero
+	// uses DefaultGrowth and NoGrowth turns growing off.
+	Growth int
+	// GrowthRule decides when the snail grows.
+	GrowthRule GrowthRule
+	// ScoreRule decides what the snail scores points for.
+	ScoreRule ScoreRule
+	// Scoring names the ScoringPolicy of the points per food, see
+	// ScoringPolicies. Empty uses DefaultScoring.
+	Scoring string
+	// ComboWindow, if set, multiplies the points of food eaten within
+	// ComboWindow moves beyond the shortest path in a row, see Scorer.
+	ComboWindow int
+	// StartLength is the number of segments the snail starts with, laid out
+	// like a SerpentineSnail. Up to three segments start the snail in the
+	// center of the grid.
+	StartLength int
+	// GrowthLag is the number of extra ticks the tail pauses after eating.
+	GrowthLag int
+	// Dual adds a Rival on the other half of the grid that mirrors the snail.
+	Dual bool
+	// Versus adds a Rival steered by the computer on the other half of the
+	// grid.
+	Versus bool
+	// TwoPlayers adds a Rival steered by a second player with TurnRival on
+	// the other half of the grid.
+	TwoPlayers bool
+	// LongerWins lets the longer snail survive running into the body of a
+	// shorter Rival, or a longer Rival running into the snail, see
+	// ResolveSnailCollision.
+	LongerWins bool
+	// Autopilot, if set, steers the snail.
+	Autopilot *Autopilot
+	// ObjectiveFoods is the number of foods to eat to win, zero is off.
+	ObjectiveFoods int
+	// MoveBudget is the number of moves available, zero is unlimited.
+	MoveBudget int
+	// ObstacleCount is the number of obstacles scattered on the grid.
+	ObstacleCount int
+	// PortalPairs is the number of portals placed on the grid.
+	PortalPairs int
+	// Layers is the number of stacked layers of the grid, up to MaxLayers.
+	// Zero and one are a single layer.
+	Layers int
+	// Ladders is the number of ladders between every two stacked layers, at
+	// least one is placed.
+	Ladders int
+	// EventInterval is the average number of ticks between random events,
+	// see RunEvents. Zero disables them.
+	EventInterval int
+	// ObstacleDensity is the share of cells in percent covered by obstacles,
+	// it replaces ObstacleCount if set.
+	ObstacleDensity int
+	// WinBonus are the points for winning, see WinBonus.
+	WinBonus int
+	// AntiStall is the number of moves beyond the shortest path after which
+	// food is moved, zero is off.
+	AntiStall int
+	// Grace is the number of ticks at the start in which collisions are ignored.
+	Grace int
+	// Lives is the number of times the snail respawns after dying.
+	Lives int
+	// FoodScript lists the positions food is placed on before random ones.
+	FoodScript []Pos
+	// RewindChance is the chance in percent that eating spawns a power-up.
+	RewindChance int
+	// PoisonChance is the chance in percent that eating spawns poison.
+	PoisonChance int
+	// PowerUpChance is the chance in percent that eating spawns one of the
+	// PowerUps.
+	PowerUpChance int
+	// GoldenChance is the chance in percent that eating spawns golden food.
+	GoldenChance int
+	// FoodTTL is the number of ticks after which uneaten food moves, zero is
+	// never.
+	FoodTTL int
+	// FoodWander is the number of ticks after which the food takes a step,
+	// zero keeps it in place.
+	FoodWander int
+	// FoodTTLPenalty are the points lost when food moves because of FoodTTL.
+	FoodTTLPenalty int
+	// Rules decide the core rules of the game, nil uses DefaultRules.
+	Rules RuleSet `json:"-"`
+	// Script, if set, intercepts ticks, food placement and scoring.
+	Script Script `json:"-"`
+	// Level, if set, replaces the grid dimensions, the obstacles and the start
+	// of the snail.
+	Level *Level
+	// Logger, if set, receives a debug log of the events of every tick.
+	Logger *slog.Logger `json:"-"`
+}
+
+// growth returns the number of segments the snail grows per eaten food.
+func (config Config) growth() int {
+	return max(config.Growth, 0)
+}
+
+// Clone returns a copy of the config that shares no autopilot, food script or
+// level with config, so games started with both do not affect each other.
+func (config Config) Clone() Config {
+	if config.Autopilot != nil {
+		autopilot := *config.Autopilot
+		config.Autopilot = &autopilot
+	}
+	config.FoodScript = slices.Clone(config.FoodScript)
+	if config.Level != nil {
+		level := *config.Level
+		level.Walls = slices.Clone(level.Walls)
+		level.Portals = slices.Clone(level.Portals)
+		config.Level = &level
+	}
+	return config
+}
+
+// Game is a single game of snail. It is advanced with Step and its state is
+// read through its accessors.
+type Game struct {
+	Config
+	food          Pos
+	snail         Snail
+	start         Snail
+	scorer        Scorer
+	currentSeed   int64
+	source        *countedSource
+	rng           *rand.Rand
+	tick          int
+	inputLogger   *InputLogger
+	replayIndex   int
+	pendingGrowth int
+	lagLeft       int
+	stretch       int
+	foodsEaten    int
+	movesLeft     int
+	obstacles     []Pos
+	portals       [][2]Pos
+	rivals        []Rival
+	outcome       SnailOutcome
+	graceLeft     int
+	livesLeft     int
+	respawned     bool
+	scriptIndex   int
+	perfects      int
+	powerUp       *Pos
+	pickups       []Pickup
+	effects       map[PickupKind]int
+	event         *Event
+	nextEvent     int
+	rewinds       int
+	history       SnapshotRing
+	foodAge       int
+	wanderAge     int
+	wandered      bool
+	over          bool
+	timeUp        bool
+	wrapped       bool
+	wrapExit      Pos
+	wrapEntry     Pos
+	recording     Recording
+}
+
+// New starts a game with config. With a Replay the seed and, if it was logged,
+// the config of the replayed game are used.
+func New(config Config) (*Game, error) {
+	if config.Replay != nil && config.Replay.Config != nil {
+		config = config.Replay.replayConfig(config)
+	}
+	if config.Level != nil {
+		config.XDim = config.Level.Width
+		config.YDim = config.Level.Height
+	}
+	if config.XDim < 3 || config.YDim < 1 {
+		return nil, fmt.Errorf("invalid grid dimensions %dx%d", config.XDim, config.YDim)
+	}
+	if config.Layers > MaxLayers {
+		return nil, fmt.Errorf("a grid has at most %d layers, not %d", MaxLayers, config.Layers)
+	}
+	if config.ScoreWeight == 0 {
+		config.ScoreWeight = DefaultScoreWeight
+	}
+	if config.Growth == 0 {
+		config.Growth = DefaultGrowth
+	}
+	if config.Growth < NoGrowth {
+		return nil, fmt.Errorf("invalid growth %d", config.Growth)
+	}
+	if config.Scoring == "" {
+		config.Scoring = DefaultScoring
+	}
+	if _, err := ParseScoringPolicy(config.Scoring); err != nil {
+		return nil, err
+	}
+	seed := config.Seed
+	if config.Replay != nil {
+		seed = config.Replay.Seed
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	source := newCountedSource(seed, 0)
+	game := &Game{
+		Config:      config,
+		currentSeed: seed,
+		source:      source,
+		rng:         rand.New(source),
+		snail:       InitSnail(config.XDim, config.YDim),
+		scorer:      InitScorer(config.XDim, config.YDim, config.ScoreWeight),
+		graceLeft:   config.Grace,
+		movesLeft:   config.MoveBudget,
+		livesLeft:   config.Lives,
+	}
+	if config.Level != nil {
+		if err := config.Level.CheckStart(config.Bounds); err != nil {
+			return nil, err
+		}
+		game.snail = config.Level.StartSnail()
+		game.obstacles = append([]Pos(nil), config.Level.Walls...)
+		game.portals = append([][2]Pos(nil), config.Level.Portals...)
+		if err := game.CheckConnected(); err != nil {
+			return nil, err
+		}
+	} else {
+		control, rival, err := config.rivalControl()
+		if err != nil {
+			return nil, err
+		}
+		if rival {
+			// the snail starts in the upper half, the rival in the lower one
+			game.snail = InitSnail(config.XDim, config.YDim/2)
+		}
+		if config.StartLength > len(game.snail.Body) {
+			snail, err := SerpentineSnail(config.XDim, config.YDim, config.StartLength)
+			if err != nil {
+				return nil, err
+			}
+			game.snail = snail
+		}
+		if rival {
+			if err := game.AddRival(MirrorSnail(game.snail, config.YDim), control); err != nil {
+				return nil, err
+			}
+		}
+		game.PlaceObstacles(config.Obstacles())
+		if err := game.PlacePortals(config.PortalPairs); err != nil {
+			return nil, err
+		}
+		if err := game.PlaceLadders(config.Ladders); err != nil {
+			return nil, err
+		}
+	}
+	game.start = game.Snail()
+	if err := game.CreateFood(); err != nil {
+		return nil, err
+	}
+	game.scorer.OldHeadPos = game.snail.GetHead()
+	game.scorer.OldFoodPos = game.food
+	game.scorer.comboWindow = config.ComboWindow
+	game.scorer.scoring = config.Scoring
+	game.scorer.bounds = config.Bounds
+	game.recording = Recording{
+		Seed:        seed,
+		Width:       config.XDim,
+		Height:      config.YDim,
+		ScoreWeight: config.ScoreWeight,
+		WinBonus:    config.WinBonus,
+		Bounds:      config.Bounds,
+		EatRule:     config.EatRule,
+		FoodTTL:     config.FoodTTL,
+		TTLPenalty:  config.FoodTTLPenalty,
+		Portals:     game.portals,
+		Layers:      config.Layers,
+		Lives:       config.Lives,
+		Rivals:      game.rivalControls(),
+		ScoreRule:   config.ScoreRule,
+		ComboWindow: config.ComboWindow,
+		Scoring:     config.Scoring,
+	}
+	if config.Rules != nil {
+		// unregistered rules are verified like the classic ones
+		game.recording.Rules, _ = RulesName(config.Rules)
+	}
+	if config.Lives > 0 {
+		game.recording.Start = game.start.Body
+	}
+	return game, nil
+}
+
+// Step advances the game by one tick. It returns false once the game is over,
+// see EndGame.
+func (game *Game) Step() (bool, error) {
+	game.wrapped = false
+	if err := game.ReplayInputs(); err != nil {
+		return false, err
+	}
+	if game.EatsFood(game.food, game.snail.Body) {
+		game.pendingGrowth += game.growth()
+		game.lagLeft += game.GrowthLag
+		game.foodsEaten += 1
+		food, err := game.scorer.CalculateScore(len(game.snail.Body))
+		if err != nil {
+			return false, err
+		}
+		if game.ScoreRule == ScoreSurvival {
+			game.scorer.Rescore(0)
+			food.Points = 0
+		}
+		if food.Perfect() {
+			game.perfects += 1
+		}
+		food, err = game.scriptScore(food)
+		if err != nil {
+			return false, err
+		}
+		if game.Active(PickupDouble) {
+			food.Points *= 2
+			game.scorer.Rescore(food.Points)
+		}
+		game.Debug("food eaten", "steps", food.Steps, "distance", food.Distance, "points", food.Points, "score", game.scorer.Score)
+		game.rules().OnEat(game)
+		if game.WonGame() || game.BoardFull() {
+			// the last free cell was eaten, there is no room for new food
+			game.recording.Record(game)
+			return false, nil
+		}
+		if err := game.CreateFood(); err != nil {
+			return false, err
+		}
+		game.scorer.OldHeadPos = game.snail.GetHead()
+		game.scorer.OldFoodPos = game.food
+		game.SpawnPowerUp()
+		game.SpawnPickups()
+	} else if over, err := game.FeedRivals(); over || err != nil {
+		game.recording.Record(game)
+		return false, err
+	}
+	game.CollectPowerUp()
+	game.CollectPickups()
+	game.AgePickups()
+	if game.Stalled() || game.FoodExpired() {
+		expired := game.FoodExpired()
+		moved, err := game.RelocateFood()
+		if err != nil {
+			return false, err
+		}
+		if moved {
+			if expired {
+				game.scorer.Penalize(game.FoodTTLPenalty)
+				game.Debug("food expired", "penalty", game.FoodTTLPenalty, "score", game.scorer.Score)
+			}
+			game.scorer.ResetSteps()
+			game.scorer.OldHeadPos = game.snail.GetHead()
+			game.scorer.OldFoodPos = game.food
+		}
+	}
+	if game.WanderFood() {
+		// the food is scored from where it wandered to, like moved food
+		game.scorer.ResetSteps()
+		game.scorer.OldHeadPos = game.snail.GetHead()
+		game.scorer.OldFoodPos = game.food
+	}
+	if err := game.RunEvents(); err != nil {
+		return false, err
+	}
+	if game.Autopilot != nil {
+		if err := game.ChangeDirection(game.Autopilot.NextDirection(game)); err != nil {
+			return false, err
+		}
+	}
+	grow := game.Growing()
+	if game.Bounds == BoundsBounce {
+		game.Bounce(grow)
+	}
+	game.SteerRivals()
+	game.recording.Record(game)
+	if game.Collides() && game.rules().OnCollision(game) {
+		if game.Rewind() {
+			return true, nil
+		}
+		if !game.Respawn() {
+			game.outcome = OutcomeFirstDies
+			return false, nil
+		}
+		// the respawn takes the place of the move
+		return true, game.finishTick()
+	}
+	if game.RivalsCollide() {
+		return false, nil
+	}
+	if game.WonGame() || game.OutOfMoves() || game.timeUp {
+		return false, nil
+	}
+	game.TakeSnapshot()
+	game.ConsumeGrowth()
+	oldHead := game.snail.GetHead()
+	newHead, wrapped := game.NextPos(oldHead, game.snail.Direction)
+	game.snail.MoveTo(newHead, grow)
+	game.MoveRivals()
+	if wrapped {
+		game.wrapped = true
+		game.wrapExit = oldHead
+		game.wrapEntry = game.snail.GetHead()
+	}
+	if !grow && game.stretch > 0 {
+		// the tail catches up with the stretched body
+		game.snail.Body = game.snail.Body[1:]
+		game.stretch -= 1
+	}
+	if err := game.finishTick(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// finishTick counts the tick after the snail moved.
+func (game *Game) finishTick() error {
+	game.scorer.Step()
+	if game.ScoreRule == ScoreSurvival {
+		game.scorer.AddPoints(1)
+	}
+	game.tick += 1
+	ageEffects(game.effects)
+	game.movesLeft -= 1
+	game.foodAge += 1
+	game.wanderAge += 1
+	if game.graceLeft > 0 {
+		game.graceLeft -= 1
+	}
+	return game.scriptTick()
+}
+
+// EndGame marks the game as over, awards the win bonus and reports whether
+// the game was won.
+func (game *Game) EndGame() bool {
+	game.over = true
+	won := game.WonGame()
+	if won {
+		game.scorer.Score += WinBonus(game.WinBonus, game.foodsEaten, game.tick, game.XDim, game.YDim)
+	}
+	game.recording.Won = won
+	return won
+}
+
+// Died reports whether the game ended because the snail died.
+func (game *Game) Died() bool {
+	return game.over && !game.WonGame() && !game.OutOfMoves() && !game.timeUp
+}
+
+// SetTimeUp ends the game on its next Step because its time limit was
+// reached.
+func (game *Game) SetTimeUp() {
+	game.timeUp = true
+}
+
+// Outcome describes how the game ended.
+func (game *Game) Outcome() string {
+	switch {
+	case !game.over:
+		return "stopped"
+	case game.WonGame():
+		return "won"
+	case game.OutOfMoves():
+		return "out of moves"
+	case game.timeUp:
+		return "time limit"
+	default:
+		return "died"
+	}
+}
+
+// Food returns the position of the food.
+func (game *Game) Food() Pos {
+	return game.food
+}
+
+// Snail returns a copy of the snail.
+func (game *Game) Snail() Snail {
+	snail := game.snail
+	snail.Body = append([]Pos(nil), game.snail.Body...)
+	return snail
+}
+
+// Head returns the position of the head of the snail.
+func (game *Game) Head() Pos {
+	return game.snail.GetHead()
+}
+
+// Length returns the number of segments of the snail.
+func (game *Game) Length() int {
+	return len(game.snail.Body)
+}
+
+// Score returns the current score.
+func (game *Game) Score() int {
+	return game.scorer.Score
+}
+
+// Combo returns the multiplier of the points for the next food.
+func (game *Game) Combo() int {
+	return game.scorer.Combo()
+}
+
+// Breakdown returns how the points for every eaten food came about.
+func (game *Game) Breakdown() []FoodScore {
+	return append([]FoodScore(nil), game.scorer.Breakdown...)
+}
+
+// WriteBreakdown writes the score breakdown to w, see Scorer.WriteBreakdown.
+func (game *Game) WriteBreakdown(w io.Writer) error {
+	return game.scorer.WriteBreakdown(w)
+}
+
+// Obstacles returns a copy of the obstacles on the grid.
+func (game *Game) Obstacles() []Pos {
+	return append([]Pos(nil), game.obstacles...)
+}
+
+// PowerUp returns the position of the power-up and false if there is none.
+func (game *Game) PowerUp() (Pos, bool) {
+	if game.powerUp == nil {
+		return Pos{}, false
+	}
+	return *game.powerUp, true
+}
+
+// CurrentSeed returns the seed the game was started with.
+func (game *Game) CurrentSeed() int64 {
+	return game.currentSeed
+}
+
+// Tick returns the number of moves made so far.
+func (game *Game) Tick() int {
+	return game.tick
+}
+
+// PendingGrowth returns the number of segments the snail still grows by.
+func (game *Game) PendingGrowth() int {
+	return game.pendingGrowth
+}
+
+// FoodsEaten returns the number of foods eaten so far.
+func (game *Game) FoodsEaten() int {
+	return game.foodsEaten
+}
+
+// MovesLeft returns the moves left of the MoveBudget.
+func (game *Game) MovesLeft() int {
+	return game.movesLeft
+}
+
+// GraceLeft returns the ticks left in which collisions are ignored.
+func (game *Game) GraceLeft() int {
+	return game.graceLeft
+}
+
+// Perfects returns the number of foods reached on a shortest path.
+func (game *Game) Perfects() int {
+	return game.perfects
+}
+
+// Rewinds returns the number of rewind charges.
+func (game *Game) Rewinds() int {
+	return game.rewinds
+}
+
+// RivalOutcome returns which snail died once the game ended because the snail
+// or a Vital rival died.
+func (game *Game) RivalOutcome() SnailOutcome {
+	return game.outcome
+}
+
+// LivesLeft returns the number of respawns left.
+func (game *Game) LivesLeft() int {
+	return game.livesLeft
+}
+
+// Over reports whether EndGame was called.
+func (game *Game) Over() bool {
+	return game.over
+}
+
+// TimeUp reports whether SetTimeUp was called.
+func (game *Game) TimeUp() bool {
+	return game.timeUp
+}
+
+// Wrapped returns the cells the head moved between if it wrapped around an
+// edge on the last Step.
+func (game *Game) Wrapped() (exit, entry Pos, ok bool) {
+	return game.wrapExit, game.wrapEntry, game.wrapped
+}
+
+// Recording returns the recording of the game with its current score.
+func (game *Game) Recording() Recording {
+	rec := game.recording
+	rec.Ticks = append([]RecordedTick(nil), game.recording.Ticks...)
+	rec.Score = game.scorer.Score
+	return rec
+}
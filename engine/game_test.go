@@ -0,0 +1,208 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "testing"
+
+// playOut steps the game until it is over and returns whether it was won.
+func playOut(t *testing.T, game *Game) bool {
+	t.Helper()
+	for ticks := 0; ; ticks++ {
+		if ticks > 10*game.XDim*game.YDim {
+			t.Fatal("the game did not end")
+		}
+		running, err := game.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !running {
+			return game.EndGame()
+		}
+	}
+}
+
+func TestObjective(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  int
+		won     bool
+		outcome string
+		foods   int
+	}{
+		// the snail starts at 7, 5 heading east and needs two moves
+		{"in time", 10, true, "won", 2},
+		{"last move", 2, true, "won", 2},
+		{"out of moves", 1, false, "out of moves", 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, ObjectiveFoods: 2, MoveBudget: test.budget,
+				FoodScript: []Pos{{X: 8, Y: 5}, {X: 9, Y: 5}, {X: 0, Y: 0}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			won := playOut(t, game)
+			if won != test.won || game.Outcome() != test.outcome || game.FoodsEaten() != test.foods {
+				t.Errorf("won %v outcome %q foods %d, want won %v outcome %q foods %d",
+					won, game.Outcome(), game.FoodsEaten(), test.won, test.outcome, test.foods)
+			}
+			if game.Died() {
+				t.Error("the game counts as died")
+			}
+		})
+	}
+}
+
+func TestEndGameWinBonus(t *testing.T) {
+	tests := []struct {
+		name string
+		// the snail starts at 7, 5 heading east
+		script []Pos
+		bonus  int
+	}{
+		// the par of a single food on a 10x10 grid is 10 moves
+		{"no bonus", []Pos{{X: 8, Y: 5}}, 0},
+		{"fast", []Pos{{X: 8, Y: 5}}, 100},
+		{"slow", []Pos{{X: 0, Y: 0}}, 100},
+	}
+	bonuses := map[string]int{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := Config{XDim: 10, YDim: 10, Seed: 1, Bounds: BoundsWalls, ObjectiveFoods: 1, WinBonus: test.bonus, FoodScript: test.script}
+			game, err := New(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var pilot Autopilot
+			for {
+				if err := game.ChangeDirection(pilot.NextDirection(game)); err != nil {
+					t.Fatal(err)
+				}
+				running, err := game.Step()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !running {
+					break
+				}
+			}
+			score := game.Score()
+			if !game.EndGame() {
+				t.Fatal("the objective was not reached")
+			}
+			want := WinBonus(test.bonus, 1, game.Tick(), 10, 10)
+			if bonus := game.Score() - score; bonus != want {
+				t.Errorf("won a bonus of %d after %d moves, want %d", bonus, game.Tick(), want)
+			}
+			if rec := game.Recording(); rec.Score != game.Score() || !rec.Won {
+				t.Errorf("recorded score %d won %v, want %d won true", rec.Score, rec.Won, game.Score())
+			}
+			bonuses[test.name] = game.Score() - score
+		})
+	}
+	if bonuses["no bonus"] != 0 || bonuses["fast"] <= bonuses["slow"] || bonuses["slow"] < 100 {
+		t.Errorf("bonuses %v, want none without a bonus and a larger one for the faster game", bonuses)
+	}
+}
+
+func TestGrowthLag(t *testing.T) {
+	tests := []struct {
+		growth, lag int
+		// wantFixed is the number of ticks the tail stays in place from the
+		// tick the food is eaten on
+		wantFixed int
+	}{
+		{NoGrowth, 0, 0},
+		{NoGrowth, 1, 1},
+		{NoGrowth, 2, 2},
+		{0, 0, 1},
+		{1, 0, 1},
+		{1, 2, 3},
+	}
+	for _, test := range tests {
+		game, err := New(Config{XDim: 20, YDim: 10, Seed: 1, Growth: test.growth, GrowthLag: test.lag,
+			FoodScript: []Pos{{X: 14, Y: 5}, {X: 0, Y: 0}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the head reaches the food on the second tick
+		for i := 0; i < 2; i++ {
+			if _, err := game.Step(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		fixed := 0
+		for tick := 0; tick < 8; tick++ {
+			tail := game.Snail().Body[0]
+			if _, err := game.Step(); err != nil {
+				t.Fatal(err)
+			}
+			if tick == 0 && game.FoodsEaten() != 1 {
+				t.Fatalf("growth %d lag %d: the food was not eaten", test.growth, test.lag)
+			}
+			if game.Snail().Body[0] == tail {
+				fixed++
+			}
+		}
+		if fixed != test.wantFixed {
+			t.Errorf("growth %d lag %d: the tail stayed in place for %d ticks, want %d", test.growth, test.lag, fixed, test.wantFixed)
+		}
+		// the stretched body is caught up with after the lag
+		// the tail stays in place once per grown segment and lag tick
+		if want := 3 + test.wantFixed - test.lag; game.Length() != want {
+			t.Errorf("growth %d lag %d: length %d, want %d", test.growth, test.lag, game.Length(), want)
+		}
+	}
+}
+
+// fixedScript awards points for every food.
+type fixedScript struct {
+	points int
+}
+
+func (fixedScript) Tick(game *Game) error { return nil }
+
+func (fixedScript) PlaceFood(game *Game, pos Pos) (Pos, error) { return pos, nil }
+
+func (script fixedScript) Score(game *Game, food FoodScore) (int, error) { return script.points, nil }
+
+func TestDoublePointsScript(t *testing.T) {
+	game, err := New(Config{XDim: 20, YDim: 10, Seed: 1, Growth: 1, Script: fixedScript{points: 7},
+		FoodScript: []Pos{{X: 13, Y: 5}, {X: 0, Y: 0}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.effects = map[PickupKind]int{PickupDouble: 10}
+	// the head reaches the food on the first tick and it is eaten on the next
+	for i := 0; i < 2; i++ {
+		if _, err := game.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if game.FoodsEaten() != 1 {
+		t.Fatal("the food was not eaten")
+	}
+	if breakdown := game.Breakdown(); game.Score() != 14 || breakdown[0].Points != 14 {
+		t.Errorf("score %d with %v, want the 7 points of the script doubled to 14", game.Score(), breakdown)
+	}
+}
@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// Heatmap counts how often the head of the snail visited each cell in the
+// recording, indexed by row and column. The cells of stacked layers are
+// counted together.
+func (rec *Recording) Heatmap() [][]int {
+	heat := make([][]int, rec.Height)
+	for y := range heat {
+		heat[y] = make([]int, rec.Width)
+	}
+	for _, tick := range rec.Ticks {
+		if len(tick.Body) == 0 {
+			continue
+		}
+		head := tick.Body[len(tick.Body)-1]
+		if head.X >= 0 && head.X < rec.Width && head.Y >= 0 && head.Y < rec.Height {
+			heat[head.Y][head.X] += 1
+		}
+	}
+	return heat
+}
+
+// Hottest returns the most visited cell of heat and its number of visits.
+func Hottest(heat [][]int) (Pos, int) {
+	hottest, visits := Pos{}, 0
+	for y, row := range heat {
+		for x, count := range row {
+			if count > visits {
+				hottest, visits = Pos{X: x, Y: y}, count
+			}
+		}
+	}
+	return hottest, visits
+}
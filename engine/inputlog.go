@@ -0,0 +1,254 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Names of the inputs that are written to an input log.
+const (
+	InputNorth = "north"
+	InputSouth = "south"
+	InputEast  = "east"
+	InputWest  = "west"
+	InputPause = "pause"
+	// InputRival prefixes the direction inputs of the rival steered by a
+	// second player, e.g. rival-north.
+	InputRival = "rival-"
+)
+
+var directionInputs = map[string]Velocity{
+	InputNorth: NorthDir,
+	InputSouth: SouthDir,
+	InputEast:  EastDir,
+	InputWest:  WestDir,
+}
+
+// DirectionInput returns the input name of dir.
+func DirectionInput(dir Velocity) string {
+	for name, velocity := range directionInputs {
+		if velocity.Equals(dir) {
+			return name
+		}
+	}
+	return ""
+}
+
+// InputEvent is an input that was applied by the game loop on Tick.
+type InputEvent struct {
+	Tick  int
+	Input string
+}
+
+// InputLog is a recorded session: the seed and the config of the game and all
+// inputs that were applied to it. Replaying the events against a game using
+// the same seed and config reproduces the session, see New.
+type InputLog struct {
+	Seed int64
+	// Config is the config the game was played with, nil if the log does not
+	// record it. Its Replay and the fields that are not stored as json are
+	// never set.
+	Config *Config
+	// Delay is the delay between two ticks the game started with.
+	Delay time.Duration
+	// Rules is the name the rules of the game are registered under, empty
+	// for the default rules or rules that are not registered.
+	Rules  string
+	Events []InputEvent
+}
+
+// writeHeader writes the seed and the config of the log, one "name value"
+// line each, starting with the seed. The config is written as json.
+func (inputLog InputLog) writeHeader(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "seed %d\n", inputLog.Seed); err != nil {
+		return err
+	}
+	if inputLog.Config != nil {
+		config := *inputLog.Config
+		config.Replay = nil
+		data, err := json.Marshal(config)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "config %s\n", data); err != nil {
+			return err
+		}
+	}
+	if inputLog.Delay > 0 {
+		if _, err := fmt.Fprintf(w, "delay %s\n", inputLog.Delay); err != nil {
+			return err
+		}
+	}
+	if inputLog.Rules != "" {
+		if _, err := fmt.Fprintf(w, "rules %s\n", inputLog.Rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readHeader sets the header field name of the log to value.
+func (inputLog *InputLog) readHeader(name, value string) error {
+	var err error
+	switch name {
+	case "config":
+		inputLog.Config = &Config{}
+		err = json.Unmarshal([]byte(value), inputLog.Config)
+	case "delay":
+		inputLog.Delay, err = time.ParseDuration(value)
+	case "rules":
+		inputLog.Rules = value
+	default:
+		err = fmt.Errorf("unknown header %q", name)
+	}
+	return err
+}
+
+// InputLogger writes processed input events to a file, one event per line
+// preceded by a header containing the seed and the config of the game.
+type InputLogger struct {
+	file *os.File
+}
+
+// replayConfig returns the logged config with the replay and the fields of
+// config that are not logged.
+func (inputLog *InputLog) replayConfig(config Config) Config {
+	logged := *inputLog.Config
+	logged.Replay = config.Replay
+	logged.Rules = config.Rules
+	logged.Script = config.Script
+	logged.Logger = config.Logger
+	return logged
+}
+
+// CreateInputLogger creates (or truncates) the input log at path and writes
+// the seed and the config of header to it. Its events are ignored.
+func CreateInputLogger(path string, header InputLog) (*InputLogger, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := header.writeHeader(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &InputLogger{file: file}, nil
+}
+
+// Log appends the event to the log. A nil logger discards all events.
+func (logger *InputLogger) Log(event InputEvent) error {
+	if logger == nil {
+		return nil
+	}
+	_, err := fmt.Fprintf(logger.file, "%d %s\n", event.Tick, event.Input)
+	return err
+}
+
+// Close closes the underlying file.
+func (logger *InputLogger) Close() error {
+	if logger == nil {
+		return nil
+	}
+	return logger.file.Close()
+}
+
+// ReadInputLog parses an input log written by an InputLogger.
+func ReadInputLog(r io.Reader) (InputLog, error) {
+	var inputLog InputLog
+	// the config is too long for the default buffer of a scanner
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	line := 0
+	seeded := false
+	for scanner.Scan() {
+		line += 1
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(text, " ")
+		value = strings.TrimSpace(value)
+		if !seeded {
+			if name != "seed" {
+				return inputLog, fmt.Errorf("line %d: missing seed header", line)
+			}
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return inputLog, fmt.Errorf("line %d: %w", line, err)
+			}
+			inputLog.Seed = seed
+			seeded = true
+			continue
+		}
+		if len(inputLog.Events) == 0 && !isTick(name) {
+			if err := inputLog.readHeader(name, value); err != nil {
+				return inputLog, fmt.Errorf("line %d: %w", line, err)
+			}
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return inputLog, fmt.Errorf("line %d: expected two fields", line)
+		}
+		tick, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return inputLog, fmt.Errorf("line %d: %w", line, err)
+		}
+		if _, ok := directionInputs[strings.TrimPrefix(fields[1], InputRival)]; !ok && fields[1] != InputPause {
+			return inputLog, fmt.Errorf("line %d: unknown input %q", line, fields[1])
+		}
+		inputLog.Events = append(inputLog.Events, InputEvent{Tick: tick, Input: fields[1]})
+	}
+	if err := scanner.Err(); err != nil {
+		return inputLog, err
+	}
+	if !seeded {
+		return inputLog, fmt.Errorf("empty input log")
+	}
+	return inputLog, nil
+}
+
+// isTick reports whether field is the tick of an event rather than the name
+// of a header.
+func isTick(field string) bool {
+	_, err := strconv.Atoi(field)
+	return err == nil
+}
+
+// ReadInputLogFile reads the input log stored at path.
+func ReadInputLogFile(path string) (InputLog, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return InputLog{}, err
+	}
+	defer file.Close()
+	return ReadInputLog(file)
+}
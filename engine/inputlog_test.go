@@ -0,0 +1,106 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInputLoggerHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header InputLog
+	}{
+		{"seed only", InputLog{Seed: 7}},
+		{"config", InputLog{Seed: 7, Config: &Config{XDim: 12, YDim: 10, ObstacleCount: 5, FoodScript: []Pos{{X: 1, Y: 2}}}}},
+		{"full", InputLog{Seed: -3, Config: &Config{XDim: 20, YDim: 15, Bounds: BoundsWalls, Lives: 2}, Delay: 120 * time.Millisecond, Rules: "zen"}},
+	}
+	events := []InputEvent{{Tick: 2, Input: InputNorth}, {Tick: 5, Input: InputPause}, {Tick: 9, Input: InputRival + InputWest}}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "inputs.log")
+			logger, err := CreateInputLogger(path, test.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, event := range events {
+				if err := logger.Log(event); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := logger.Close(); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReadInputLogFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := test.header
+			want.Events = events
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("read %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestReadInputLogErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+		want string
+	}{
+		{"empty", "", "empty input log"},
+		{"missing seed", "size 10x10\n", "missing seed header"},
+		{"blank lines", "\n\n", "empty input log"},
+		{"unknown header", "seed 1\ncolor red\n", "unknown header"},
+		{"bad config", "seed 1\nconfig {\"XDim\": \n", "line 2"},
+		{"bad delay", "seed 1\ndelay fast\n", "line 2"},
+		{"header after events", "seed 1\n3 north\ndelay 1s\n", "line 3"},
+		{"three fields", "seed 1\n3 north east\n", "expected two fields"},
+		{"unknown input", "seed 1\n3 up\n", "unknown input"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ReadInputLog(strings.NewReader(test.log))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("ReadInputLog = %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestReadInputLogBlankLines(t *testing.T) {
+	got, err := ReadInputLog(strings.NewReader("\n  \nseed 4\n\nrules zen\n2 west\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := InputLog{Seed: 4, Rules: "zen", Events: []InputEvent{{Tick: 2, Input: InputWest}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("read %+v, want %+v", got, want)
+	}
+}
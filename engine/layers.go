@@ -0,0 +1,73 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// MaxLayers is the number of layers a grid can be stacked to.
+const MaxLayers = 3
+
+// layers returns the number of stacked layers of the grid, at least one.
+func (config Config) layers() int {
+	if config.Layers < 1 {
+		return 1
+	}
+	return config.Layers
+}
+
+// cellIndex returns the index of pos in a slice with a value per cell of all
+// layers of the grid.
+func (game *Game) cellIndex(pos Pos) int {
+	return (pos.Layer*game.YDim+pos.Y)*game.XDim + pos.X
+}
+
+// PlaceLadders connects every two stacked layers of the grid with count
+// ladders, at least one. A ladder is a portal between the same cell of both
+// layers, so a head that moves onto it leaves it on the other layer, see
+// NextPos.
+func (game *Game) PlaceLadders(count int) error {
+	if count < 1 {
+		count = 1
+	}
+	for layer := 0; layer+1 < game.layers(); layer++ {
+		occupied := make(map[Pos]bool)
+		for _, pos := range game.Occupied() {
+			occupied[pos] = true
+		}
+		cells, err := game.Allocate(count, func(pos Pos) bool {
+			return pos.Layer == layer && !occupied[Pos{X: pos.X, Y: pos.Y, Layer: layer + 1}]
+		})
+		if err != nil {
+			return err
+		}
+		for _, cell := range cells {
+			game.portals = append(game.portals, [2]Pos{cell, {X: cell.X, Y: cell.Y, Layer: layer + 1}})
+		}
+	}
+	return nil
+}
+
+// IsLadder reports whether pos is an end of a ladder, a portal to another
+// layer.
+func (game *Game) IsLadder(pos Pos) bool {
+	exit, ok := game.PortalExit(pos)
+	return ok && exit.Layer != pos.Layer
+}
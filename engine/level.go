@@ -0,0 +1,197 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Level is a fixed board layout read from an ASCII map. Every line of the map
+// is a row of the board:
+//
+//	#  wall
+//	.  floor, a space is floor as well
+//	S  start, the head of the snail which faces east
+//	0-9  portal, a digit marks both ends of a portal, see NextPos
+//
+// Shorter lines are padded with floor.
+type Level struct {
+	Width   int
+	Height  int
+	Walls   []Pos
+	Start   Pos
+	Portals [][2]Pos
+}
+
+// ParseLevel reads a level from r.
+func ParseLevel(r io.Reader) (*Level, error) {
+	var rows []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		rows = append(rows, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for len(rows) > 0 && strings.TrimSpace(rows[len(rows)-1]) == "" {
+		rows = rows[:len(rows)-1]
+	}
+	level := &Level{Height: len(rows)}
+	for _, row := range rows {
+		if len(row) > level.Width {
+			level.Width = len(row)
+		}
+	}
+	if level.Width < 3 || level.Height < 1 {
+		return nil, fmt.Errorf("invalid level dimensions %dx%d", level.Width, level.Height)
+	}
+	starts := 0
+	var portals [10][]Pos
+	for y, row := range rows {
+		for x, c := range row {
+			pos := Pos{X: x, Y: y}
+			switch {
+			case c == '#':
+				level.Walls = append(level.Walls, pos)
+			case c == '.' || c == ' ':
+			case c == 'S':
+				level.Start = pos
+				starts += 1
+			case c >= '0' && c <= '9':
+				portals[c-'0'] = append(portals[c-'0'], pos)
+			default:
+				return nil, fmt.Errorf("invalid cell %q at %d,%d", c, x, y)
+			}
+		}
+	}
+	if starts != 1 {
+		return nil, fmt.Errorf("the level needs exactly one start, found %d", starts)
+	}
+	for digit, ends := range portals {
+		if len(ends) == 0 {
+			continue
+		}
+		if len(ends) != 2 {
+			return nil, fmt.Errorf("portal %d needs exactly two ends, found %d", digit, len(ends))
+		}
+		level.Portals = append(level.Portals, [2]Pos{ends[0], ends[1]})
+	}
+	return level, nil
+}
+
+// ReadLevelFile reads a level from the file at path.
+func ReadLevelFile(path string) (*Level, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	level, err := ParseLevel(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return level, nil
+}
+
+// Blocked reports whether pos is a wall or a portal, the body of the snail
+// may not start on either.
+func (level *Level) Blocked(pos Pos) bool {
+	for _, wall := range level.Walls {
+		if wall == pos {
+			return true
+		}
+	}
+	for _, portal := range level.Portals {
+		if portal[0] == pos || portal[1] == pos {
+			return true
+		}
+	}
+	return false
+}
+
+// StartSnail returns the snail on the start of the level. A start close to
+// the left edge wraps the body around it.
+func (level *Level) StartSnail() Snail {
+	snail := InitSnail(level.Width, level.Height)
+	for i := range snail.Body {
+		x := level.Start.X - len(snail.Body) + 1 + i
+		snail.Body[i] = Pos{X: (x + level.Width) % level.Width, Y: level.Start.Y}
+	}
+	return snail
+}
+
+// CheckStart returns an error if the body of the snail on the start of the
+// level would be on a wall or a portal, or would have to wrap around the
+// edge although bounds do not let it.
+func (level *Level) CheckStart(bounds Bounds) error {
+	body := level.StartSnail().Body
+	if level.Start.X < len(body)-1 && bounds != BoundsWrap {
+		return fmt.Errorf("the start at %d,%d leaves no room for the body", level.Start.X, level.Start.Y)
+	}
+	for _, pos := range body[:len(body)-1] {
+		if level.Blocked(pos) {
+			return fmt.Errorf("the start at %d,%d leaves no room for the body", level.Start.X, level.Start.Y)
+		}
+	}
+	return nil
+}
+
+// CheckConnected returns an error if a floor cell of the level cannot be
+// reached from its start, food placed there could never be eaten.
+func (game *Game) CheckConnected() error {
+	blocked := make(map[Pos]bool, len(game.obstacles))
+	for _, pos := range game.obstacles {
+		blocked[pos] = true
+	}
+	for _, pos := range game.Portals() {
+		blocked[pos] = true
+	}
+	start := game.snail.GetHead()
+	seen := map[Pos]bool{start: true}
+	queue := []Pos{start}
+	for len(queue) > 0 {
+		pos := queue[0]
+		queue = queue[1:]
+		for _, dir := range Directions {
+			next, wrapped := game.NextPos(pos, dir)
+			if wrapped && game.Bounds != BoundsWrap || blocked[next] || seen[next] {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	for y := 0; y < game.YDim; y++ {
+		for x := 0; x < game.XDim; x++ {
+			pos := Pos{X: x, Y: y}
+			if !blocked[pos] && !seen[pos] {
+				return fmt.Errorf("the cell %d,%d cannot be reached from the start", x, y)
+			}
+		}
+	}
+	return nil
+}
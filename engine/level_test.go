@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel(strings.NewReader("#####\n#1.S#\n# 1\n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Level{
+		Width:  5,
+		Height: 3,
+		Walls:  []Pos{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 1}, {X: 4, Y: 1}, {X: 0, Y: 2}},
+		Start:  Pos{X: 3, Y: 1},
+		// the last row is padded with floor
+		Portals: [][2]Pos{{{X: 1, Y: 1}, {X: 2, Y: 2}}},
+	}
+	if !reflect.DeepEqual(level, want) {
+		t.Errorf("parsed %+v, want %+v", level, want)
+	}
+}
+
+func TestParseLevelErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		level string
+		want  string
+	}{
+		{"empty", "", "invalid level dimensions 0x0"},
+		{"too narrow", "S.\n", "invalid level dimensions 2x1"},
+		{"invalid cell", "..S\n.x.\n", `invalid cell 'x' at 1,1`},
+		{"no start", "...\n", "exactly one start, found 0"},
+		{"two starts", "..S\n..S\n", "exactly one start, found 2"},
+		{"one portal end", "..S1\n", "portal 1 needs exactly two ends, found 1"},
+		{"three portal ends", "2.S2\n..2.\n", "portal 2 needs exactly two ends, found 3"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseLevel(strings.NewReader(test.level))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("ParseLevel = %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestLevelStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  string
+		bounds Bounds
+		// body is the snail on the start, nil if the start is rejected
+		body []Pos
+	}{
+		{"room", "....S\n", BoundsWrap, []Pos{{X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}}},
+		{"wrapped body", ".S...\n", BoundsWrap, []Pos{{X: 4, Y: 0}, {X: 0, Y: 0}, {X: 1, Y: 0}}},
+		{"edge with walls", ".S...\n", BoundsWalls, nil},
+		{"edge with bounce", "S....\n", BoundsBounce, nil},
+		{"wall behind", ".#S..\n", BoundsWrap, nil},
+		{"wrapped onto a wall", "S...#\n", BoundsWrap, nil},
+		{"portal behind", "1.S.1\n", BoundsWrap, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			level, err := ParseLevel(strings.NewReader(test.level))
+			if err != nil {
+				t.Fatal(err)
+			}
+			game, err := New(Config{Level: level, Bounds: test.bounds, Seed: 1})
+			if test.body == nil {
+				if err == nil || !strings.Contains(err.Error(), "leaves no room for the body") {
+					t.Errorf("New = %v, want the start to be rejected", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := game.Snail().Body; !reflect.DeepEqual(got, test.body) {
+				t.Errorf("snail %v, want %v", got, test.body)
+			}
+		})
+	}
+}
+
+func TestCheckConnected(t *testing.T) {
+	tests := []struct {
+		name   string
+		level  string
+		bounds Bounds
+		ok     bool
+	}{
+		{"open", "...S.\n.....\n", BoundsWalls, true},
+		{"walled off", "..S#.\n...#.\n", BoundsWalls, false},
+		{"around the edge", "..S#.\n...#.\n", BoundsWrap, true},
+		{"through a portal", "..S#1\n.1.#.\n", BoundsWalls, true},
+		{"enclosed", ".....\n..S..\n.###.\n.#.#.\n.###.\n", BoundsWrap, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			level, err := ParseLevel(strings.NewReader(test.level))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = New(Config{Level: level, Bounds: test.bounds, Seed: 1})
+			if test.ok && err != nil {
+				t.Errorf("New = %v, want the level to be connected", err)
+			}
+			if !test.ok && (err == nil || !strings.Contains(err.Error(), "cannot be reached from the start")) {
+				t.Errorf("New = %v, want unreachable floor to be rejected", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// respawnGrace is the number of ticks collisions are ignored after the snail
+// respawned.
+const respawnGrace = 10
+
+// Respawn uses up a life to put the snail back on its start position with
+// its start length, keeping the score. The snail cannot collide for
+// respawnGrace ticks afterwards. It returns false if there is no life left.
+func (game *Game) Respawn() bool {
+	if game.livesLeft < 1 {
+		return false
+	}
+	game.livesLeft -= 1
+	game.snail = Snail{
+		Body:      append([]Pos(nil), game.start.Body...),
+		Direction: game.start.Direction,
+		OldTail:   game.start.OldTail,
+	}
+	game.pendingGrowth = 0
+	game.lagLeft = 0
+	game.stretch = 0
+	game.graceLeft = respawnGrace
+	game.respawned = true
+	game.history.Reset()
+	// the food is scored from the start position and losing a life breaks
+	// the combo
+	game.scorer.ResetSteps()
+	game.scorer.BreakCombo()
+	game.scorer.OldHeadPos = game.snail.GetHead()
+	game.scorer.OldFoodPos = game.food
+	game.Debug("snail respawned", "lives", game.livesLeft)
+	return true
+}
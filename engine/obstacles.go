@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// obstacleRetries is the number of layouts generated for a given number of
+// obstacles before the number is reduced.
+const obstacleRetries = 10
+
+// Obstacles returns the number of obstacles scattered on all layers of the
+// grid.
+func (config Config) Obstacles() int {
+	if config.ObstacleDensity > 0 {
+		return config.XDim * config.YDim * config.layers() * config.ObstacleDensity / 100
+	}
+	return config.ObstacleCount
+}
+
+// PlaceObstacles scatters count obstacles on cells not covered by the snail.
+// Layouts that leave the snail without a safe first move are regenerated and
+// after obstacleRetries failed attempts the number of obstacles is reduced.
+func (game *Game) PlaceObstacles(count int) {
+	for ; count > 0; count-- {
+		for attempt := 0; attempt < obstacleRetries; attempt++ {
+			game.obstacles = nil
+			cells, err := game.Allocate(count, nil)
+			if err != nil {
+				// not enough room, retrying does not help
+				break
+			}
+			game.obstacles = cells
+			if game.HasSafeMove() {
+				return
+			}
+		}
+	}
+	game.obstacles = nil
+}
+
+// HasSafeMove reports whether the snail can make at least one move without
+// dying.
+func (game *Game) HasSafeMove() bool {
+	for _, dir := range Directions {
+		if !game.IsValidNewDir(dir) {
+			continue
+		}
+		if _, ok := game.SafeNextPos(dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// HitsObstacle reports whether the head is on an obstacle.
+func (game *Game) HitsObstacle() bool {
+	return game.CheckCollisions(game.snail.GetHead(), game.obstacles)
+}
@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "testing"
+
+func TestPlaceObstacles(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  int
+		height int
+		bounds Bounds
+		count  int
+		// max is the most obstacles that may be placed, exact the count that
+		// must be placed
+		max   int
+		exact bool
+	}{
+		{"roomy", 10, 10, BoundsWrap, 5, 5, true},
+		{"none", 10, 10, BoundsWrap, 0, 0, true},
+		// the snail starts at the east wall of a 5x3 grid, obstacles on all
+		// other cells box it in
+		{"boxed in", 5, 3, BoundsWalls, 12, 11, false},
+		{"more than cells", 5, 3, BoundsWalls, 40, 11, false},
+		{"crowded", 5, 3, BoundsWrap, 10, 10, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 20; seed++ {
+				game, err := New(Config{XDim: test.width, YDim: test.height, Seed: seed, Bounds: test.bounds})
+				if err != nil {
+					t.Fatal(err)
+				}
+				game.PlaceObstacles(test.count)
+				placed := len(game.Obstacles())
+				if placed > test.max || test.exact && placed != test.max {
+					t.Fatalf("seed %d: placed %d obstacles, want %d", seed, placed, test.max)
+				}
+				if !game.HasSafeMove() {
+					t.Fatalf("seed %d: the snail is boxed in by %v", seed, game.Obstacles())
+				}
+				for _, pos := range game.Obstacles() {
+					if game.CheckCollisions(pos, game.Snail().Body) {
+						t.Fatalf("seed %d: obstacle on the snail at %v", seed, pos)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// PickupKind is the kind of an item on the board besides the food.
+type PickupKind int
+
+const (
+	// PickupPoison shrinks the snail by poisonShrink segments and costs
+	// PoisonPenalty points.
+	PickupPoison PickupKind = iota
+	// PickupGolden is rare food worth GoldenBonus points.
+	PickupGolden
+	// PickupGhost lets the snail pass through itself for a while.
+	PickupGhost
+	// PickupSlow slows the game down for a while.
+	PickupSlow
+	// PickupShrink shrinks the snail by shrinkSegments segments.
+	PickupShrink
+	// PickupDouble doubles the points for food for a while.
+	PickupDouble
+)
+
+// PowerUps are the kinds of pickups spawned by PowerUpChance.
+var PowerUps = []PickupKind{PickupGhost, PickupSlow, PickupShrink, PickupDouble}
+
+// effectTicks are the number of ticks the effects of the timed pickups last.
+var effectTicks = map[PickupKind]int{
+	PickupGhost:  30,
+	PickupSlow:   40,
+	PickupDouble: 50,
+}
+
+// shrinkSegments is the number of segments a PickupShrink takes off.
+const shrinkSegments = 3
+
+// powerUpTTL is the number of ticks a power-up stays on the board.
+const powerUpTTL = 40
+
+// Pickup is an item on the board that disappears after TicksLeft ticks.
+type Pickup struct {
+	Kind      PickupKind
+	Pos       Pos
+	TicksLeft int
+}
+
+// PoisonPenalty are the points lost by eating poison.
+const PoisonPenalty = 10
+
+// poisonShrink is the number of segments the snail loses by eating poison.
+const poisonShrink = 2
+
+// poisonTTL is the number of ticks poison stays on the board.
+const poisonTTL = 40
+
+// GoldenBonus are the points for golden food.
+const GoldenBonus = 50
+
+// goldenTTL is the number of ticks golden food stays on the board.
+const goldenTTL = 25
+
+// pickupPoints returns the points the snail gets for a pickup of kind.
+func pickupPoints(kind PickupKind) int {
+	switch kind {
+	case PickupPoison:
+		return -PoisonPenalty
+	case PickupGolden:
+		return GoldenBonus
+	}
+	return 0
+}
+
+// SpawnPickups places poison, golden food and one of the PowerUps on free
+// cells with a chance of PoisonChance, GoldenChance and PowerUpChance percent.
+// It is called whenever food was eaten. There is at most one golden food at a
+// time.
+func (game *Game) SpawnPickups() {
+	if game.PoisonChance > 0 && game.rng.Intn(100) < game.PoisonChance {
+		game.spawnPickup(PickupPoison, poisonTTL)
+	}
+	if _, ok := game.Golden(); !ok && game.GoldenChance > 0 && game.rng.Intn(100) < game.GoldenChance {
+		game.spawnPickup(PickupGolden, goldenTTL)
+	}
+	if game.PowerUpChance > 0 && game.rng.Intn(100) < game.PowerUpChance {
+		game.spawnPickup(PowerUps[game.rng.Intn(len(PowerUps))], powerUpTTL)
+	}
+}
+
+// Golden returns the golden food and false if there is none.
+func (game *Game) Golden() (Pickup, bool) {
+	for _, pickup := range game.pickups {
+		if pickup.Kind == PickupGolden {
+			return pickup, true
+		}
+	}
+	return Pickup{}, false
+}
+
+// spawnPickup places a pickup of kind for ttl ticks on a free cell that is
+// not the food. Without room the pickup is skipped.
+func (game *Game) spawnPickup(kind PickupKind, ttl int) {
+	cells, err := game.Allocate(1, func(pos Pos) bool { return pos != game.food })
+	if err != nil {
+		return
+	}
+	game.pickups = append(game.pickups, Pickup{Kind: kind, Pos: cells[0], TicksLeft: ttl})
+	game.Debug("pickup placed", "kind", kind, "x", cells[0].X, "y", cells[0].Y)
+}
+
+// CollectPickups applies the pickup the head is on and removes it.
+func (game *Game) CollectPickups() {
+	head := game.snail.GetHead()
+	for index, pickup := range game.pickups {
+		if pickup.Pos != head {
+			continue
+		}
+		game.pickups = append(game.pickups[:index:index], game.pickups[index+1:]...)
+		game.applyPickup(pickup.Kind)
+		return
+	}
+}
+
+// applyPickup grants the effect and the points of a pickup of kind. Timed
+// effects start over if they are still active.
+func (game *Game) applyPickup(kind PickupKind) {
+	switch kind {
+	case PickupPoison:
+		game.shrink(poisonShrink)
+	case PickupShrink:
+		game.shrink(shrinkSegments)
+	}
+	if ticks, ok := effectTicks[kind]; ok {
+		if game.effects == nil {
+			game.effects = make(map[PickupKind]int)
+		}
+		game.effects[kind] = ticks
+	}
+	game.scorer.AddPoints(pickupPoints(kind))
+	game.Debug("pickup collected", "kind", kind, "score", game.scorer.Score)
+}
+
+// shrink takes up to segments segments off the tail, the head is kept.
+func (game *Game) shrink(segments int) {
+	cut := len(game.snail.Body) - 1
+	if cut > segments {
+		cut = segments
+	}
+	game.snail.Body = game.snail.Body[cut:]
+	game.stretch = 0
+}
+
+// Active reports whether the effect of a pickup of kind is active.
+func (game *Game) Active(kind PickupKind) bool {
+	return game.effects[kind] > 0
+}
+
+// Effects returns the number of ticks left of every active effect.
+func (game *Game) Effects() map[PickupKind]int {
+	effects := make(map[PickupKind]int, len(game.effects))
+	for kind, ticks := range game.effects {
+		effects[kind] = ticks
+	}
+	return effects
+}
+
+// ageEffects counts down the ticks left of effects and removes the effects
+// that ran out.
+func ageEffects(effects map[PickupKind]int) {
+	for kind := range effects {
+		effects[kind] -= 1
+		if effects[kind] < 1 {
+			delete(effects, kind)
+		}
+	}
+}
+
+// AgePickups removes the pickups whose time ran out.
+func (game *Game) AgePickups() {
+	kept := game.pickups[:0]
+	for _, pickup := range game.pickups {
+		pickup.TicksLeft -= 1
+		if pickup.TicksLeft > 0 {
+			kept = append(kept, pickup)
+		}
+	}
+	game.pickups = kept
+}
+
+// Pickups returns a copy of the pickups on the board.
+func (game *Game) Pickups() []Pickup {
+	return append([]Pickup(nil), game.pickups...)
+}
@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Place returns count distinct random cells of a width x height grid that are
+// not in occupied and for which allowed holds, if it is set. The candidates
+// are enumerated in row-major order, row by row from the top and each row
+// from left to right, so the same random numbers always select the same cells
+// for the same occupancy. ErrNoFreeCell is returned if there are fewer than
+// count candidates.
+func Place(rng *rand.Rand, width, height, count int, occupied []Pos, allowed func(Pos) bool) ([]Pos, error) {
+	return PlaceLayers(rng, width, height, 1, count, occupied, allowed)
+}
+
+// PlaceLayers is like Place on a grid of layers stacked width x height grids.
+// The layers are enumerated from the first one.
+func PlaceLayers(rng *rand.Rand, width, height, layers, count int, occupied []Pos, allowed func(Pos) bool) ([]Pos, error) {
+	taken := make(map[Pos]bool, len(occupied))
+	for _, pos := range occupied {
+		taken[pos] = true
+	}
+	var free []Pos
+	for layer := 0; layer < layers; layer++ {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				pos := Pos{X: x, Y: y, Layer: layer}
+				if !taken[pos] && (allowed == nil || allowed(pos)) {
+					free = append(free, pos)
+				}
+			}
+		}
+	}
+	if count > len(free) {
+		return nil, fmt.Errorf("%w: %d cells requested, %d free", ErrNoFreeCell, count, len(free))
+	}
+	for i := 0; i < count; i++ {
+		j := i + rng.Intn(len(free)-i)
+		free[i], free[j] = free[j], free[i]
+	}
+	return free[:count], nil
+}
+
+// Occupied returns the cells covered by the snail, a rival, an obstacle, a
+// portal, a pickup or the power-up.
+func (game *Game) Occupied() []Pos {
+	occupied := make([]Pos, 0, len(game.snail.Body)+len(game.obstacles)+1)
+	occupied = append(occupied, game.snail.Body...)
+	for _, rival := range game.rivals {
+		occupied = append(occupied, rival.Snail.Body...)
+	}
+	occupied = append(occupied, game.obstacles...)
+	occupied = append(occupied, game.Portals()...)
+	for _, pickup := range game.pickups {
+		occupied = append(occupied, pickup.Pos)
+	}
+	if game.powerUp != nil {
+		occupied = append(occupied, *game.powerUp)
+	}
+	return occupied
+}
+
+// Allocate returns count distinct random cells of the grid that are not
+// Occupied and for which allowed holds, if it is set.
+func (game *Game) Allocate(count int, allowed func(Pos) bool) ([]Pos, error) {
+	return PlaceLayers(game.rng, game.XDim, game.YDim, game.layers(), count, game.Occupied(), allowed)
+}
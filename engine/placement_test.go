@@ -0,0 +1,119 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestPlaceOrder(t *testing.T) {
+	// the middle row of a 3x3 grid is occupied, which leaves the cells
+	// 0,0 1,0 2,0 0,2 1,2 2,2 in this order
+	occupied := []Pos{{X: 0, Y: 1}, {X: 1, Y: 1}, {X: 2, Y: 1}}
+	reversed := []Pos{{X: 2, Y: 1}, {X: 1, Y: 1}, {X: 0, Y: 1}}
+	leftColumn := func(pos Pos) bool { return pos.X == 0 }
+	tests := []struct {
+		name    string
+		seed    int64
+		count   int
+		allowed func(Pos) bool
+		want    []Pos
+	}{
+		{"last cell", 1, 1, nil, []Pos{{X: 2, Y: 2}}},
+		{"bottom row", 2, 1, nil, []Pos{{X: 1, Y: 2}}},
+		{"two cells", 3, 2, nil, []Pos{{X: 1, Y: 2}, {X: 0, Y: 2}}},
+		{"all cells", 4, 6, nil, []Pos{{X: 1, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 2}, {X: 2, Y: 2}, {X: 0, Y: 0}, {X: 1, Y: 2}}},
+		{"allowed", 1, 1, leftColumn, []Pos{{X: 0, Y: 2}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, cells := range [][]Pos{occupied, reversed} {
+				got, err := Place(rand.New(rand.NewSource(test.seed)), 3, 3, test.count, cells, test.allowed)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(got, test.want) {
+					t.Errorf("occupied %v: placed on %v, want %v", cells, got, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestPlaceTooMany(t *testing.T) {
+	occupied := []Pos{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}}
+	tests := []struct {
+		name    string
+		count   int
+		allowed func(Pos) bool
+	}{
+		{"more than free", 7, nil},
+		{"more than allowed", 2, func(pos Pos) bool { return pos == Pos{X: 1, Y: 1} }},
+		{"none allowed", 1, func(Pos) bool { return false }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cells, err := Place(rand.New(rand.NewSource(1)), 3, 3, test.count, occupied, test.allowed)
+			if !errors.Is(err, ErrNoFreeCell) {
+				t.Errorf("Place = %v, %v, want ErrNoFreeCell", cells, err)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, ObstacleCount: 10, PortalPairs: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	occupied := map[Pos]bool{game.Food(): true}
+	for _, pos := range game.Occupied() {
+		occupied[pos] = true
+	}
+	free := 100 - len(occupied)
+	for _, count := range []int{1, 10, free} {
+		cells, err := game.Allocate(count, func(pos Pos) bool { return pos != game.Food() })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cells) != count {
+			t.Fatalf("%d cells allocated, want %d", len(cells), count)
+		}
+		seen := map[Pos]bool{}
+		for _, pos := range cells {
+			if occupied[pos] || seen[pos] {
+				t.Fatalf("%v allocated twice or on an occupied cell", pos)
+			}
+			if pos.X < 0 || pos.X >= 10 || pos.Y < 0 || pos.Y >= 10 {
+				t.Fatalf("%v allocated outside of the grid", pos)
+			}
+			seen[pos] = true
+		}
+	}
+	if _, err := game.Allocate(free+1, func(pos Pos) bool { return pos != game.Food() }); !errors.Is(err, ErrNoFreeCell) {
+		t.Errorf("allocating %d of %d free cells: %v, want ErrNoFreeCell", free+1, free, err)
+	}
+}
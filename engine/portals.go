@@ -0,0 +1,83 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// PlacePortals places count pairs of portals on free cells, see NextPos.
+func (game *Game) PlacePortals(count int) error {
+	game.portals = nil
+	cells, err := game.Allocate(2*count, nil)
+	if err != nil {
+		return err
+	}
+	for index := 0; index < len(cells); index += 2 {
+		game.portals = append(game.portals, [2]Pos{cells[index], cells[index+1]})
+	}
+	return nil
+}
+
+// Portals returns both ends of every portal.
+func (game *Game) Portals() []Pos {
+	ends := make([]Pos, 0, 2*len(game.portals))
+	for _, portal := range game.portals {
+		ends = append(ends, portal[0], portal[1])
+	}
+	return ends
+}
+
+// PortalPairs returns the two linked ends of every portal.
+func (game *Game) PortalPairs() [][2]Pos {
+	return append([][2]Pos(nil), game.portals...)
+}
+
+// PortalExit returns the other end of the portal at pos.
+func (game *Game) PortalExit(pos Pos) (Pos, bool) {
+	for _, portal := range game.portals {
+		if portal[0] == pos {
+			return portal[1], true
+		}
+		if portal[1] == pos {
+			return portal[0], true
+		}
+	}
+	return Pos{}, false
+}
+
+// NextPos returns the cell the head moves to from pos in direction dir and
+// whether the move wrapped around an edge of the grid. A head that enters a
+// portal is emitted from the other end of it in the same direction, so the
+// ends of a portal are never occupied.
+func (game *Game) NextPos(pos Pos, dir Velocity) (Pos, bool) {
+	moving := Snail{Direction: dir}
+	next, wrapped := moving.NextPos(pos, game.XDim, game.YDim)
+	// a portal may emit the head right into another one
+	for hops := 0; hops < len(game.portals); hops++ {
+		exit, ok := game.PortalExit(next)
+		if !ok {
+			break
+		}
+		var exitWrapped bool
+		next, exitWrapped = moving.NextPos(exit, game.XDim, game.YDim)
+		wrapped = wrapped || exitWrapped
+	}
+	return next, wrapped
+}
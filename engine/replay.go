@@ -0,0 +1,257 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// RecordedTick is the state of a game right before the snail moves: the
+// body, the direction it is about to move in and the current food.
+type RecordedTick struct {
+	Body      []Pos
+	Direction Velocity
+	Food      Pos
+	Pickups   []Pickup `json:",omitempty"`
+	Rivals    [][]Pos  `json:",omitempty"`
+	// Respawned is set on the first tick after the snail lost a life.
+	Respawned bool `json:",omitempty"`
+	// Wandered is set if the food took a step since the last tick, see
+	// WanderFood.
+	Wandered bool `json:",omitempty"`
+	// Cut is the number of segments ZenRules cut off before the snail moved.
+	Cut int `json:",omitempty"`
+}
+
+// Recording is a complete game that can be verified with VerifyReplay. Its
+// Rules are the name the rules of the game are registered under, empty for
+// the classic ones.
+type Recording struct {
+	// Seed is the seed the game was played with, starting a game with it
+	// and the same inputs reproduces the recording.
+	Seed        int64
+	Width       int
+	Height      int
+	ScoreWeight int
+	WinBonus    int
+	Bounds      Bounds
+	EatRule     EatRule
+	FoodTTL     int
+	TTLPenalty  int
+	Portals     [][2]Pos       `json:",omitempty"`
+	Layers      int            `json:",omitempty"`
+	Lives       int            `json:",omitempty"`
+	Start       []Pos          `json:",omitempty"`
+	Rivals      []RivalControl `json:",omitempty"`
+	ScoreRule   ScoreRule      `json:",omitempty"`
+	ComboWindow int            `json:",omitempty"`
+	Scoring     string         `json:",omitempty"`
+	Rules       string         `json:",omitempty"`
+	Won         bool
+	Score       int
+	Ticks       []RecordedTick
+}
+
+// Record appends the current state of the game to the recording.
+func (rec *Recording) Record(game *Game) {
+	body := make([]Pos, len(game.snail.Body))
+	copy(body, game.snail.Body)
+	rec.Ticks = append(rec.Ticks, RecordedTick{
+		Body:      body,
+		Direction: game.snail.Direction,
+		Food:      game.food,
+		Pickups:   game.Pickups(),
+		Rivals:    game.rivalBodies(),
+		Respawned: game.respawned,
+		Wandered:  game.wandered,
+	})
+	game.respawned = false
+	game.wandered = false
+}
+
+// WriteFile stores the recording as json in the file at path.
+func (rec *Recording) WriteFile(path string) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// VerifyReplay reads a recording and recomputes its score from scratch using
+// the scoring logic. An error is returned if the recording is inconsistent or
+// the recomputed score differs from the recorded one.
+func VerifyReplay(r io.Reader) error {
+	var rec Recording
+	if err := json.NewDecoder(r).Decode(&rec); err != nil {
+		return fmt.Errorf("cannot read replay: %w", err)
+	}
+	if rec.Width < 1 || rec.Height < 1 {
+		return fmt.Errorf("invalid replay dimensions %dx%d", rec.Width, rec.Height)
+	}
+	if rec.ScoreWeight == 0 {
+		rec.ScoreWeight = DefaultScoreWeight
+	}
+	scorer := InitScorer(rec.Width, rec.Height, rec.ScoreWeight)
+	scorer.comboWindow = rec.ComboWindow
+	scorer.scoring = rec.Scoring
+	scorer.bounds = rec.Bounds
+	game := Game{Config: Config{XDim: rec.Width, YDim: rec.Height, Layers: rec.Layers, Bounds: rec.Bounds, EatRule: rec.EatRule}, portals: rec.Portals}
+	foods := 0
+	// placed is the tick on which the current food appeared
+	placed := 0
+	effects := make(map[PickupKind]int)
+	respawns := 0
+	for index, tick := range rec.Ticks {
+		if len(tick.Body) == 0 {
+			return fmt.Errorf("tick %d: empty snail body", index)
+		}
+		if err := game.ValidateBody(tick.Body); err != nil {
+			return fmt.Errorf("tick %d: %w", index, err)
+		}
+		head := tick.Body[len(tick.Body)-1]
+		if index == 0 {
+			scorer.OldHeadPos = head
+			scorer.OldFoodPos = tick.Food
+			scorer.Step()
+			continue
+		}
+		prev := rec.Ticks[index-1]
+		if tick.Respawned {
+			// a pickup on the start may already have shortened the respawned snail
+			if respawns >= rec.Lives || len(tick.Body) > len(rec.Start) || !slices.Equal(tick.Body, rec.Start[len(rec.Start)-len(tick.Body):]) {
+				return fmt.Errorf("tick %d: respawned at %v, expected the start", index, tick.Body)
+			}
+			// the snail died and respawned instead of moving
+			respawns += 1
+			scorer.ResetSteps()
+			scorer.BreakCombo()
+			scorer.OldHeadPos = head
+			scorer.OldFoodPos = prev.Food
+			scorer.Step()
+		} else if expected, _ := game.NextPos(prev.Body[len(prev.Body)-1], prev.Direction); expected != head {
+			return fmt.Errorf("tick %d: head at %v, expected %v", index, head, expected)
+		}
+		if len(tick.Rivals) > len(rec.Rivals) {
+			return fmt.Errorf("tick %d: %d rivals, expected %d", index, len(tick.Rivals), len(rec.Rivals))
+		}
+		rival := game.rivalEating(prev.Food, tick.Rivals)
+		if eats := game.EatsFood(prev.Food, tick.Body); eats || rival >= 0 && rec.Rivals[rival].Shared() {
+			length := len(tick.Body)
+			if !eats {
+				length = len(tick.Rivals[rival])
+			}
+			food, err := scorer.CalculateScore(length)
+			if err != nil {
+				return fmt.Errorf("tick %d: %w", index, err)
+			}
+			if rec.ScoreRule == ScoreSurvival {
+				scorer.Rescore(0)
+				food.Points = 0
+			}
+			if effects[PickupDouble] > 0 {
+				scorer.Rescore(2 * food.Points)
+			}
+			foods += 1
+			placed = index
+			scorer.OldHeadPos = head
+			scorer.OldFoodPos = tick.Food
+		} else if tick.Wandered {
+			// wandering food keeps its age, it is scored like moved food
+			scorer.ResetSteps()
+			scorer.OldHeadPos = head
+			scorer.OldFoodPos = tick.Food
+		} else if tick.Food != prev.Food {
+			// the food was moved because the snail stalled, it expired or a
+			// rival ate it
+			if rival < 0 && rec.FoodTTL > 0 && index-placed >= rec.FoodTTL {
+				scorer.Penalize(rec.TTLPenalty)
+			}
+			placed = index
+			scorer.ResetSteps()
+			scorer.OldHeadPos = head
+			scorer.OldFoodPos = tick.Food
+		}
+		for _, pickup := range prev.Pickups {
+			if pickup.Pos != head {
+				continue
+			}
+			scorer.AddPoints(pickupPoints(pickup.Kind))
+			if ticks, ok := effectTicks[pickup.Kind]; ok {
+				effects[pickup.Kind] = ticks
+			}
+		}
+		if tick.Cut > 0 {
+			// the head ran into the segment at tick.Cut-1 and ZenRules cut
+			// off the tail up to it
+			last := len(tick.Body) - 1
+			if rec.Rules != "zen" {
+				return fmt.Errorf("tick %d: %d segments cut off under the rules %q", index, tick.Cut, rec.Rules)
+			}
+			if tick.Cut > last || tick.Body[tick.Cut-1] != head {
+				return fmt.Errorf("tick %d: %d segments cut off, the head was not on the last of them", index, tick.Cut)
+			}
+			scorer.Penalize(tick.Cut * ZenCutPenalty)
+		}
+		scorer.Step()
+		if rec.ScoreRule == ScoreSurvival {
+			scorer.AddPoints(1)
+		}
+		ageEffects(effects)
+	}
+	if rec.Won && len(rec.Ticks) > 0 {
+		// the game ends before the snail moves on the last tick
+		scorer.Score += WinBonus(rec.WinBonus, foods, len(rec.Ticks)-1, rec.Width, rec.Height)
+	}
+	if scorer.Score != rec.Score {
+		return fmt.Errorf("score mismatch: recorded %d, recomputed %d", rec.Score, scorer.Score)
+	}
+	return nil
+}
+
+// VerifyReplayFile runs VerifyReplay on the recording stored at path.
+func VerifyReplayFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return VerifyReplay(file)
+}
+
+// ReadRecordingFile reads the recording stored at path.
+func ReadRecordingFile(path string) (Recording, error) {
+	var rec Recording
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rec, err
+	}
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return rec, fmt.Errorf("cannot read replay: %w", err)
+	}
+	return rec, nil
+}
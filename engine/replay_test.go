@@ -0,0 +1,141 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// playAutopilot plays a game configured by config with an Autopilot for at
+// most ticks ticks.
+func playAutopilot(t *testing.T, config Config, ticks int) *Game {
+	t.Helper()
+	game, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pilot Autopilot
+	for i := 0; i < ticks; i++ {
+		if err := game.ChangeDirection(pilot.NextDirection(game)); err != nil {
+			t.Fatal(err)
+		}
+		running, err := game.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !running {
+			game.EndGame()
+			break
+		}
+	}
+	return game
+}
+
+func TestVerifyReplay(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(rec *Recording)
+		want   string
+	}{
+		{"untouched", func(rec *Recording) {}, ""},
+		{"score", func(rec *Recording) { rec.Score += 1 }, "score mismatch"},
+		{"weight", func(rec *Recording) { rec.ScoreWeight *= 2 }, "score mismatch"},
+		{"head", func(rec *Recording) {
+			body := rec.Ticks[5].Body
+			body[len(body)-1].X = (body[len(body)-1].X + 2) % rec.Width
+		}, "tick 5"},
+		{"empty body", func(rec *Recording) { rec.Ticks[3].Body = nil }, "tick 3: empty snail body"},
+		{"dimensions", func(rec *Recording) { rec.Width = 0 }, "invalid replay dimensions"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := playAutopilot(t, Config{XDim: 10, YDim: 10, Seed: 5}, 200)
+			if game.FoodsEaten() == 0 {
+				t.Fatal("the autopilot ate no food")
+			}
+			rec := game.Recording()
+			test.tamper(&rec)
+			data, err := json.Marshal(rec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = VerifyReplay(bytes.NewReader(data))
+			switch {
+			case test.want == "" && err != nil:
+				t.Errorf("VerifyReplay = %v, want nil", err)
+			case test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)):
+				t.Errorf("VerifyReplay = %v, want an error containing %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestVerifyReplayZen(t *testing.T) {
+	for seed := int64(1); seed <= 20; seed++ {
+		game, err := New(Config{XDim: 8, YDim: 8, Seed: seed, Growth: 2, Rules: ZenRules{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// turning at random runs into the body often
+		rng := rand.New(rand.NewSource(seed))
+		for i := 0; i < 300; i++ {
+			if err := game.ChangeDirection(Directions[rng.Intn(len(Directions))]); err != nil {
+				t.Fatal(err)
+			}
+			running, err := game.Step()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !running {
+				break
+			}
+		}
+		game.EndGame()
+		rec := game.Recording()
+		cuts := 0
+		for _, tick := range rec.Ticks {
+			cuts += tick.Cut
+		}
+		if cuts == 0 {
+			t.Fatalf("seed %d: no segment was cut off", seed)
+		}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyReplay(bytes.NewReader(data)); err != nil {
+			t.Errorf("seed %d: VerifyReplay = %v, want nil", seed, err)
+		}
+		rec.Rules = ""
+		if data, err = json.Marshal(rec); err != nil {
+			t.Fatal(err)
+		}
+		if err := VerifyReplay(bytes.NewReader(data)); err == nil || !strings.Contains(err.Error(), "cut off") {
+			t.Errorf("seed %d: VerifyReplay of classic rules = %v, want an error about the cut", seed, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "fmt"
+
+// ReverseRules turn the game around: the snail starts long, usually with a
+// StartLength close to the size of the grid, every eaten food takes Growth
+// segments off its tail and the game is won once only the head is left.
+type ReverseRules struct {
+	DefaultRules
+}
+
+// OnEat shrinks the snail instead of growing it.
+func (ReverseRules) OnEat(game *Game) {
+	game.pendingGrowth -= game.growth()
+	game.lagLeft -= game.GrowthLag
+	segments := game.growth()
+	if segments < 1 {
+		segments = 1
+	}
+	game.shrink(segments)
+}
+
+// WinCondition holds once only the head is left or the objective is reached.
+func (ReverseRules) WinCondition(game *Game) bool {
+	return len(game.snail.Body) == 1 || game.ObjectiveReached()
+}
+
+// SerpentineSnail returns a snail of length segments laid out row by row from
+// the top-left of a width x height grid, turning at the end of every row.
+// The last row is left free, so the snail can move.
+func SerpentineSnail(width, height, length int) (Snail, error) {
+	if length > width*(height-1) {
+		return Snail{}, fmt.Errorf("a snail of %d segments does not fit on a %dx%d grid", length, width, height)
+	}
+	body := make([]Pos, length)
+	for index := range body {
+		row, col := index/width, index%width
+		if row%2 == 1 {
+			col = width - 1 - col
+		}
+		body[index] = Pos{X: col, Y: row}
+	}
+	head := body[length-1]
+	dir := EastDir
+	if head.Y%2 == 1 {
+		dir = WestDir
+	}
+	if next := (Pos{X: head.X + dir.X, Y: head.Y}); next.X < 0 || next.X >= width {
+		// the head is at the end of its row
+		dir = SouthDir
+	}
+	return Snail{Body: body, Direction: dir, OldTail: Pos{X: -1, Y: -1}}, nil
+}
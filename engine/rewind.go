@@ -0,0 +1,186 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// rewindDepth is the number of ticks kept for a rewind. A rewind returns to
+// the oldest of them.
+const rewindDepth = 5
+
+// GameSnapshot is the state of a game at the start of a tick that a rewind
+// returns to.
+type GameSnapshot struct {
+	Body          []Pos
+	Direction     Velocity
+	Food          Pos
+	Scorer        Scorer
+	PendingGrowth int
+	lagLeft       int
+	stretch       int
+	FoodsEaten    int
+	MovesLeft     int
+	Tick          int
+	Rivals        []Rival
+	Pickups       []Pickup
+	Effects       map[PickupKind]int
+	PowerUp       *Pos
+	Obstacles     []Pos
+	Event         *Event
+	nextEvent     int
+	foodAge       int
+	wanderAge     int
+	recorded      int
+}
+
+// SnapshotRing keeps the last rewindDepth snapshots of a game.
+type SnapshotRing struct {
+	snapshots [rewindDepth]GameSnapshot
+	next      int
+	count     int
+}
+
+// Push adds snapshot to the ring, replacing the oldest one if it is full.
+func (ring *SnapshotRing) Push(snapshot GameSnapshot) {
+	ring.snapshots[ring.next] = snapshot
+	ring.next = (ring.next + 1) % rewindDepth
+	if ring.count < rewindDepth {
+		ring.count += 1
+	}
+}
+
+// Oldest returns the oldest snapshot of the ring and false if it is empty.
+func (ring *SnapshotRing) Oldest() (GameSnapshot, bool) {
+	if ring.count == 0 {
+		return GameSnapshot{}, false
+	}
+	return ring.snapshots[(ring.next-ring.count+rewindDepth)%rewindDepth], true
+}
+
+// Reset removes all snapshots.
+func (ring *SnapshotRing) Reset() {
+	*ring = SnapshotRing{}
+}
+
+// TakeSnapshot stores the current state of the game for a rewind.
+func (game *Game) TakeSnapshot() {
+	body := make([]Pos, len(game.snail.Body))
+	copy(body, game.snail.Body)
+	scorer := game.scorer
+	scorer.Breakdown = append([]FoodScore(nil), game.scorer.Breakdown...)
+	rivals := game.Rivals()
+	for index := range rivals {
+		rivals[index].Scorer.Breakdown = append([]FoodScore(nil), rivals[index].Scorer.Breakdown...)
+	}
+	var event *Event
+	if game.event != nil {
+		copied := *game.event
+		copied.Walls = append([]Pos(nil), game.event.Walls...)
+		event = &copied
+	}
+	var powerUp *Pos
+	if game.powerUp != nil {
+		pos := *game.powerUp
+		powerUp = &pos
+	}
+	game.history.Push(GameSnapshot{
+		Body:          body,
+		Direction:     game.snail.Direction,
+		Food:          game.food,
+		Scorer:        scorer,
+		PendingGrowth: game.pendingGrowth,
+		lagLeft:       game.lagLeft,
+		stretch:       game.stretch,
+		FoodsEaten:    game.foodsEaten,
+		MovesLeft:     game.movesLeft,
+		Tick:          game.tick,
+		Rivals:        rivals,
+		Pickups:       game.Pickups(),
+		Effects:       game.Effects(),
+		PowerUp:       powerUp,
+		Obstacles:     game.Obstacles(),
+		Event:         event,
+		nextEvent:     game.nextEvent,
+		foodAge:       game.foodAge,
+		wanderAge:     game.wanderAge,
+		recorded:      len(game.recording.Ticks),
+	})
+}
+
+// Rewind uses up a rewind charge to return the game to the oldest snapshot,
+// including the rivals, the pickups, the effects and the events. It returns false if
+// there is no charge or snapshot.
+func (game *Game) Rewind() bool {
+	snapshot, ok := game.history.Oldest()
+	if game.rewinds < 1 || !ok {
+		return false
+	}
+	game.rewinds -= 1
+	game.history.Reset()
+	game.snail = Snail{Body: snapshot.Body, Direction: snapshot.Direction}
+	game.food = snapshot.Food
+	game.scorer = snapshot.Scorer
+	game.pendingGrowth = snapshot.PendingGrowth
+	game.lagLeft = snapshot.lagLeft
+	game.stretch = snapshot.stretch
+	game.foodsEaten = snapshot.FoodsEaten
+	game.movesLeft = snapshot.MovesLeft
+	game.tick = snapshot.Tick
+	game.rivals = snapshot.Rivals
+	game.pickups = snapshot.Pickups
+	game.effects = snapshot.Effects
+	game.obstacles = snapshot.Obstacles
+	game.event = snapshot.Event
+	game.nextEvent = snapshot.nextEvent
+	game.foodAge = snapshot.foodAge
+	game.wanderAge = snapshot.wanderAge
+	if snapshot.PowerUp == nil || game.powerUp != nil {
+		// a power-up collected since stays collected, its charge was used
+		// up by the rewind
+		game.powerUp = snapshot.PowerUp
+	}
+	// the tick of the snapshot is recorded again when the loop continues
+	game.recording.Ticks = game.recording.Ticks[:snapshot.recorded-1]
+	return true
+}
+
+// SpawnPowerUp places a rewind power-up on a free cell with a chance of
+// RewindChance percent, unless one is already on the board.
+func (game *Game) SpawnPowerUp() {
+	if game.RewindChance < 1 || game.powerUp != nil || game.rng.Intn(100) >= game.RewindChance {
+		return
+	}
+	cells, err := game.Allocate(1, func(pos Pos) bool { return pos != game.food })
+	if err != nil {
+		// no room, the power-up is skipped
+		return
+	}
+	game.powerUp = &cells[0]
+}
+
+// CollectPowerUp grants a rewind charge if the head is on the power-up.
+func (game *Game) CollectPowerUp() {
+	if game.powerUp == nil || game.snail.GetHead() != *game.powerUp {
+		return
+	}
+	game.rewinds += 1
+	game.powerUp = nil
+}
@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRewindOnDeath(t *testing.T) {
+	tests := []struct {
+		charged bool
+		// event raises a wall after the tick the rewind returns to
+		event bool
+	}{
+		{false, false},
+		{true, false},
+		{true, true},
+	}
+	for _, test := range tests {
+		charged := test.charged
+		game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, Bounds: BoundsWalls, EventInterval: 1000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		game.food = Pos{X: 0, Y: 0}
+		if charged {
+			// the power-up is collected on the way to the wall
+			game.powerUp = &Pos{X: 8, Y: 5}
+		}
+		rewound := false
+		for tick := 1; ; tick++ {
+			if tick > 20 {
+				t.Fatalf("charged %t: the snail did not die", charged)
+			}
+			if test.event && tick == 2 {
+				if err := game.startEvent(EventWall); err != nil {
+					t.Fatal(err)
+				}
+				if len(game.Obstacles()) == 0 {
+					t.Fatal("the event raised no wall")
+				}
+			}
+			rewinds := game.Rewinds()
+			running, err := game.Step()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if running && game.Rewinds() < rewinds {
+				rewound = true
+				if head := game.Head(); head.X >= 9 {
+					t.Errorf("charged %t: head at %v after the rewind, want it back from the wall", charged, head)
+				}
+				if _, ok := game.Event(); ok || len(game.Obstacles()) > 0 {
+					t.Errorf("charged %t event %t: obstacles %v after the rewind, want the event undone", charged, test.event, game.Obstacles())
+				}
+				continue
+			}
+			if !running {
+				break
+			}
+		}
+		if game.EndGame(); !game.Died() {
+			t.Errorf("charged %t: the game did not end with the death of the snail", charged)
+		}
+		if rewound != charged || game.Rewinds() != 0 {
+			t.Errorf("charged %t: rewound %t with %d charges left, want rewound %t with none", charged, rewound, game.Rewinds(), charged)
+		}
+	}
+}
+
+func TestRewindVersus(t *testing.T) {
+	game, err := New(Config{XDim: 20, YDim: 10, Seed: 1, Bounds: BoundsWalls, Versus: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.food = Pos{X: 0, Y: 0}
+	game.rewinds = 1
+	game.effects = map[PickupKind]int{PickupDouble: 20}
+	type state struct {
+		rivals  []Rival
+		effects map[PickupKind]int
+	}
+	// states are the states at the start of every tick
+	states := make(map[int]state)
+	for step := 1; game.Rewinds() > 0; step++ {
+		if step > 20 {
+			t.Fatal("the snail did not die")
+		}
+		states[game.Tick()] = state{game.Rivals(), game.Effects()}
+		running, err := game.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !running {
+			t.Fatal("the game ended without a rewind")
+		}
+	}
+	want, ok := states[game.Tick()]
+	if !ok || game.Tick() >= len(states)-1 {
+		t.Fatalf("tick %d after the rewind, want an earlier one", game.Tick())
+	}
+	if rivals := game.Rivals(); !reflect.DeepEqual(rivals[0].Snail.Body, want.rivals[0].Snail.Body) {
+		t.Errorf("rival at %v after the rewind, want %v", rivals[0].Snail.Body, want.rivals[0].Snail.Body)
+	}
+	if effects := game.Effects(); !reflect.DeepEqual(effects, want.effects) {
+		t.Errorf("effects %v after the rewind, want %v", effects, want.effects)
+	}
+}
@@ -0,0 +1,334 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "fmt"
+
+// RivalControl decides how a Rival is steered.
+type RivalControl int
+
+const (
+	// RivalMirror turns like the snail, mirrored vertically.
+	RivalMirror RivalControl = iota
+	// RivalAutopilot is steered by the computer along the shortest safe path
+	// to the food.
+	RivalAutopilot
+	// RivalPlayer is steered by a second player with TurnRival.
+	RivalPlayer
+)
+
+// Shared reports whether the food a rival eats scores for the player, other
+// rivals score on their own.
+func (control RivalControl) Shared() bool {
+	return control == RivalMirror
+}
+
+// Vital reports whether the game ends once a rival dies, other rivals are
+// taken off the grid.
+func (control RivalControl) Vital() bool {
+	return control != RivalAutopilot
+}
+
+// rivalControl returns the control of the rival added by Dual, Versus or
+// TwoPlayers and whether one of them is set. At most one of them may be set.
+func (config Config) rivalControl() (RivalControl, bool, error) {
+	controls := make([]RivalControl, 0, 1)
+	if config.Dual {
+		controls = append(controls, RivalMirror)
+	}
+	if config.Versus {
+		controls = append(controls, RivalAutopilot)
+	}
+	if config.TwoPlayers {
+		controls = append(controls, RivalPlayer)
+	}
+	if len(controls) > 1 {
+		return 0, false, fmt.Errorf("dual, versus and two players cannot be combined")
+	}
+	if len(controls) == 0 {
+		return 0, false, nil
+	}
+	return controls[0], true, nil
+}
+
+// Rival is a further snail on the grid next to the one of the player. Unless
+// its control is Shared, it scores with a Scorer of its own.
+type Rival struct {
+	Snail         Snail
+	Control       RivalControl
+	Scorer        Scorer
+	PendingGrowth int
+	FoodsEaten    int
+	Dead          bool
+}
+
+// MirrorSnail returns snail mirrored vertically on a grid of height rows.
+func MirrorSnail(snail Snail, height int) Snail {
+	body := make([]Pos, len(snail.Body))
+	for index, pos := range snail.Body {
+		body[index] = Pos{X: pos.X, Y: height - 1 - pos.Y}
+	}
+	return Snail{
+		Body:      body,
+		Direction: Velocity{X: snail.Direction.X, Y: -snail.Direction.Y},
+		OldTail:   Pos{X: -1, Y: -1},
+	}
+}
+
+// AddRival puts a rival with snail on the grid. The snail must not overlap
+// anything on the grid.
+func (game *Game) AddRival(snail Snail, control RivalControl) error {
+	for _, pos := range snail.Body {
+		if game.CheckCollisions(pos, game.Occupied()) {
+			return fmt.Errorf("rival overlaps the grid at %v", pos)
+		}
+	}
+	game.rivals = append(game.rivals, Rival{
+		Snail:   snail,
+		Control: control,
+		Scorer:  InitScorer(game.XDim, game.YDim, game.ScoreWeight),
+	})
+	game.rivals[len(game.rivals)-1].Scorer.scoring = game.Scoring
+	game.rivals[len(game.rivals)-1].Scorer.bounds = game.Bounds
+	return nil
+}
+
+// Rivals returns a copy of the rivals.
+func (game *Game) Rivals() []Rival {
+	rivals := make([]Rival, len(game.rivals))
+	for index, rival := range game.rivals {
+		rival.Snail.Body = append([]Pos(nil), rival.Snail.Body...)
+		rivals[index] = rival
+	}
+	return rivals
+}
+
+// rivalControls returns the RivalControl of every rival.
+func (game *Game) rivalControls() []RivalControl {
+	controls := make([]RivalControl, len(game.rivals))
+	for index, rival := range game.rivals {
+		controls[index] = rival.Control
+	}
+	return controls
+}
+
+// rivalBodies returns the bodies of the rivals, the ones of dead rivals are
+// empty.
+func (game *Game) rivalBodies() [][]Pos {
+	bodies := make([][]Pos, len(game.rivals))
+	for index, rival := range game.rivals {
+		bodies[index] = append([]Pos(nil), rival.Snail.Body...)
+	}
+	return bodies
+}
+
+// rivalsLength returns the number of segments of all rivals.
+func (game *Game) rivalsLength() int {
+	length := 0
+	for _, rival := range game.rivals {
+		length += len(rival.Snail.Body)
+	}
+	return length
+}
+
+// rivalEating returns the index of the first body of bodies that eats food,
+// or -1 if none does.
+func (game *Game) rivalEating(food Pos, bodies [][]Pos) int {
+	for index, body := range bodies {
+		if len(body) > 0 && game.EatsFood(food, body) {
+			return index
+		}
+	}
+	return -1
+}
+
+// FeedRivals lets the first rival that reaches the food eat it and reports
+// whether the game ends because there is no room for new food. The food of a
+// Shared rival is scored for the player.
+func (game *Game) FeedRivals() (bool, error) {
+	index := game.rivalEating(game.food, game.rivalBodies())
+	if index < 0 {
+		return false, nil
+	}
+	rival := &game.rivals[index]
+	rival.PendingGrowth += game.growth()
+	rival.FoodsEaten += 1
+	if rival.Control.Shared() {
+		food, err := game.scorer.CalculateScore(len(rival.Snail.Body))
+		if err != nil {
+			return false, err
+		}
+		if game.Active(PickupDouble) {
+			food.Points *= 2
+			game.scorer.Rescore(food.Points)
+		}
+		game.foodsEaten += 1
+		game.Debug("food eaten by rival", "rival", index, "points", food.Points, "score", game.scorer.Score)
+	} else {
+		food, err := rival.Scorer.CalculateScore(len(rival.Snail.Body))
+		if err != nil {
+			return false, err
+		}
+		game.scorer.ResetSteps()
+		game.Debug("food eaten by rival", "rival", index, "points", food.Points, "score", rival.Scorer.Score)
+	}
+	if game.WonGame() || game.BoardFull() {
+		return true, nil
+	}
+	if err := game.CreateFood(); err != nil {
+		return false, err
+	}
+	game.scorer.OldHeadPos = game.snail.GetHead()
+	game.scorer.OldFoodPos = game.food
+	return false, nil
+}
+
+// TurnRival turns the rival at index into dir if it is steered by RivalPlayer
+// and dir does not reverse it, and logs the input.
+func (game *Game) TurnRival(index int, dir Velocity) error {
+	if index < 0 || index >= len(game.rivals) {
+		return nil
+	}
+	rival := &game.rivals[index]
+	current := rival.Snail.Direction
+	if rival.Control != RivalPlayer || rival.Dead || dir.Equals(Velocity{X: -current.X, Y: -current.Y}) {
+		return nil
+	}
+	rival.Snail.Direction = dir
+	return game.LogInput(InputRival + DirectionInput(dir))
+}
+
+// SteerRivals turns every rival according to its RivalControl.
+func (game *Game) SteerRivals() {
+	for index := range game.rivals {
+		rival := &game.rivals[index]
+		switch rival.Control {
+		case RivalMirror:
+			rival.Snail.Direction = Velocity{X: game.snail.Direction.X, Y: -game.snail.Direction.Y}
+		case RivalAutopilot:
+			if !rival.Dead {
+				rival.Snail.Direction = game.pilotRival(rival)
+			}
+		}
+	}
+}
+
+// pilotRival returns the direction of the move of rival that is closest to
+// the food without running into a snail, an obstacle or around an edge that
+// does not wrap. The direction is kept if there is no such move.
+func (game *Game) pilotRival(rival *Rival) Velocity {
+	head, dir := rival.Snail.GetHead(), rival.Snail.Direction
+	body := rival.Snail.Body
+	if rival.PendingGrowth < 1 {
+		// the tail moves out of the way
+		body = body[1:]
+	}
+	best := dir
+	bestDistance := -1
+	for _, turn := range Directions {
+		if turn.X == -dir.X && turn.Y == -dir.Y {
+			continue
+		}
+		next, wrapped := game.NextPos(head, turn)
+		if wrapped && game.Bounds != BoundsWrap {
+			continue
+		}
+		if game.CheckCollisions(next, body) || game.CheckCollisions(next, game.snail.Body) || game.CheckCollisions(next, game.obstacles) {
+			continue
+		}
+		distance := game.PathLength(next, game.food)
+		if distance < 0 {
+			// the food is unreachable from here, but the move is still safe
+			distance = game.XDim * game.YDim
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best = turn
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// RivalsCollide resolves the collisions of the rivals with themselves, the
+// obstacles, lethal walls and the snail. A dead rival is taken off the grid.
+// It reports whether the game ends, because the snail or a Vital rival died,
+// see RivalOutcome. Collisions are ignored while a grace period is left.
+func (game *Game) RivalsCollide() bool {
+	if game.graceLeft > 0 {
+		return false
+	}
+	for index := range game.rivals {
+		rival := &game.rivals[index]
+		if rival.Dead {
+			continue
+		}
+		body := rival.Snail.Body
+		head := body[len(body)-1]
+		dies := game.CheckCollisions(head, body[:len(body)-1]) || game.CheckCollisions(head, game.obstacles)
+		if _, wrapped := game.NextPos(head, rival.Snail.Direction); wrapped && game.Bounds == BoundsWalls {
+			dies = true
+		}
+		switch outcome := game.ResolveSnailCollision(game.snail.Body, body, game.LongerWins); outcome {
+		case OutcomeFirstDies, OutcomeBothDie:
+			game.Debug("snail ran into a rival", "rival", index)
+			game.outcome = outcome
+			return true
+		case OutcomeSecondDies:
+			dies = true
+		}
+		if !dies {
+			continue
+		}
+		game.Debug("rival died", "rival", index)
+		rival.Dead = true
+		rival.Snail.Body = nil
+		if rival.Control.Vital() {
+			game.outcome = OutcomeSecondDies
+			return true
+		}
+	}
+	return false
+}
+
+// MoveRivals moves every living rival one cell forward.
+func (game *Game) MoveRivals() {
+	for index := range game.rivals {
+		rival := &game.rivals[index]
+		if rival.Dead {
+			continue
+		}
+		grow := rival.PendingGrowth > 0
+		if grow {
+			rival.PendingGrowth -= 1
+		}
+		next, _ := game.NextPos(rival.Snail.GetHead(), rival.Snail.Direction)
+		rival.Snail.MoveTo(next, grow)
+		if rival.Scorer.OldFoodPos != game.food {
+			// the food was placed anew, it is scored from here
+			rival.Scorer.ResetSteps()
+			rival.Scorer.OldHeadPos = rival.Snail.GetHead()
+			rival.Scorer.OldFoodPos = game.food
+		}
+		rival.Scorer.Step()
+	}
+}
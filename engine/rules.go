@@ -0,0 +1,214 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "strings"
+
+func (game *Game) CheckCollisions(posToCheck Pos, potentialCollision []Pos) bool {
+	for _, pos := range potentialCollision {
+		if pos == posToCheck {
+			return true
+		}
+	}
+	return false
+}
+
+// EatsFood reports whether a snail with the given body reaches the food at
+// food under the eat rule of the game.
+func (game *Game) EatsFood(food Pos, body []Pos) bool {
+	if game.CheckCollisions(food, body) {
+		return true
+	}
+	if game.EatRule != EatTouch || len(body) == 0 {
+		return false
+	}
+	return game.CheckCollisions(food, game.Adjacent(body[len(body)-1]))
+}
+
+// Stalled reports whether the snail took AntiStall moves more than needed to
+// reach the food, in which case the food is moved to prevent endless games.
+func (game *Game) Stalled() bool {
+	if game.AntiStall < 1 {
+		return false
+	}
+	return game.scorer.movesSinceLastInc-game.Distance(game.scorer.OldHeadPos, game.scorer.OldFoodPos) >= game.AntiStall
+}
+
+// FoodExpired reports whether the food was not eaten within FoodTTL ticks.
+func (game *Game) FoodExpired() bool {
+	return game.FoodTTL > 0 && game.foodAge >= game.FoodTTL
+}
+
+// WonGame reports whether the game is won under its RuleSet.
+func (game *Game) WonGame() bool {
+	return game.rules().WinCondition(game)
+}
+
+// BoardFull reports whether the snail and the rivals cover every cell
+// without an obstacle or a portal.
+func (game *Game) BoardFull() bool {
+	return game.XDim*game.YDim*game.layers()-len(game.obstacles)-len(game.Portals()) <= len(game.snail.Body)-game.stretch+game.rivalsLength()
+}
+
+// Growing reports whether the tail stays in place on the next move, either
+// because the snail grows, because its body stretches with GrowthLag or
+// because it leaves a trail with GrowTrail.
+func (game *Game) Growing() bool {
+	return game.GrowthRule == GrowTrail || game.pendingGrowth > 0 || game.lagLeft > 0
+}
+
+// ConsumeGrowth accounts for one move with the tail in place. Pending growth
+// is used up first, then the lag, which stretches the body by one segment
+// that the tail catches up with once the snail stops growing.
+func (game *Game) ConsumeGrowth() {
+	if game.pendingGrowth > 0 {
+		game.pendingGrowth -= 1
+	} else if game.lagLeft > 0 {
+		game.lagLeft -= 1
+		game.stretch += 1
+	}
+}
+
+// ObjectiveReached reports whether the required number of foods was eaten.
+func (game *Game) ObjectiveReached() bool {
+	return game.ObjectiveFoods > 0 && game.foodsEaten >= game.ObjectiveFoods
+}
+
+// OutOfMoves reports whether the move budget is used up.
+func (game *Game) OutOfMoves() bool {
+	return game.MoveBudget > 0 && game.movesLeft <= 0
+}
+
+func (game *Game) IsValidNewDir(newDir Velocity) bool {
+	if NorthDir.Equals(game.snail.Direction) {
+		return !SouthDir.Equals(newDir)
+	} else if SouthDir.Equals(game.snail.Direction) {
+		return !NorthDir.Equals(newDir)
+	} else if EastDir.Equals(game.snail.Direction) {
+		return !WestDir.Equals(newDir)
+	} else if WestDir.Equals(game.snail.Direction) {
+		return !EastDir.Equals(newDir)
+	}
+	return NorthDir.Equals(newDir) || SouthDir.Equals(newDir) || WestDir.Equals(newDir) || EastDir.Equals(newDir)
+}
+
+// Bounce turns the snail along the wall if its next move would wrap around an
+// edge. It prefers the turn towards the center of the grid but never picks a
+// turn that leads into the body or around another edge if there is a choice.
+func (game *Game) Bounce(grow bool) {
+	head := game.snail.GetHead()
+	if _, wrapped := game.NextPos(head, game.snail.Direction); !wrapped {
+		return
+	}
+	dir := game.snail.Direction
+	turns := [2]Velocity{{X: dir.Y, Y: dir.X}, {X: -dir.Y, Y: -dir.X}}
+	if turns[0].X*(game.XDim/2-head.X)+turns[0].Y*(game.YDim/2-head.Y) < 0 {
+		turns[0], turns[1] = turns[1], turns[0]
+	}
+	body := game.snail.Body
+	if !grow {
+		// the tail moves out of the way
+		body = body[1:]
+	}
+	fallback := dir
+	for _, turn := range turns {
+		next, wrapped := game.NextPos(head, turn)
+		if wrapped {
+			continue
+		}
+		if !game.CheckCollisions(next, body) && !game.CheckCollisions(next, game.obstacles) {
+			game.snail.Direction = turn
+			return
+		}
+		if fallback == dir {
+			fallback = turn
+		}
+	}
+	game.snail.Direction = fallback
+}
+
+// Collides reports whether the snail runs into itself, an obstacle or a
+// lethal wall. Collisions are ignored while a grace period is left, running
+// into itself while a PickupGhost is active.
+func (game *Game) Collides() bool {
+	if game.graceLeft > 0 {
+		return false
+	}
+	body := game.snail.Body
+	bitten := !game.Active(PickupGhost) && game.CheckCollisions(body[len(body)-1], body[:len(body)-1])
+	return bitten || game.HitsObstacle() || game.HitsWall()
+}
+
+// HitsWall reports whether the next move runs into a lethal edge.
+func (game *Game) HitsWall() bool {
+	if game.Bounds != BoundsWalls {
+		return false
+	}
+	_, wrapped := game.NextPos(game.snail.GetHead(), game.snail.Direction)
+	return wrapped
+}
+
+// ChangeDirection turns the snail into newDir if that is a valid move under
+// the RuleSet and logs the input.
+func (game *Game) ChangeDirection(newDir Velocity) error {
+	if !game.rules().ValidMove(game, newDir) {
+		return nil
+	}
+	if !newDir.Equals(game.snail.Direction) {
+		game.Debug("direction changed", "x", newDir.X, "y", newDir.Y)
+	}
+	game.snail.Direction = newDir
+	return game.LogInput(DirectionInput(newDir))
+}
+
+// LogInput writes an input processed on the current tick to the input log.
+func (game *Game) LogInput(input string) error {
+	return game.inputLogger.Log(InputEvent{Tick: game.tick, Input: input})
+}
+
+// SetInputLogger makes the game log all inputs it processes to logger.
+func (game *Game) SetInputLogger(logger *InputLogger) {
+	game.inputLogger = logger
+}
+
+// ReplayInputs applies the replayed inputs of the current tick. Pauses are
+// skipped as they do not change the state of the game.
+func (game *Game) ReplayInputs() error {
+	if game.Replay == nil {
+		return nil
+	}
+	events := game.Replay.Events
+	for game.replayIndex < len(events) && events[game.replayIndex].Tick <= game.tick {
+		if name, ok := strings.CutPrefix(events[game.replayIndex].Input, InputRival); ok {
+			if err := game.TurnRival(0, directionInputs[name]); err != nil {
+				return err
+			}
+		} else if dir, ok := directionInputs[events[game.replayIndex].Input]; ok {
+			if err := game.ChangeDirection(dir); err != nil {
+				return err
+			}
+		}
+		game.replayIndex += 1
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "testing"
+
+func TestBounce(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []Pos
+		dir      Velocity
+		wantHead Pos
+		wantDir  Velocity
+	}{
+		{"east edge turns to the center below", []Pos{{X: 7, Y: 2}, {X: 8, Y: 2}, {X: 9, Y: 2}}, EastDir, Pos{X: 9, Y: 3}, SouthDir},
+		{"east edge turns to the center above", []Pos{{X: 7, Y: 7}, {X: 8, Y: 7}, {X: 9, Y: 7}}, EastDir, Pos{X: 9, Y: 6}, NorthDir},
+		{"corner", []Pos{{X: 7, Y: 0}, {X: 8, Y: 0}, {X: 9, Y: 0}}, EastDir, Pos{X: 9, Y: 1}, SouthDir},
+		{"north edge", []Pos{{X: 4, Y: 2}, {X: 4, Y: 1}, {X: 4, Y: 0}}, NorthDir, Pos{X: 5, Y: 0}, EastDir},
+		{"body blocks the center", []Pos{{X: 9, Y: 4}, {X: 9, Y: 3}, {X: 8, Y: 3}, {X: 8, Y: 2}, {X: 9, Y: 2}}, EastDir, Pos{X: 9, Y: 1}, NorthDir},
+		{"inside", []Pos{{X: 3, Y: 5}, {X: 4, Y: 5}, {X: 5, Y: 5}}, EastDir, Pos{X: 6, Y: 5}, EastDir},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, Bounds: BoundsBounce})
+			if err != nil {
+				t.Fatal(err)
+			}
+			game.snail = Snail{Body: test.body, Direction: test.dir}
+			game.food = Pos{X: 0, Y: 9}
+			running, err := game.Step()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !running {
+				t.Fatal("the snail died at the wall")
+			}
+			if head := game.Head(); head != test.wantHead {
+				t.Errorf("head at %v, want %v", head, test.wantHead)
+			}
+			if dir := game.Snail().Direction; !dir.Equals(test.wantDir) {
+				t.Errorf("direction %v, want %v", dir, test.wantDir)
+			}
+			if _, _, wrapped := game.Wrapped(); wrapped {
+				t.Error("the snail wrapped around the edge")
+			}
+		})
+	}
+}
+
+func TestEatsFood(t *testing.T) {
+	middle := []Pos{{X: 3, Y: 5}, {X: 4, Y: 5}, {X: 5, Y: 5}}
+	edge := []Pos{{X: 7, Y: 5}, {X: 8, Y: 5}, {X: 9, Y: 5}}
+	tests := []struct {
+		name      string
+		body      []Pos
+		food      Pos
+		bounds    Bounds
+		wantExact bool
+		wantTouch bool
+	}{
+		{"on the head", middle, Pos{X: 5, Y: 5}, BoundsWrap, true, true},
+		{"next to the head", middle, Pos{X: 6, Y: 5}, BoundsWrap, false, true},
+		{"below the head", middle, Pos{X: 5, Y: 6}, BoundsWrap, false, true},
+		{"diagonal to the head", middle, Pos{X: 6, Y: 6}, BoundsWrap, false, false},
+		{"two cells ahead", middle, Pos{X: 7, Y: 5}, BoundsWrap, false, false},
+		{"next to the tail", middle, Pos{X: 2, Y: 5}, BoundsWrap, false, false},
+		{"across the wrapping edge", edge, Pos{X: 0, Y: 5}, BoundsWrap, false, true},
+		{"behind a wall", edge, Pos{X: 0, Y: 5}, BoundsWalls, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, rule := range []EatRule{EatExact, EatTouch} {
+				game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, Bounds: test.bounds, EatRule: rule})
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := test.wantExact
+				if rule == EatTouch {
+					want = test.wantTouch
+				}
+				if got := game.EatsFood(test.food, test.body); got != want {
+					t.Errorf("rule %d: eats food at %v: %t, want %t", rule, test.food, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAntiStall(t *testing.T) {
+	tests := []struct {
+		name      string
+		antiStall int
+		// wantTick is the tick the food is moved on, zero if it stays
+		wantTick int
+	}{
+		{"off", 0, 0},
+		{"short", 2, 11},
+		{"long", 6, 15},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, AntiStall: test.antiStall})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the snail circles along row 5 and never gets closer than 5
+			// moves to the food at 0,0, which is 8 moves away at first
+			game.food = Pos{X: 0, Y: 0}
+			game.scorer.OldHeadPos = game.Head()
+			game.scorer.OldFoodPos = game.food
+			for tick := 1; tick <= 30; tick++ {
+				if _, err := game.Step(); err != nil {
+					t.Fatal(err)
+				}
+				if game.Food() == (Pos{X: 0, Y: 0}) {
+					continue
+				}
+				if game.FoodsEaten() > 0 {
+					t.Fatal("the food was eaten")
+				}
+				if tick != test.wantTick {
+					t.Fatalf("food moved on tick %d, want %d", tick, test.wantTick)
+				}
+				return
+			}
+			if test.wantTick != 0 {
+				t.Errorf("food not moved by tick 30, want it moved on tick %d", test.wantTick)
+			}
+		})
+	}
+}
+
+func TestGrace(t *testing.T) {
+	tests := []struct {
+		grace int
+		// wantTick is the tick the snail dies on
+		wantTick int
+	}{
+		{0, 2},
+		{1, 2},
+		{3, 4},
+		{5, 6},
+	}
+	for _, test := range tests {
+		game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, Grace: test.grace})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the snail runs along a row of obstacles from the first move on
+		game.obstacles = []Pos{{X: 8, Y: 5}, {X: 9, Y: 5}, {X: 0, Y: 5}, {X: 1, Y: 5}, {X: 2, Y: 5}, {X: 3, Y: 5}, {X: 4, Y: 5}}
+		game.food = Pos{X: 0, Y: 0}
+		for tick := 1; ; tick++ {
+			if tick > 10 {
+				t.Fatalf("grace %d: the snail did not die", test.grace)
+			}
+			running, err := game.Step()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if running {
+				continue
+			}
+			if game.EndGame(); tick != test.wantTick || !game.Died() {
+				t.Errorf("grace %d: game over on tick %d with died %t, want the snail dead on tick %d",
+					test.grace, tick, game.Died(), test.wantTick)
+			}
+			break
+		}
+	}
+}
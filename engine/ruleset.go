@@ -0,0 +1,117 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// RuleSet decides when moves are allowed, what happens on eating and
+// collisions and when a game is won. Embed DefaultRules to change only some
+// of them.
+type RuleSet interface {
+	// ValidMove reports whether the snail may turn into dir.
+	ValidMove(game *Game, dir Velocity) bool
+	// OnEat is called after the snail ate food and it was scored.
+	OnEat(game *Game)
+	// OnCollision is called when the snail runs into itself, an obstacle or
+	// a lethal wall and reports whether that ends the game.
+	OnCollision(game *Game) bool
+	// WinCondition reports whether the game is won.
+	WinCondition(game *Game) bool
+}
+
+// DefaultRules are the rules of a classic game of snail.
+type DefaultRules struct{}
+
+// ValidMove forbids turning back into the body.
+func (DefaultRules) ValidMove(game *Game, dir Velocity) bool {
+	return game.IsValidNewDir(dir)
+}
+
+func (DefaultRules) OnEat(game *Game) {}
+
+// OnCollision ends the game.
+func (DefaultRules) OnCollision(game *Game) bool {
+	return true
+}
+
+// WinCondition holds once the board is full or the objective is reached.
+func (DefaultRules) WinCondition(game *Game) bool {
+	return game.BoardFull() || game.ObjectiveReached()
+}
+
+var registeredRules = map[string]RuleSet{
+	"classic": DefaultRules{},
+	"zen":     ZenRules{},
+	"reverse": ReverseRules{},
+}
+
+// RegisterRules makes rules available under name, usually from the init
+// function of a mod that is compiled in. It panics if name is taken.
+func RegisterRules(name string, rules RuleSet) {
+	if _, ok := registeredRules[name]; ok {
+		panic(fmt.Sprintf("rules %q are registered twice", name))
+	}
+	registeredRules[name] = rules
+}
+
+// LookupRules returns the rules registered under name.
+func LookupRules(name string) (RuleSet, error) {
+	rules, ok := registeredRules[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown rules %q, registered are %v", name, RegisteredRules())
+	}
+	return rules, nil
+}
+
+// RulesName returns the name rules are registered under and false if they
+// are not registered.
+func RulesName(rules RuleSet) (string, bool) {
+	for _, name := range RegisteredRules() {
+		if reflect.DeepEqual(registeredRules[name], rules) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// RegisteredRules returns the sorted names of all registered rules.
+func RegisteredRules() []string {
+	names := make([]string, 0, len(registeredRules))
+	for name := range registeredRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rules returns the RuleSet of the game.
+func (game *Game) rules() RuleSet {
+	if game.Rules == nil {
+		return DefaultRules{}
+	}
+	return game.Rules
+}
@@ -0,0 +1,226 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"fmt"
+	"io"
+	"math"
+)
+
+// DefaultScoreWeight is the Scorer weight at which awarded points are not scaled.
+const DefaultScoreWeight = 50
+
+// MaxCombo is the highest multiplier a combo reaches.
+const MaxCombo = 5
+
+// FoodScore is how the points for a single eaten food came about.
+type FoodScore struct {
+	// Steps is the number of moves the snail took to reach the food.
+	Steps int
+	// Distance is the shortest number of moves to the food when it appeared.
+	Distance int
+	// Points are the points awarded for the food.
+	Points int
+	// Combo is the combo multiplier the points were awarded with.
+	Combo int
+}
+
+// Perfect reports whether the food was reached on a shortest path.
+func (food FoodScore) Perfect() bool {
+	return food.Steps <= food.Distance
+}
+
+type Scorer struct {
+	Score             int
+	movesSinceLastInc int
+	weight            int
+	gridWidth         int
+	gridHeight        int
+	maxPoints         int
+	OldHeadPos        Pos
+	OldFoodPos        Pos
+	Breakdown         []FoodScore
+	// comboWindow is the number of moves beyond the shortest path within
+	// which food raises the combo, zero turns combos off.
+	comboWindow int
+	// combo is the combo multiplier minus one when the last food was eaten.
+	combo int
+	// scoring names the ScoringPolicy of the points per food.
+	scoring string
+	// bounds decides whether the shortest path to the food may wrap around
+	// the edges of the grid.
+	bounds Bounds
+}
+
+func (scorer *Scorer) Step() {
+	scorer.movesSinceLastInc += 1
+}
+
+// Combo returns the multiplier of the points for the current food. Every
+// comboWindow moves beyond the shortest path to it lower the multiplier by
+// one.
+func (scorer *Scorer) Combo() int {
+	multiplier := scorer.combo + 1
+	if scorer.comboWindow > 0 {
+		if wasted := scorer.movesSinceLastInc - scorer.distance(); wasted > 0 {
+			multiplier -= wasted / scorer.comboWindow
+		}
+	}
+	return max(multiplier, 1)
+}
+
+// BreakCombo drops the combo multiplier back to one.
+func (scorer *Scorer) BreakCombo() {
+	scorer.combo = 0
+}
+
+func (scorer *Scorer) ResetSteps() {
+	scorer.movesSinceLastInc = 0
+}
+
+// Penalize subtracts points from the score without going below zero.
+func (scorer *Scorer) Penalize(points int) {
+	scorer.Score -= points
+	if scorer.Score < 0 {
+		scorer.Score = 0
+	}
+}
+
+// AddPoints adds points to the score, negative points are subtracted like
+// with Penalize.
+func (scorer *Scorer) AddPoints(points int) {
+	if points < 0 {
+		scorer.Penalize(-points)
+		return
+	}
+	scorer.Score += points
+}
+
+// Award adds points scaled by weight/DefaultScoreWeight to the score and
+// returns the added points. At least one point is awarded.
+func (scorer *Scorer) Award(points float64) int {
+	scaled := int(math.Max(math.Round(points*float64(scorer.weight)/DefaultScoreWeight), 1))
+	scorer.Score += scaled
+	return scaled
+}
+
+// distance returns the shortest number of moves from OldHeadPos to
+// OldFoodPos, see Game.Distance. It only wraps around the edges if the
+// bounds let the snail do so.
+func (scorer *Scorer) distance() int {
+	grid := Game{Config: Config{XDim: scorer.gridWidth, YDim: scorer.gridHeight, Bounds: scorer.bounds}}
+	return grid.Distance(scorer.OldHeadPos, scorer.OldFoodPos)
+}
+
+// policy returns the ScoringPolicy named by scoring.
+func (scorer *Scorer) policy() ScoringPolicy {
+	if policy, ok := ScoringPolicies[scorer.scoring]; ok {
+		return policy
+	}
+	return EfficiencyScoring{}
+}
+
+// CalculateScore awards the points for the food that was just eaten by a
+// snail of the given length under the ScoringPolicy, times the combo
+// multiplier, and returns how they came about. Food reached within
+// comboWindow moves beyond the shortest path raises the multiplier, other
+// food drops it back to one.
+func (scorer *Scorer) CalculateScore(length int) (FoodScore, error) {
+	defer scorer.ResetSteps()
+	if scorer.movesSinceLastInc < 1 {
+		return FoodScore{}, ErrNoSteps
+	}
+	multiplier := scorer.Combo()
+	scored := ScoredFood{
+		Steps:     scorer.movesSinceLastInc,
+		Distance:  scorer.distance(),
+		Length:    length,
+		Width:     scorer.gridWidth,
+		Height:    scorer.gridHeight,
+		MaxPoints: scorer.maxPoints,
+	}
+	food := FoodScore{
+		Steps:    scored.Steps,
+		Distance: scored.Distance,
+		Points:   scorer.Award(scorer.policy().Points(scored) * float64(multiplier)),
+		Combo:    multiplier,
+	}
+	scorer.combo = 0
+	if scorer.comboWindow > 0 && food.Steps-food.Distance < scorer.comboWindow {
+		scorer.combo = min(multiplier, MaxCombo-1)
+	}
+	scorer.Breakdown = append(scorer.Breakdown, food)
+	return food, nil
+}
+
+// Rescore replaces the points awarded for the last food with points.
+func (scorer *Scorer) Rescore(points int) {
+	if len(scorer.Breakdown) == 0 {
+		return
+	}
+	last := &scorer.Breakdown[len(scorer.Breakdown)-1]
+	scorer.Score += points - last.Points
+	last.Points = points
+}
+
+// WinBonus returns the points awarded for winning a game on a width x height
+// grid after eating foods foods in moves moves: bonus for completing it plus
+// an efficiency bonus of up to bonus that shrinks the more moves were needed
+// compared to a par of half the grid's circumference per food.
+func WinBonus(bonus, foods, moves, width, height int) int {
+	if bonus < 1 {
+		return 0
+	}
+	par := float64(foods*(width+height)) / 2
+	efficiency := 1.0
+	if moves > 0 {
+		efficiency = math.Min(par/float64(moves), 1)
+	}
+	return bonus + int(math.Round(float64(bonus)*efficiency))
+}
+
+func InitScorer(width, height, weight int) Scorer {
+	return Scorer{
+		Score:             0,
+		movesSinceLastInc: 0,
+		weight:            weight,
+		gridWidth:         width,
+		gridHeight:        height,
+		maxPoints:         10,
+	}
+}
+
+// WriteBreakdown writes one line per eaten food to w.
+func (scorer *Scorer) WriteBreakdown(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "%-6s %6s %9s %7s\n", "food", "steps", "shortest", "points"); err != nil {
+		return err
+	}
+	for index, food := range scorer.Breakdown {
+		if _, err := fmt.Fprintf(w, "%-6d %6d %9d %7d\n", index+1, food.Steps, food.Distance, food.Points); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "total score %d\n", scorer.Score)
+	return err
+}
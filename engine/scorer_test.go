@@ -0,0 +1,254 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAward(t *testing.T) {
+	tests := []struct {
+		weight int
+		points float64
+		want   int
+	}{
+		{DefaultScoreWeight, 4, 4},
+		{100, 4, 8},
+		{25, 4, 2},
+		{75, 3, 5},
+		{1, 4, 1},
+		{DefaultScoreWeight, 0, 1},
+	}
+	for _, test := range tests {
+		scorer := InitScorer(10, 10, test.weight)
+		if got := scorer.Award(test.points); got != test.want || scorer.Score != test.want {
+			t.Errorf("weight %d: Award(%v) = %d with score %d, want %d", test.weight, test.points, got, scorer.Score, test.want)
+		}
+	}
+}
+
+func TestCalculateScoreWeight(t *testing.T) {
+	score := func(weight int) int {
+		scorer := InitScorer(10, 10, weight)
+		scorer.OldHeadPos = Pos{X: 1, Y: 1}
+		scorer.OldFoodPos = Pos{X: 4, Y: 3}
+		for i := 0; i < 7; i++ {
+			scorer.Step()
+		}
+		food, err := scorer.CalculateScore(3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if food.Points != scorer.Score {
+			t.Fatalf("weight %d: awarded %d points but scored %d", weight, food.Points, scorer.Score)
+		}
+		return food.Points
+	}
+	base := score(DefaultScoreWeight)
+	if base < 2 {
+		t.Fatalf("%d points at the default weight, too few to tell scaled points apart", base)
+	}
+	tests := []struct {
+		weight int
+		want   int
+	}{
+		{DefaultScoreWeight, base},
+		{2 * DefaultScoreWeight, 2 * base},
+		{4 * DefaultScoreWeight, 4 * base},
+		{1, 1},
+	}
+	for _, test := range tests {
+		if got := score(test.weight); got != test.want {
+			t.Errorf("weight %d: %d points, want %d", test.weight, got, test.want)
+		}
+	}
+}
+
+func TestCombo(t *testing.T) {
+	// the food is four moves from the head, steps are the moves taken to it
+	tests := []struct {
+		name   string
+		window int
+		steps  []int
+		want   []int
+	}{
+		{"off", 0, []int{4, 4, 4}, []int{1, 1, 1}},
+		{"inside the window", 3, []int{4, 6, 4}, []int{1, 2, 3}},
+		{"capped", 3, []int{4, 4, 4, 4, 4, 4}, []int{1, 2, 3, 4, 5, 5}},
+		// four wasted moves lower the multiplier of that food from four to
+		// three and drop the one of the next food back to one
+		{"outside the window", 3, []int{4, 4, 4, 8, 4}, []int{1, 2, 3, 3, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scorer := InitScorer(10, 10, DefaultScoreWeight)
+			scorer.comboWindow = test.window
+			var got []int
+			for _, steps := range test.steps {
+				scorer.OldHeadPos = Pos{X: 0, Y: 0}
+				scorer.OldFoodPos = Pos{X: 4, Y: 0}
+				scorer.movesSinceLastInc = steps
+				food, err := scorer.CalculateScore(3)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, food.Combo)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("combos %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestScorerDistance(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  Bounds
+		head    Pos
+		food    Pos
+		want    int
+		perfect bool
+	}{
+		{"wrap across the edge", BoundsWrap, Pos{X: 1, Y: 5}, Pos{X: 8, Y: 5}, 3, false},
+		{"walls", BoundsWalls, Pos{X: 1, Y: 5}, Pos{X: 8, Y: 5}, 7, true},
+		{"bounce", BoundsBounce, Pos{X: 1, Y: 5}, Pos{X: 8, Y: 5}, 7, true},
+		{"walls in a corner", BoundsWalls, Pos{X: 0, Y: 0}, Pos{X: 9, Y: 9}, 18, true},
+		{"wrap in a corner", BoundsWrap, Pos{X: 0, Y: 0}, Pos{X: 9, Y: 9}, 2, false},
+		{"walls inside", BoundsWalls, Pos{X: 2, Y: 3}, Pos{X: 4, Y: 6}, 5, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, Bounds: test.bounds})
+			if err != nil {
+				t.Fatal(err)
+			}
+			scorer := game.scorer
+			scorer.OldHeadPos = test.head
+			scorer.OldFoodPos = test.food
+			// the snail takes the shortest path without wrapping
+			steps := abs(test.head.X-test.food.X) + abs(test.head.Y-test.food.Y)
+			for i := 0; i < steps; i++ {
+				scorer.Step()
+			}
+			food, err := scorer.CalculateScore(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if food.Distance != test.want || food.Perfect() != test.perfect {
+				t.Errorf("distance %d perfect %v, want %d perfect %v", food.Distance, food.Perfect(), test.want, test.perfect)
+			}
+		})
+	}
+}
+
+func TestWinBonus(t *testing.T) {
+	tests := []struct {
+		name   string
+		bonus  int
+		foods  int
+		moves  int
+		height int
+		want   int
+	}{
+		{"off", 0, 5, 50, 10, 0},
+		// the par of five foods on a 10x10 grid is 50 moves
+		{"par", 100, 5, 50, 10, 200},
+		{"faster than par", 100, 5, 25, 10, 200},
+		{"twice the par", 100, 5, 100, 10, 150},
+		{"four times the par", 100, 5, 200, 10, 125},
+		{"no moves", 100, 0, 0, 10, 200},
+		// the par of one food on a 10x9 grid is 9.5 moves
+		{"odd circumference", 100, 1, 19, 9, 150},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := WinBonus(test.bonus, test.foods, test.moves, 10, test.height); got != test.want {
+				t.Errorf("WinBonus(%d, %d, %d, 10, %d) = %d, want %d", test.bonus, test.foods, test.moves, test.height, got, test.want)
+			}
+		})
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	tests := []struct {
+		name string
+		// turns are the directions taken from the first food on, one per tick
+		turns []Velocity
+		want  []FoodScore
+	}{
+		{"shortest paths", []Velocity{NorthDir}, []FoodScore{{Steps: 2, Distance: 2}, {Steps: 3, Distance: 3}}},
+		{"detour", []Velocity{NorthDir, WestDir, NorthDir, NorthDir, EastDir}, []FoodScore{{Steps: 2, Distance: 2}, {Steps: 5, Distance: 3}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, Growth: 1, Bounds: BoundsWalls,
+				FoodScript: []Pos{{X: 9, Y: 5}, {X: 9, Y: 2}}, ObjectiveFoods: 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			turns, turning := test.turns, false
+			for ticks := 0; ; ticks++ {
+				if ticks > 20 {
+					t.Fatal("the game did not end")
+				}
+				turning = turning || game.Head() == game.FoodScript[0]
+				if turning && len(turns) > 0 {
+					if err := game.ChangeDirection(turns[0]); err != nil {
+						t.Fatal(err)
+					}
+					turns = turns[1:]
+				}
+				running, err := game.Step()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !running {
+					break
+				}
+			}
+			breakdown := game.Breakdown()
+			if len(breakdown) != len(test.want) {
+				t.Fatalf("breakdown %+v, want %d foods", breakdown, len(test.want))
+			}
+			total := 0
+			for index, food := range breakdown {
+				if food.Steps != test.want[index].Steps || food.Distance != test.want[index].Distance {
+					t.Errorf("food %d took %d steps of %d, want %d of %d",
+						index+1, food.Steps, food.Distance, test.want[index].Steps, test.want[index].Distance)
+				}
+				if food.Points < 1 {
+					t.Errorf("food %d awarded %d points", index+1, food.Points)
+				}
+				total += food.Points
+			}
+			if total != game.Score() {
+				t.Errorf("breakdown sums up to %d points, want the score %d", total, game.Score())
+			}
+			if perfect := breakdown[1].Perfect(); perfect != (test.want[1].Steps == test.want[1].Distance) {
+				t.Errorf("second food perfect %t", perfect)
+			}
+		})
+	}
+}
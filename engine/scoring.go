@@ -0,0 +1,103 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultScoring is the name of the ScoringPolicy used if none is set.
+const DefaultScoring = "efficiency"
+
+// ScoredFood is what a ScoringPolicy scores eaten food by.
+type ScoredFood struct {
+	// Steps is the number of moves the snail took to reach the food.
+	Steps int
+	// Distance is the shortest number of moves to the food when it appeared.
+	Distance int
+	// Length is the length of the snail that ate the food.
+	Length int
+	// Width and Height are the dimensions of the grid.
+	Width  int
+	Height int
+	// MaxPoints are the most points a food is meant to be worth.
+	MaxPoints int
+}
+
+// ScoringPolicy decides the points for eaten food. The points are scaled by
+// the score weight and multiplied by the combo afterwards, at least one point
+// is awarded.
+type ScoringPolicy interface {
+	Points(food ScoredFood) float64
+}
+
+// EfficiencyScoring awards up to MaxPoints for food reached on a shortest
+// path, falling off cubically with the moves wasted on the way, down to one
+// point once half the grid was wasted.
+type EfficiencyScoring struct{}
+
+func (EfficiencyScoring) Points(food ScoredFood) float64 {
+	half := (food.Width * food.Height) / 2
+	if food.Steps < food.Distance {
+		// this should actually never happen
+		return float64(food.MaxPoints)
+	}
+	if food.Steps-food.Distance >= half {
+		// too many steps -> 1 point
+		return 1
+	}
+	x := float64(food.Steps-food.Distance) / float64(half)
+	return (1 - math.Pow(2*x-1, 3)) / 2 * float64(food.MaxPoints)
+}
+
+// ClassicScoring awards a point per food however it was reached.
+type ClassicScoring struct{}
+
+func (ClassicScoring) Points(food ScoredFood) float64 {
+	return 1
+}
+
+// LengthScoring awards as many points as the snail is long, so food gets
+// worth more the longer the snail grows.
+type LengthScoring struct{}
+
+func (LengthScoring) Points(food ScoredFood) float64 {
+	return float64(food.Length)
+}
+
+// ScoringPolicies are the policies Config.Scoring names, further ones can be
+// added before a game is started.
+var ScoringPolicies = map[string]ScoringPolicy{
+	"efficiency": EfficiencyScoring{},
+	"classic":    ClassicScoring{},
+	"length":     LengthScoring{},
+}
+
+// ParseScoringPolicy returns the ScoringPolicy for the given name.
+func ParseScoringPolicy(name string) (ScoringPolicy, error) {
+	if policy, ok := ScoringPolicies[name]; ok {
+		return policy, nil
+	}
+	return EfficiencyScoring{}, fmt.Errorf("unknown scoring policy %q", name)
+}
@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "testing"
+
+func TestScoringPolicies(t *testing.T) {
+	// the food is four moves from the head on a 10x10 grid
+	tests := []struct {
+		name    string
+		scoring string
+		steps   int
+		length  int
+		want    int
+	}{
+		{"efficiency shortest", "efficiency", 4, 3, 10},
+		{"efficiency quarter wasted", "efficiency", 29, 3, 5},
+		{"efficiency half wasted", "efficiency", 54, 3, 1},
+		{"classic shortest", "classic", 4, 3, 1},
+		{"classic wasted", "classic", 29, 3, 1},
+		{"length short", "length", 4, 3, 3},
+		{"length long", "length", 29, 12, 12},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			scorer := InitScorer(10, 10, DefaultScoreWeight)
+			scorer.scoring = test.scoring
+			scorer.OldHeadPos = Pos{X: 0, Y: 0}
+			scorer.OldFoodPos = Pos{X: 4, Y: 0}
+			scorer.movesSinceLastInc = test.steps
+			food, err := scorer.CalculateScore(test.length)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if food.Points != test.want || scorer.Score != test.want {
+				t.Errorf("awarded %d with score %d, want %d", food.Points, scorer.Score, test.want)
+			}
+		})
+	}
+}
+
+func TestParseScoringPolicy(t *testing.T) {
+	for name := range ScoringPolicies {
+		if _, err := ParseScoringPolicy(name); err != nil {
+			t.Errorf("ParseScoringPolicy(%q) = %v", name, err)
+		}
+	}
+	if _, err := ParseScoringPolicy("golf"); err == nil {
+		t.Error("an unknown scoring policy was parsed")
+	}
+}
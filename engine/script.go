@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// Script intercepts events of a game to vary its rules without changing the
+// engine, e.g. with a script of a scripting language. An error of a Script
+// ends the game with that error.
+type Script interface {
+	// Tick is called after every tick of a running game.
+	Tick(game *Game) error
+	// PlaceFood returns the cell new food is placed on instead of pos. Cells
+	// outside the grid or Occupied ones are ignored.
+	PlaceFood(game *Game, pos Pos) (Pos, error)
+	// Score returns the points awarded for eaten food instead of food.Points.
+	Score(game *Game, food FoodScore) (int, error)
+}
+
+// scriptFood passes the cell chosen for new food to the Script and returns
+// the cell the food is placed on.
+func (game *Game) scriptFood(pos Pos) (Pos, error) {
+	if game.Script == nil {
+		return pos, nil
+	}
+	scripted, err := game.Script.PlaceFood(game, pos)
+	if err != nil {
+		return pos, err
+	}
+	if scripted.X < 0 || scripted.X >= game.XDim || scripted.Y < 0 || scripted.Y >= game.YDim {
+		return pos, nil
+	}
+	for _, occupied := range game.Occupied() {
+		if occupied == scripted {
+			return pos, nil
+		}
+	}
+	return scripted, nil
+}
+
+// scriptScore lets the Script replace the points of the food just eaten and
+// returns the food with the points awarded.
+func (game *Game) scriptScore(food FoodScore) (FoodScore, error) {
+	if game.Script == nil {
+		return food, nil
+	}
+	points, err := game.Script.Score(game, food)
+	if err != nil {
+		return food, err
+	}
+	game.scorer.Rescore(points)
+	food.Points = points
+	return food, nil
+}
+
+// scriptTick calls the Tick of the Script, if there is one.
+func (game *Game) scriptTick() error {
+	if game.Script == nil {
+		return nil
+	}
+	return game.Script.Tick(game)
+}
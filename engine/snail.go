@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+type Snail struct {
+	// NOTE: the head is at the end of the slice!
+	Body      []Pos
+	Direction Velocity
+	OldTail   Pos
+}
+
+// NextPos returns the position following oldPos in the current direction and
+// whether the move wrapped around an edge of the grid.
+func (snail *Snail) NextPos(oldPos Pos, XDim int, YDim int) (Pos, bool) {
+	newHead := Neighbor(oldPos, snail.Direction, XDim, YDim)
+	wrapped := newHead.X != oldPos.X+snail.Direction.X || newHead.Y != oldPos.Y+snail.Direction.Y
+	return newHead, wrapped
+}
+
+// MoveForward advances the snail by one cell, keeping its tail in place if it
+// grows, and reports whether the head wrapped around an edge of the grid.
+func (snail *Snail) MoveForward(grow bool, XDim int, YDim int) bool {
+	oldHead := snail.Body[len(snail.Body)-1]
+	newHead, wrapped := snail.NextPos(oldHead, XDim, YDim)
+	snail.MoveTo(newHead, grow)
+	return wrapped
+}
+
+// MoveTo moves the head to newHead, keeping the tail in place if the snail
+// grows.
+func (snail *Snail) MoveTo(newHead Pos, grow bool) {
+	snail.Body = append(snail.Body, newHead)
+	if !grow {
+		snail.OldTail = snail.Body[0]
+		snail.Body = snail.Body[1:]
+	}
+}
+
+func (snail *Snail) GetHead() Pos {
+	return snail.Body[len(snail.Body)-1]
+}
+
+func InitSnail(width int, height int) Snail {
+	startPos := []Pos{{
+		X: int(width / 2),
+		Y: int(height / 2),
+	},
+		{
+			X: int(width/2) + 1,
+			Y: int(height / 2),
+		},
+		{
+			X: int(width/2) + 2,
+			Y: int(height / 2),
+		},
+	}
+	oldTail := Pos{
+		X: -1,
+		Y: -1,
+	}
+	snail := Snail{
+		Body:      startPos,
+		Direction: EastDir,
+		OldTail:   oldTail,
+	}
+	return snail
+}
@@ -0,0 +1,282 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+)
+
+// scorerState holds all fields of a Scorer for json.
+type scorerState struct {
+	Score             int
+	MovesSinceLastInc int
+	Weight            int
+	GridWidth         int
+	GridHeight        int
+	MaxPoints         int
+	OldHeadPos        Pos
+	OldFoodPos        Pos
+	Breakdown         []FoodScore
+	ComboWindow       int    `json:",omitempty"`
+	Combo             int    `json:",omitempty"`
+	Scoring           string `json:",omitempty"`
+	Bounds            Bounds `json:",omitempty"`
+}
+
+func (scorer Scorer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(scorerState{
+		Score:             scorer.Score,
+		MovesSinceLastInc: scorer.movesSinceLastInc,
+		Weight:            scorer.weight,
+		GridWidth:         scorer.gridWidth,
+		GridHeight:        scorer.gridHeight,
+		MaxPoints:         scorer.maxPoints,
+		OldHeadPos:        scorer.OldHeadPos,
+		OldFoodPos:        scorer.OldFoodPos,
+		Breakdown:         scorer.Breakdown,
+		ComboWindow:       scorer.comboWindow,
+		Combo:             scorer.combo,
+		Scoring:           scorer.scoring,
+		Bounds:            scorer.bounds,
+	})
+}
+
+func (scorer *Scorer) UnmarshalJSON(data []byte) error {
+	var state scorerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	*scorer = Scorer{
+		Score:             state.Score,
+		movesSinceLastInc: state.MovesSinceLastInc,
+		weight:            state.Weight,
+		gridWidth:         state.GridWidth,
+		gridHeight:        state.GridHeight,
+		maxPoints:         state.MaxPoints,
+		OldHeadPos:        state.OldHeadPos,
+		OldFoodPos:        state.OldFoodPos,
+		Breakdown:         state.Breakdown,
+		comboWindow:       state.ComboWindow,
+		combo:             state.Combo,
+		scoring:           state.Scoring,
+		bounds:            state.Bounds,
+	}
+	return nil
+}
+
+// gameState holds the state of a Game for json.
+type gameState struct {
+	Config        Config
+	Rules         string `json:",omitempty"`
+	Seed          int64
+	Tick          int
+	Food          Pos
+	Snail         Snail
+	Start         Snail
+	Scorer        Scorer
+	Obstacles     []Pos
+	Portals       [][2]Pos `json:",omitempty"`
+	Rivals        []Rival  `json:",omitempty"`
+	PowerUp       *Pos
+	Pickups       []Pickup           `json:",omitempty"`
+	Effects       map[PickupKind]int `json:",omitempty"`
+	Event         *Event             `json:",omitempty"`
+	NextEvent     int                `json:",omitempty"`
+	ReplayIndex   int
+	PendingGrowth int
+	LagLeft       int
+	Stretch       int
+	FoodsEaten    int
+	MovesLeft     int
+	GraceLeft     int
+	LivesLeft     int
+	Respawned     bool `json:",omitempty"`
+	ScriptIndex   int
+	Perfects      int
+	Rewinds       int
+	FoodAge       int
+	WanderAge     int
+	Over          bool
+	TimeUp        bool
+	Outcome       SnailOutcome `json:",omitempty"`
+	Wrapped       bool         `json:",omitempty"`
+	WrapExit      Pos
+	WrapEntry     Pos
+	// Draws is the number of random numbers drawn, see countedSource.
+	Draws     uint64
+	Recording Recording
+}
+
+// countedSource is a rand.Source that counts the numbers drawn from it, so a
+// stored game can continue its random sequence where it left off.
+type countedSource struct {
+	rand.Source64
+	draws uint64
+}
+
+// newCountedSource returns a source seeded with seed that already had draws
+// numbers drawn from it.
+func newCountedSource(seed int64, draws uint64) *countedSource {
+	source := &countedSource{Source64: rand.NewSource(seed).(rand.Source64)}
+	for source.draws < draws {
+		source.Int63()
+	}
+	return source
+}
+
+func (source *countedSource) Int63() int64 {
+	source.draws += 1
+	return source.Source64.Int63()
+}
+
+func (source *countedSource) Uint64() uint64 {
+	source.draws += 1
+	return source.Source64.Uint64()
+}
+
+// MarshalJSON stores the config and the complete state of the game. The
+// rules are stored by the name they are registered under, see RegisterRules.
+// The input logger, the script and the ticks a rewind can return to are not
+// stored.
+func (game *Game) MarshalJSON() ([]byte, error) {
+	rules := ""
+	if game.Rules != nil {
+		name, ok := RulesName(game.Rules)
+		if !ok {
+			return nil, fmt.Errorf("the rules %T are not registered and cannot be stored", game.Rules)
+		}
+		rules = name
+	}
+	return json.Marshal(gameState{
+		Config:        game.Config,
+		Rules:         rules,
+		Seed:          game.currentSeed,
+		Tick:          game.tick,
+		Food:          game.food,
+		Snail:         game.snail,
+		Start:         game.start,
+		Scorer:        game.scorer,
+		Obstacles:     game.obstacles,
+		Portals:       game.portals,
+		Rivals:        game.rivals,
+		PowerUp:       game.powerUp,
+		Pickups:       game.pickups,
+		Effects:       game.effects,
+		Event:         game.event,
+		NextEvent:     game.nextEvent,
+		ReplayIndex:   game.replayIndex,
+		PendingGrowth: game.pendingGrowth,
+		LagLeft:       game.lagLeft,
+		Stretch:       game.stretch,
+		FoodsEaten:    game.foodsEaten,
+		MovesLeft:     game.movesLeft,
+		GraceLeft:     game.graceLeft,
+		LivesLeft:     game.livesLeft,
+		Respawned:     game.respawned,
+		ScriptIndex:   game.scriptIndex,
+		Perfects:      game.perfects,
+		Rewinds:       game.rewinds,
+		FoodAge:       game.foodAge,
+		WanderAge:     game.wanderAge,
+		Over:          game.over,
+		TimeUp:        game.timeUp,
+		Outcome:       game.outcome,
+		Wrapped:       game.wrapped,
+		WrapExit:      game.wrapExit,
+		WrapEntry:     game.wrapEntry,
+		Draws:         game.source.draws,
+		Recording:     game.recording,
+	})
+}
+
+// UnmarshalJSON restores a game stored with MarshalJSON. The random choices
+// after it continue the random sequence of the stored game, so a loaded game
+// plays on exactly like the game would have without being stored.
+func (game *Game) UnmarshalJSON(data []byte) error {
+	var state gameState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	config := state.Config
+	if state.Rules != "" {
+		rules, err := LookupRules(state.Rules)
+		if err != nil {
+			return err
+		}
+		config.Rules = rules
+	}
+	if config.XDim < 3 || config.YDim < 1 {
+		return fmt.Errorf("invalid grid dimensions %dx%d", config.XDim, config.YDim)
+	}
+	if len(state.Snail.Body) == 0 {
+		return fmt.Errorf("the snail has no body")
+	}
+	grid := Game{Config: config, portals: state.Portals}
+	if err := grid.ValidateBody(state.Snail.Body); err != nil {
+		return err
+	}
+	source := newCountedSource(state.Seed, state.Draws)
+	*game = Game{
+		Config:        config,
+		currentSeed:   state.Seed,
+		source:        source,
+		rng:           rand.New(source),
+		tick:          state.Tick,
+		food:          state.Food,
+		snail:         state.Snail,
+		start:         state.Start,
+		scorer:        state.Scorer,
+		obstacles:     state.Obstacles,
+		portals:       state.Portals,
+		rivals:        state.Rivals,
+		powerUp:       state.PowerUp,
+		pickups:       state.Pickups,
+		effects:       state.Effects,
+		event:         state.Event,
+		nextEvent:     state.NextEvent,
+		replayIndex:   state.ReplayIndex,
+		pendingGrowth: state.PendingGrowth,
+		lagLeft:       state.LagLeft,
+		stretch:       state.Stretch,
+		foodsEaten:    state.FoodsEaten,
+		movesLeft:     state.MovesLeft,
+		graceLeft:     state.GraceLeft,
+		livesLeft:     state.LivesLeft,
+		respawned:     state.Respawned,
+		scriptIndex:   state.ScriptIndex,
+		perfects:      state.Perfects,
+		rewinds:       state.Rewinds,
+		foodAge:       state.FoodAge,
+		wanderAge:     state.WanderAge,
+		over:          state.Over,
+		timeUp:        state.TimeUp,
+		outcome:       state.Outcome,
+		wrapped:       state.Wrapped,
+		wrapExit:      state.WrapExit,
+		wrapEntry:     state.WrapEntry,
+		recording:     state.Recording,
+	}
+	return nil
+}
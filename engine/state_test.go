@@ -0,0 +1,183 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// customRules are rules that are not registered.
+type customRules struct {
+	DefaultRules
+}
+
+func TestGameJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules RuleSet
+		want  string
+	}{
+		{"default", nil, ""},
+		{"classic", DefaultRules{}, "classic"},
+		{"zen", ZenRules{}, "zen"},
+		{"reverse", ReverseRules{}, "reverse"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 10, YDim: 10, Seed: 7, Rules: test.rules})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 5; i++ {
+				if _, err := game.Step(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			data, err := json.Marshal(game)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var state gameState
+			if err := json.Unmarshal(data, &state); err != nil {
+				t.Fatal(err)
+			}
+			if state.Rules != test.want {
+				t.Errorf("stored rules %q, want %q", state.Rules, test.want)
+			}
+			var loaded Game
+			if err := json.Unmarshal(data, &loaded); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(loaded.Rules, test.rules) {
+				t.Errorf("loaded rules %#v, want %#v", loaded.Rules, test.rules)
+			}
+			if loaded.Tick() != game.Tick() || loaded.Score() != game.Score() || loaded.Food() != game.Food() ||
+				!reflect.DeepEqual(loaded.Snail(), game.Snail()) {
+				t.Errorf("loaded game differs: tick %d score %d food %v, want tick %d score %d food %v",
+					loaded.Tick(), loaded.Score(), loaded.Food(), game.Tick(), game.Score(), game.Food())
+			}
+		})
+	}
+}
+
+func TestGameJSONUnregisteredRules(t *testing.T) {
+	game, err := New(Config{XDim: 10, YDim: 10, Seed: 7, Rules: customRules{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := json.Marshal(game); err == nil {
+		t.Error("rules that are not registered were stored")
+	}
+}
+
+func TestGameJSONUnknownRules(t *testing.T) {
+	game, err := New(Config{XDim: 10, YDim: 10, Seed: 7, Rules: ZenRules{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state map[string]any
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	state["Rules"] = "unknown"
+	if data, err = json.Marshal(state); err != nil {
+		t.Fatal(err)
+	}
+	var loaded Game
+	if err := json.Unmarshal(data, &loaded); err == nil {
+		t.Error("a game with unknown rules was loaded")
+	}
+}
+
+func TestGameJSONContinues(t *testing.T) {
+	config := Config{XDim: 10, YDim: 10, Seed: 7, Growth: 1, PoisonChance: 50, Autopilot: &Autopilot{ErrorRate: 0.3}}
+	game, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 30; i++ {
+		if _, err := game.Step(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Game
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	// the loaded game draws the same random numbers as the stored one
+	for i := 0; i < 60; i++ {
+		running, err := game.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		loadedRunning, err := loaded.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if running != loadedRunning || loaded.Food() != game.Food() || !reflect.DeepEqual(loaded.Snail(), game.Snail()) ||
+			!reflect.DeepEqual(loaded.Pickups(), game.Pickups()) {
+			t.Fatalf("tick %d: loaded game has food %v snail %v, want food %v snail %v",
+				game.Tick(), loaded.Food(), loaded.Snail().Body, game.Food(), game.Snail().Body)
+		}
+		if !running {
+			break
+		}
+	}
+	if game.FoodsEaten() < 2 {
+		t.Errorf("only %d foods eaten, the food placement was not compared", game.FoodsEaten())
+	}
+}
+
+func TestGameJSONOutcome(t *testing.T) {
+	game, err := New(Config{XDim: 10, YDim: 10, Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	game.outcome = OutcomeSecondDies
+	game.wrapped, game.wrapExit, game.wrapEntry = true, Pos{X: 9, Y: 5}, Pos{X: 0, Y: 5}
+	data, err := json.Marshal(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded Game
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.outcome != game.outcome {
+		t.Errorf("loaded outcome %v, want %v", loaded.outcome, game.outcome)
+	}
+	exit, entry, wrapped := loaded.Wrapped()
+	if !wrapped || exit != game.wrapExit || entry != game.wrapEntry {
+		t.Errorf("loaded wrap %v from %v to %v, want a wrap from %v to %v", wrapped, exit, entry, game.wrapExit, game.wrapEntry)
+	}
+}
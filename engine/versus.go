@@ -0,0 +1,78 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// SnailOutcome is the result of resolving the collisions between two snails.
+type SnailOutcome int
+
+const (
+	// OutcomeNone means the snails did not collide.
+	OutcomeNone SnailOutcome = iota
+	// OutcomeFirstDies means the first snail dies and the second survives.
+	OutcomeFirstDies
+	// OutcomeSecondDies means the second snail dies and the first survives.
+	OutcomeSecondDies
+	// OutcomeBothDie means both snails die.
+	OutcomeBothDie
+)
+
+var snailOutcomeTexts = map[SnailOutcome]string{
+	OutcomeNone:       "",
+	OutcomeFirstDies:  "Player 2 wins!",
+	OutcomeSecondDies: "Player 1 wins!",
+	OutcomeBothDie:    "Both snails died!",
+}
+
+func (outcome SnailOutcome) String() string {
+	return snailOutcomeTexts[outcome]
+}
+
+// ResolveSnailCollision decides which of two snails dies after both moved.
+// Heads on the same cell kill both snails. A head on the body of the other
+// snail kills the snail it belongs to, unless longerWins is set and the snail
+// is longer than the other one, in which case it eats the other snail
+// instead. If both heads are on the body of the other snail, both die.
+func (game *Game) ResolveSnailCollision(first, second []Pos, longerWins bool) SnailOutcome {
+	firstHead, secondHead := first[len(first)-1], second[len(second)-1]
+	if firstHead == secondHead {
+		return OutcomeBothDie
+	}
+	firstHits := game.CheckCollisions(firstHead, second[:len(second)-1])
+	secondHits := game.CheckCollisions(secondHead, first[:len(first)-1])
+	if firstHits && secondHits {
+		return OutcomeBothDie
+	}
+	if firstHits {
+		if longerWins && len(first) > len(second) {
+			return OutcomeSecondDies
+		}
+		return OutcomeFirstDies
+	}
+	if secondHits {
+		if longerWins && len(second) > len(first) {
+			return OutcomeFirstDies
+		}
+		return OutcomeSecondDies
+	}
+	return OutcomeNone
+}
@@ -0,0 +1,94 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+import "testing"
+
+func TestResolveSnailCollision(t *testing.T) {
+	// long runs with its head at 3,2 into the bodies of short and of equal
+	long := []Pos{{X: 3, Y: 6}, {X: 3, Y: 5}, {X: 3, Y: 4}, {X: 3, Y: 3}, {X: 3, Y: 2}}
+	short := []Pos{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}}
+	equal := []Pos{{X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}}
+	// headOn meets long head to head, crossing runs into long at 3,4 while
+	// long runs into it at 3,2
+	headOn := []Pos{{X: 5, Y: 2}, {X: 4, Y: 2}, {X: 3, Y: 2}}
+	crossing := []Pos{{X: 3, Y: 1}, {X: 3, Y: 2}, {X: 2, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}, {X: 3, Y: 4}}
+	apart := []Pos{{X: 7, Y: 7}, {X: 8, Y: 7}, {X: 9, Y: 7}}
+	tests := []struct {
+		name          string
+		first, second []Pos
+		longerWins    bool
+		want          SnailOutcome
+	}{
+		{"apart", long, apart, true, OutcomeNone},
+		{"longer into shorter", long, short, false, OutcomeFirstDies},
+		{"longer into shorter, longer wins", long, short, true, OutcomeSecondDies},
+		{"second longer into shorter", short, long, false, OutcomeSecondDies},
+		{"second longer into shorter, longer wins", short, long, true, OutcomeFirstDies},
+		{"equal length, longer wins", long, equal, true, OutcomeFirstDies},
+		{"head to head", long, headOn, false, OutcomeBothDie},
+		{"head to head, longer wins", long, headOn, true, OutcomeBothDie},
+		{"equal length head to head", short, []Pos{{X: 6, Y: 2}, {X: 5, Y: 2}, {X: 4, Y: 2}}, true, OutcomeBothDie},
+		{"into each other, longer wins", crossing, long, true, OutcomeBothDie},
+	}
+	game, err := New(Config{XDim: 10, YDim: 10, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range tests {
+		if got := game.ResolveSnailCollision(test.first, test.second, test.longerWins); got != test.want {
+			t.Errorf("%s: outcome %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestLongerWins(t *testing.T) {
+	tests := []struct {
+		name       string
+		longerWins bool
+		want       SnailOutcome
+	}{
+		{"off", false, OutcomeFirstDies},
+		{"on", true, OutcomeSecondDies},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game, err := New(Config{XDim: 10, YDim: 10, Seed: 1, TwoPlayers: true, LongerWins: test.longerWins})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the snail runs with its head into the body of the shorter rival
+			game.snail.Body = []Pos{{X: 3, Y: 6}, {X: 3, Y: 5}, {X: 3, Y: 4}, {X: 3, Y: 3}, {X: 3, Y: 2}}
+			game.rivals[0].Snail.Body = []Pos{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}}
+			if !game.RivalsCollide() {
+				t.Fatal("the game goes on")
+			}
+			if outcome := game.RivalOutcome(); outcome != test.want {
+				t.Errorf("outcome %q, want %q", outcome, test.want)
+			}
+			if dead := game.Rivals()[0].Dead; dead != (test.want == OutcomeSecondDies) {
+				t.Errorf("rival dead %t", dead)
+			}
+		})
+	}
+}
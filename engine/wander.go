@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// fleeDistance is the number of moves between the head and wandering food
+// below which the food flees from the head.
+const fleeDistance = 3
+
+// WanderFood lets the food take a step every FoodWander ticks. Close to the
+// head it steps away from it, otherwise in a random direction. It never steps
+// onto an occupied cell or over a lethal edge and stays put if there is no
+// such step. Wandering does not reset the age FoodTTL is counted with. It
+// reports whether the food moved.
+func (game *Game) WanderFood() bool {
+	if game.FoodWander < 1 || game.wanderAge < game.FoodWander {
+		return false
+	}
+	game.wanderAge = 0
+	occupied := game.Occupied()
+	var steps []Pos
+	for _, pos := range game.Adjacent(game.food) {
+		if !game.CheckCollisions(pos, occupied) {
+			steps = append(steps, pos)
+		}
+	}
+	head := game.snail.GetHead()
+	if game.Distance(head, game.food) < fleeDistance {
+		// only the steps that get farthest from the head remain
+		farthest := 0
+		for _, pos := range steps {
+			if distance := game.Distance(head, pos); distance > farthest {
+				farthest = distance
+			}
+		}
+		fleeing := steps[:0]
+		for _, pos := range steps {
+			if game.Distance(head, pos) == farthest {
+				fleeing = append(fleeing, pos)
+			}
+		}
+		steps = fleeing
+	}
+	if len(steps) == 0 {
+		return false
+	}
+	game.food = steps[game.rng.Intn(len(steps))]
+	game.wandered = true
+	game.Debug("food wandered", "x", game.food.X, "y", game.food.Y)
+	return true
+}
@@ -0,0 +1,87 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package engine
+
+// ZenCutPenalty are the points lost per segment cut off by ZenRules.
+const ZenCutPenalty = 1
+
+// ZenRules are relaxed rules in which the snail never dies. Running into the
+// body cuts off the tail up to the hit segment, obstacles are passed and a
+// lethal wall turns the snail away from it.
+type ZenRules struct {
+	DefaultRules
+}
+
+// OnCollision cuts the tail and turns away from walls, it never ends the
+// game.
+func (ZenRules) OnCollision(game *Game) bool {
+	game.CutTail()
+	if game.HitsWall() {
+		game.TurnAway()
+	}
+	return false
+}
+
+// CutTail cuts off the tail up to and including the segment the head is on
+// and subtracts ZenCutPenalty points per cut segment. The cut is stored in
+// the tick recorded last, see RecordedTick.
+func (game *Game) CutTail() {
+	body := game.snail.Body
+	head := body[len(body)-1]
+	for index, pos := range body[:len(body)-1] {
+		if pos != head {
+			continue
+		}
+		cut := index + 1
+		game.snail.Body = body[cut:]
+		game.stretch = 0
+		if ticks := game.recording.Ticks; len(ticks) > 0 {
+			ticks[len(ticks)-1].Cut = cut
+		}
+		game.scorer.Penalize(cut * ZenCutPenalty)
+		game.Debug("tail cut", "segments", cut, "score", game.scorer.Score)
+		return
+	}
+}
+
+// TurnAway turns the snail into a direction that does not leave the board,
+// preferring one that is safe. The direction is kept if there is none.
+func (game *Game) TurnAway() {
+	turn, found := game.snail.Direction, false
+	for _, dir := range Directions {
+		if !game.IsValidNewDir(dir) {
+			continue
+		}
+		if _, wrapped := game.NextPos(game.snail.GetHead(), dir); wrapped {
+			continue
+		}
+		if _, safe := game.SafeNextPos(dir); safe {
+			game.snail.Direction = dir
+			return
+		}
+		if !found {
+			turn, found = dir, true
+		}
+	}
+	game.snail.Direction = turn
+}
@@ -0,0 +1,281 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// textRunes are the two characters a cell of the given kind is drawn with by
+// the TextCanvas and by a TcellCanvas that does not use colors.
+var textRunes = map[CellKind]string{
+	CellSnailBody: "oo",
+	CellSnailHead: "@@",
+	CellFood:      "**",
+	CellObstacle:  "##",
+	CellWrap:      "::",
+	CellWrapFaded: "..",
+	CellFoodHint:  "++",
+	CellGrid:      " .",
+	CellPowerUp:   "<<",
+	CellPortal:    "()",
+	CellPoison:    "xx",
+	CellGolden:    "$$",
+	CellGhost:     "gg",
+	CellSlow:      "ss",
+	CellShrink:    "--",
+	CellDouble:    "2x",
+	CellLadder:    "HH",
+}
+
+// asciiRunes are the ASCII replacements of box drawing runes.
+var asciiRunes = map[rune]rune{
+	tcell.RuneHLine:    '-',
+	tcell.RuneVLine:    '|',
+	tcell.RuneULCorner: '+',
+	tcell.RuneURCorner: '+',
+	tcell.RuneLLCorner: '+',
+	tcell.RuneLRCorner: '+',
+	'╴':                '-',
+	'╶':                '-',
+	'╵':                '|',
+	'╷':                '|',
+}
+
+func asciiRune(r rune) rune {
+	if ascii, ok := asciiRunes[r]; ok {
+		return ascii
+	}
+	return r
+}
+
+// TextCanvas draws the board as plain ASCII text, using the same layout as
+// the TcellCanvas.
+type TextCanvas struct {
+	cells  map[engine.Pos]rune
+	width  int
+	height int
+}
+
+func NewTextCanvas() *TextCanvas {
+	return &TextCanvas{cells: map[engine.Pos]rune{}}
+}
+
+func (canvas *TextCanvas) setContent(col, row int, r rune) {
+	if col < 0 || row < 0 {
+		return
+	}
+	canvas.cells[engine.Pos{X: col, Y: row}] = r
+	if col >= canvas.width {
+		canvas.width = col + 1
+	}
+	if row >= canvas.height {
+		canvas.height = row + 1
+	}
+}
+
+func (canvas *TextCanvas) DrawCell(x, y int, kind CellKind, style tcell.Style) {
+	runes := []rune(textRunes[kind])
+	canvas.DrawRunes(x, y, runes[0], runes[1], style)
+}
+
+// DrawRunes draws the runes, replacing box drawing runes by their closest
+// ASCII characters.
+func (canvas *TextCanvas) DrawRunes(x, y int, left, right rune, style tcell.Style) {
+	canvas.setContent(x*2+1, y+1, asciiRune(left))
+	canvas.setContent(x*2+2, y+1, asciiRune(right))
+}
+
+func (canvas *TextCanvas) DrawBorder(w, h int, style tcell.Style) {
+	for col := 0; col <= w*2+2; col++ {
+		canvas.setContent(col, 0, '-')
+		canvas.setContent(col, h+1, '-')
+	}
+	for row := 0; row <= h+1; row++ {
+		r := '|'
+		if row == 0 || row == h+1 {
+			r = '+'
+		}
+		canvas.setContent(0, row, r)
+		canvas.setContent(w*2+2, row, r)
+	}
+}
+
+func (canvas *TextCanvas) DrawText(col, row int, text string, style tcell.Style) {
+	for _, r := range text {
+		canvas.setContent(col, row, r)
+		col++
+	}
+}
+
+func (canvas *TextCanvas) Clear() {
+	canvas.cells = map[engine.Pos]rune{}
+	canvas.width = 0
+	canvas.height = 0
+}
+
+func (canvas *TextCanvas) Present() {}
+
+// String returns the drawn text, one line per row without trailing spaces.
+func (canvas *TextCanvas) String() string {
+	var builder strings.Builder
+	for row := 0; row < canvas.height; row++ {
+		line := make([]rune, canvas.width)
+		for col := range line {
+			line[col] = ' '
+			if r, ok := canvas.cells[engine.Pos{X: col, Y: row}]; ok {
+				line[col] = r
+			}
+		}
+		builder.WriteString(strings.TrimRight(string(line), " "))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// RenderText returns the whole board drawn onto a TextCanvas.
+func (game *Game) RenderText() string {
+	camera := game.Camera
+	text := NewTextCanvas()
+	game.Camera = Camera{}
+	renderer := CanvasRenderer{Canvas: text}
+	renderer.DrawBoard(game)
+	game.Camera = camera
+	return text.String()
+}
+
+// Snapshot writes the board and score as text to a new file in AutosaveDir
+// that is named after the current time and returns its path. Snapshots taken
+// in the same second are numbered, e.g. snail-20230501-120000-2.txt.
+// AutosaveDir is created if it does not exist.
+func (game *Game) Snapshot() (string, error) {
+	if err := os.MkdirAll(game.AutosaveDir, 0755); err != nil {
+		return "", err
+	}
+	base := game.Clock.Now().Format("snail-20060102-150405")
+	text := fmt.Sprintf("%sScore: %d\nSeed: %d\n", game.RenderText(), game.Score(), game.CurrentSeed())
+	for count := 1; ; count++ {
+		name := base + ".txt"
+		if count > 1 {
+			name = fmt.Sprintf("%s-%d.txt", base, count)
+		}
+		path := filepath.Join(game.AutosaveDir, name)
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = file.WriteString(text)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return path, err
+	}
+}
+
+// BoardSummary describes the layout of a board.
+type BoardSummary struct {
+	Seed      int64
+	Width     int
+	Height    int
+	Snail     []engine.Pos
+	Direction engine.Velocity
+	Food      engine.Pos
+	Obstacles []engine.Pos
+	// Portals are the two linked ends of every portal.
+	Portals [][2]engine.Pos
+}
+
+// Board returns the BoardSummary of the current board.
+func (game *Game) Board() BoardSummary {
+	return BoardSummary{
+		Seed:      game.CurrentSeed(),
+		Width:     game.XDim,
+		Height:    game.YDim,
+		Snail:     game.Snail().Body,
+		Direction: game.Snail().Direction,
+		Food:      game.Food(),
+		Obstacles: game.Obstacles(),
+		Portals:   game.PortalPairs(),
+	}
+}
+
+// DryRun sets up a game configured by options without a screen
+// and writes the starting board as text followed by a json summary to w.
+func (game *Game) DryRun(w io.Writer, options ...Option) error {
+	game.Apply(options...)
+	game.SetDefaults()
+	if err := game.ResetState(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(game.Board(), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", game.RenderText(), data)
+	return err
+}
+
+// savedGame holds a Game for json.
+type savedGame struct {
+	Game  *engine.Game
+	Delay time.Duration
+}
+
+// MarshalJSON stores the state of the engine game, see
+// engine.Game.MarshalJSON, together with the current delay of the game.
+func (game *Game) MarshalJSON() ([]byte, error) {
+	return json.Marshal(savedGame{Game: game.Game, Delay: game.GameDelayMilliSeconds})
+}
+
+// UnmarshalJSON restores a game stored with MarshalJSON. The next game loop
+// continues it instead of starting a new game, its config becomes the config
+// of the following games.
+func (game *Game) UnmarshalJSON(data []byte) error {
+	var saved savedGame
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	if saved.Game == nil {
+		return errors.New("the saved game has no state")
+	}
+	game.Game = saved.Game
+	game.config = saved.Game.Config
+	game.GameDelayMilliSeconds = saved.Delay
+	game.startDelay = saved.Delay
+	game.resume = true
+	return nil
+}
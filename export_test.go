@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/q713/snail/engine"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+func TestDryRunGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		portals int
+	}{
+		{"default", []Option{WithDimensions(10), WithSeed(3)}, 0},
+		{"walls", []Option{WithSize(14, 8), WithSeed(11), WithBounds(engine.BoundsWalls)}, 0},
+		{"portals", []Option{WithConfig(engine.Config{XDim: 12, YDim: 12, Seed: 5, ObstacleCount: 4, PortalPairs: 2})}, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := NewGame().DryRun(&out, test.options...); err != nil {
+				t.Fatal(err)
+			}
+			var again bytes.Buffer
+			if err := NewGame().DryRun(&again, test.options...); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != again.String() {
+				t.Fatalf("dry-run output differs between runs:\n%s\n%s", out.String(), again.String())
+			}
+			golden := filepath.Join("testdata", "dryrun_"+test.name+".golden")
+			if *update {
+				if err := os.MkdirAll("testdata", 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, out.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != string(want) {
+				t.Errorf("dry-run output:\n%s\nwant:\n%s", out.String(), want)
+			}
+			// the summary follows the board
+			data := out.Bytes()[bytes.IndexByte(out.Bytes(), '{'):]
+			var board BoardSummary
+			if err := json.Unmarshal(data, &board); err != nil {
+				t.Fatal(err)
+			}
+			if len(board.Portals) != test.portals {
+				t.Errorf("%d portals in the summary, want %d", len(board.Portals), test.portals)
+			}
+		})
+	}
+}
+
+func TestSnapshotSameSecond(t *testing.T) {
+	dir := t.TempDir()
+	game := newTextGame(t, WithDimensions(10), WithSeed(3), WithClock(newFakeClock()), WithOutputs(Outputs{AutosaveDir: dir}))
+	want := []string{"snail-20230501-120000.txt", "snail-20230501-120000-2.txt", "snail-20230501-120000-3.txt"}
+	for _, name := range want {
+		path, err := game.Snapshot()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != filepath.Join(dir, name) {
+			t.Errorf("snapshot written to %s, want %s", path, name)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(want) {
+		t.Errorf("%d snapshots in %s, want %d", len(entries), dir, len(want))
+	}
+}
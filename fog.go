@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// FogCanvas hides the cells farther than FogRadius moves from the head. Only
+// the snail is drawn there, dimmed, and food once it was seen, see
+// DiscoverFood.
+type FogCanvas struct {
+	Canvas
+	Game *Game
+}
+
+// Visible reports whether the cell at x, y is within FogRadius of the head.
+func (canvas FogCanvas) Visible(x, y int) bool {
+	game := canvas.Game
+	return game.Distance(game.Head(), engine.Pos{X: x, Y: y}) <= game.FogRadius
+}
+
+func (canvas FogCanvas) DrawCell(x, y int, kind CellKind, style tcell.Style) {
+	game := canvas.Game
+	switch {
+	case canvas.Visible(x, y):
+		canvas.Canvas.DrawCell(x, y, kind, style)
+	case kind == CellSnailBody || kind == CellSnailHead:
+		canvas.Canvas.DrawCell(x, y, kind, DimStyle(style))
+	case kind == CellFood && game.seenFood == engine.Pos{X: x, Y: y}:
+		canvas.Canvas.DrawCell(x, y, kind, DimStyle(style))
+	}
+}
+
+func (canvas FogCanvas) DrawRunes(x, y int, left, right rune, style tcell.Style) {
+	// runes draw the smooth snail and animations, they are dimmed like the
+	// snail
+	if !canvas.Visible(x, y) {
+		style = DimStyle(style)
+	}
+	canvas.Canvas.DrawRunes(x, y, left, right, style)
+}
+
+// DiscoverFood remembers the food as seen once it is within FogRadius of the
+// head, it stays visible through the fog from then on.
+func (game *Game) DiscoverFood() {
+	if game.FogRadius > 0 && game.Distance(game.Head(), game.Food()) <= game.FogRadius {
+		game.seenFood = game.Food()
+	}
+}
@@ -0,0 +1,115 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/q713/snail/engine"
+)
+
+// ghostPath returns the path of the recording of the best game played with
+// the same seed and settings as the current one in GhostDir.
+func (game *Game) ghostPath() (string, error) {
+	config := game.config
+	config.Seed = game.CurrentSeed()
+	config.Replay = nil
+	config.Autopilot = nil
+	data, err := json.Marshal(struct {
+		Config engine.Config
+		Key    HighScoreKey
+	}{config, game.HighScoreKey()})
+	if err != nil {
+		return "", err
+	}
+	hash := fnv.New64a()
+	hash.Write(data)
+	return filepath.Join(game.GhostDir, fmt.Sprintf("ghost-%016x.json", hash.Sum64())), nil
+}
+
+// LoadGhost reads the recording of the best game with the same seed and
+// settings, if there is one, to be raced against.
+func (game *Game) LoadGhost() error {
+	game.ghost = nil
+	path, err := game.ghostPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var ghost engine.Recording
+	if err := json.Unmarshal(data, &ghost); err != nil {
+		return fmt.Errorf("cannot read ghost %s: %w", path, err)
+	}
+	game.ghost = &ghost
+	return nil
+}
+
+// SaveGhost stores the recording of the last game as the ghost of its seed
+// and settings if it beat the score of the current ghost.
+func (game *Game) SaveGhost() error {
+	if game.ghost != nil && game.ghost.Score >= game.Score() {
+		return nil
+	}
+	path, err := game.ghostPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(game.GhostDir, 0755); err != nil {
+		return err
+	}
+	rec := game.Recording()
+	return rec.WriteFile(path)
+}
+
+// DrawGhost draws the snail of the ghost at the current tick dimmed, so the
+// snail and everything else on the board is drawn over it. Once the ghost's
+// game ended it is gone.
+func (renderer *CanvasRenderer) DrawGhost(game *Game) {
+	if game.ghost == nil || game.Tick() >= len(game.ghost.Ticks) {
+		return
+	}
+	canvas := DimCanvas{renderer.Canvas}
+	body := game.ghost.Ticks[game.Tick()].Body
+	for index, pos := range body {
+		if !game.OnLayer(pos) {
+			continue
+		}
+		kind := CellSnailBody
+		if index == len(body)-1 {
+			kind = CellSnailHead
+		}
+		canvas.DrawCell(pos.X, pos.Y, kind, snailBodySytle)
+	}
+}
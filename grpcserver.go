@@ -0,0 +1,231 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"github.com/q713/snail/engine"
+	"github.com/q713/snail/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GameServer serves the snail gRPC service. Every started game is a copy of
+// the template game it was created with, see sessionCopy, and runs headless
+// in real time.
+type GameServer struct {
+	rpc.UnimplementedSnailServer
+	template Game
+
+	// startMu serializes StartGame
+	startMu sync.Mutex
+
+	mu      sync.Mutex
+	game    *Game
+	cancel  context.CancelFunc
+	done    chan struct{}
+	state   *rpc.State
+	updated chan struct{}
+}
+
+// NewGameServer returns a GameServer that plays games like template
+// configured by options.
+func NewGameServer(template Game, options ...Option) *GameServer {
+	template.Apply(options...)
+	template.SetDefaults()
+	return &GameServer{template: template, updated: make(chan struct{})}
+}
+
+// Serve accepts gRPC connections on addr until it fails.
+func (server *GameServer) Serve(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	grpcServer := grpc.NewServer()
+	rpc.RegisterSnailServer(grpcServer, server)
+	return grpcServer.Serve(listener)
+}
+
+func (server *GameServer) StartGame(ctx context.Context, req *rpc.StartGameRequest) (*rpc.State, error) {
+	server.startMu.Lock()
+	defer server.startMu.Unlock()
+
+	server.mu.Lock()
+	if server.cancel != nil {
+		server.cancel()
+		done := server.done
+		server.mu.Unlock()
+		<-done
+		server.mu.Lock()
+	}
+	game := server.template.sessionCopy()
+	game.config.Seed = req.GetSeed()
+	game.Renderer = serverRenderer{server}
+	// a direction sent while the loop is busy waits for it
+	game.NextDirection = make(chan engine.Velocity, 1)
+	game.PauseChan = make(chan struct{})
+	loopCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	server.game, server.cancel, server.done = game, cancel, done
+	server.state = nil
+	updated := server.updated
+	server.mu.Unlock()
+
+	go func() {
+		game.Loop(loopCtx)
+		close(done)
+	}()
+	// wait for the first tick so the state belongs to the new game
+	select {
+	case <-updated:
+	case <-done:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+	return server.GetState(ctx, &rpc.GetStateRequest{})
+}
+
+func (server *GameServer) GetState(ctx context.Context, req *rpc.GetStateRequest) (*rpc.State, error) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.state == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no game started")
+	}
+	return server.state, nil
+}
+
+func (server *GameServer) SendDirection(ctx context.Context, req *rpc.SendDirectionRequest) (*rpc.SendDirectionResponse, error) {
+	dir := req.GetDirection()
+	if dir < 0 || int(dir) >= len(engine.Directions) {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown direction %d", dir)
+	}
+	server.mu.Lock()
+	game, done := server.game, server.done
+	server.mu.Unlock()
+	if game == nil {
+		return nil, status.Error(codes.FailedPrecondition, "no game started")
+	}
+	select {
+	case <-done:
+		return nil, status.Error(codes.FailedPrecondition, "game is over")
+	default:
+	}
+	// the loop queues the received direction for one of the next ticks, see
+	// QueueTurn, and takes none while the queue is full
+	select {
+	case game.NextDirection <- engine.Directions[dir]:
+		return &rpc.SendDirectionResponse{}, nil
+	default:
+		return nil, status.Error(codes.ResourceExhausted, "the game takes no more directions before the next tick")
+	}
+}
+
+func (server *GameServer) StateUpdates(req *rpc.StateUpdatesRequest, stream rpc.Snail_StateUpdatesServer) error {
+	var last *rpc.State
+	for {
+		server.mu.Lock()
+		state, updated := server.state, server.updated
+		server.mu.Unlock()
+		if state != nil && state != last {
+			if err := stream.Send(state); err != nil {
+				return err
+			}
+			if state.GetOver() {
+				return nil
+			}
+			last = state
+		}
+		select {
+		case <-updated:
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
+
+// publish replaces the state of the current game and wakes up everyone
+// waiting for it.
+func (server *GameServer) publish(game *Game) {
+	state := stateOf(game)
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.game != game {
+		// a stopped game still drawing its last tick
+		return
+	}
+	server.state = state
+	close(server.updated)
+	server.updated = make(chan struct{})
+}
+
+// stateOf converts the current state of game to its rpc message.
+func stateOf(game *Game) *rpc.State {
+	snail := game.Snail()
+	state := &rpc.State{
+		Width:      int32(game.XDim),
+		Height:     int32(game.YDim),
+		Food:       rpcPos(game.Food()),
+		Score:      int32(game.Score()),
+		Tick:       int32(game.Tick()),
+		FoodsEaten: int32(game.FoodsEaten()),
+		Seed:       game.CurrentSeed(),
+		Over:       game.Over(),
+	}
+	for _, pos := range snail.Body {
+		state.Body = append(state.Body, rpcPos(pos))
+	}
+	for i, dir := range engine.Directions {
+		if dir.Equals(snail.Direction) {
+			state.Direction = rpc.Direction(i)
+		}
+	}
+	for _, pos := range game.Obstacles() {
+		state.Obstacles = append(state.Obstacles, rpcPos(pos))
+	}
+	if state.Over {
+		state.Outcome = game.Outcome()
+	}
+	return state
+}
+
+func rpcPos(pos engine.Pos) *rpc.Pos {
+	return &rpc.Pos{X: int32(pos.X), Y: int32(pos.Y)}
+}
+
+// serverRenderer publishes the state of a game instead of drawing it.
+type serverRenderer struct {
+	server *GameServer
+}
+
+func (renderer serverRenderer) DrawBoard(game *Game) { renderer.server.publish(game) }
+func (renderer serverRenderer) DrawPause(game *Game) {}
+func (renderer serverRenderer) DrawGameOver(game *Game, won bool) {
+	renderer.server.publish(game)
+}
+func (renderer serverRenderer) Show() {}
+func (renderer serverRenderer) Fini() {}
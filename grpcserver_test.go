@@ -0,0 +1,133 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/q713/snail/engine"
+	"github.com/q713/snail/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stopGameServer stops the current game of server.
+func stopGameServer(server *GameServer) {
+	server.mu.Lock()
+	cancel, done := server.cancel, server.done
+	server.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// awaitServerTick waits until the state of the current game of server is at
+// least at tick.
+func awaitServerTick(t *testing.T, server *GameServer, tick int32) *rpc.State {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		state, err := server.GetState(context.Background(), &rpc.GetStateRequest{})
+		if err == nil && state.GetTick() >= tick {
+			return state
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("the game did not reach tick %d", tick)
+	return nil
+}
+
+func TestGameServer(t *testing.T) {
+	ctx := context.Background()
+	clock := newFakeClock()
+	server := NewGameServer(Game{}, WithDimensions(10), WithDelay(harnessDelay), WithClock(clock))
+	defer stopGameServer(server)
+	if _, err := server.GetState(ctx, &rpc.GetStateRequest{}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("GetState before the first game = %v, want %v", err, codes.FailedPrecondition)
+	}
+	if _, err := server.SendDirection(ctx, &rpc.SendDirectionRequest{Direction: rpc.Direction_NORTH}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("SendDirection before the first game = %v, want %v", err, codes.FailedPrecondition)
+	}
+
+	state, err := server.StartGame(ctx, &rpc.StartGameRequest{Seed: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.GetSeed() != 5 || state.GetWidth() != 10 || state.GetHeight() != 10 || state.GetOver() {
+		t.Fatalf("started %v, want a running 10x10 game with seed 5", state)
+	}
+	// directions beyond the queue of the loop are rejected instead of
+	// blocking until the next tick
+	sent := 0
+	for ; sent < 5; sent++ {
+		dir := []rpc.Direction{rpc.Direction_NORTH, rpc.Direction_WEST}[sent%2]
+		_, err := server.SendDirection(ctx, &rpc.SendDirectionRequest{Direction: dir})
+		if status.Code(err) == codes.ResourceExhausted {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sent == 5 || sent < 1 {
+		t.Fatalf("%d directions taken before the next tick, want one up to four", sent)
+	}
+	if _, err := server.SendDirection(ctx, &rpc.SendDirectionRequest{Direction: 7}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SendDirection of an unknown direction = %v, want %v", err, codes.InvalidArgument)
+	}
+
+	clock.Advance(harnessDelay)
+	state = awaitServerTick(t, server, state.GetTick()+1)
+	if state.GetDirection() != rpc.Direction_NORTH {
+		t.Errorf("snail heading %v after the turn, want %v", state.GetDirection(), rpc.Direction_NORTH)
+	}
+}
+
+func TestSessionCopy(t *testing.T) {
+	template := NewGame(WithConfig(engine.Config{XDim: 10, YDim: 10, Autopilot: &engine.Autopilot{ErrorRate: 0.2},
+		FoodScript: []engine.Pos{{X: 1, Y: 1}}}))
+	// the spare capacity would be shared by appending sessions
+	template.tick = make([]func(game *Game), 0, 4)
+	template.OnTick(func(game *Game) {})
+
+	first, second := template.sessionCopy(), template.sessionCopy()
+	if first.config.Autopilot == template.config.Autopilot || first.config.Autopilot == second.config.Autopilot {
+		t.Error("the sessions share the autopilot of the template")
+	}
+	if *first.config.Autopilot != *template.config.Autopilot {
+		t.Errorf("session autopilot %+v, want %+v", *first.config.Autopilot, *template.config.Autopilot)
+	}
+	first.config.FoodScript[0] = engine.Pos{X: 2, Y: 2}
+	if template.config.FoodScript[0] != (engine.Pos{X: 1, Y: 1}) {
+		t.Error("a session changed the food script of the template")
+	}
+	first.OnTick(func(game *Game) { t.Error("the hook of the first session ran in the second") })
+	second.OnTick(func(game *Game) {})
+	if len(template.tick) != 1 || len(first.tick) != 2 || len(second.tick) != 2 {
+		t.Fatalf("%d, %d and %d tick hooks, want 1, 2 and 2", len(template.tick), len(first.tick), len(second.tick))
+	}
+	second.runTick(second)
+}
@@ -0,0 +1,716 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// fakeClock is a Clock whose time only passes with Advance, so the ticks of
+// a game loop happen exactly when a test wants them to.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) After(d time.Duration) <-chan time.Time {
+	return clock.newTimer(d, 0).c
+}
+
+func (clock *fakeClock) NewTicker(d time.Duration) Ticker {
+	return clock.newTimer(d, d)
+}
+
+func (clock *fakeClock) newTimer(d, interval time.Duration) *fakeTimer {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	timer := &fakeTimer{clock: clock, c: make(chan time.Time, 1), at: clock.now.Add(d), interval: interval}
+	clock.timers = append(clock.timers, timer)
+	return timer
+}
+
+// Advance moves the time forward by d and fires the timers that are due. A
+// ticker fires at most once per Advance, like a time.Ticker whose receiver
+// is slow.
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+	clock.now = clock.now.Add(d)
+	active := clock.timers[:0]
+	for _, timer := range clock.timers {
+		if timer.stopped {
+			continue
+		}
+		if !timer.at.After(clock.now) {
+			select {
+			case timer.c <- clock.now:
+			default:
+			}
+			if timer.interval == 0 {
+				continue
+			}
+			for !timer.at.After(clock.now) {
+				timer.at = timer.at.Add(timer.interval)
+			}
+		}
+		active = append(active, timer)
+	}
+	clock.timers = active
+}
+
+// fakeTimer is a timer or, with an interval, a ticker of a fakeClock.
+type fakeTimer struct {
+	clock    *fakeClock
+	c        chan time.Time
+	at       time.Time
+	interval time.Duration
+	stopped  bool
+}
+
+func (timer *fakeTimer) C() <-chan time.Time {
+	return timer.c
+}
+
+func (timer *fakeTimer) Reset(d time.Duration) {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+	timer.at = timer.clock.now.Add(d)
+	timer.interval = d
+	if timer.stopped {
+		timer.stopped = false
+		timer.clock.timers = append(timer.clock.timers, timer)
+	}
+}
+
+func (timer *fakeTimer) Stop() {
+	timer.clock.mu.Lock()
+	defer timer.clock.mu.Unlock()
+	timer.stopped = true
+}
+
+// harnessDelay is the delay between two ticks of the games of a harness.
+const harnessDelay = 100 * time.Millisecond
+
+// harness plays a game on a tcell.SimulationScreen with a fakeClock. The
+// game runs with Play on a goroutine of its own; the test injects keys,
+// advances the clock tick by tick and reads the screen and the state of the
+// game in between.
+type harness struct {
+	t      *testing.T
+	game   *Game
+	screen tcell.SimulationScreen
+	clock  *fakeClock
+	cancel context.CancelFunc
+	// steps receives once the game loop took a tick or the game ended
+	steps    chan struct{}
+	done     chan error
+	stopOnce sync.Once
+}
+
+// newHarness starts a game on a 10x10 board with seed 3, runes and a delay of
+// harnessDelay, changed by options, and waits for its first tick. Options may
+// replace the screen by another SimulationScreen.
+func newHarness(t *testing.T, options ...Option) *harness {
+	t.Helper()
+	clock := newFakeClock()
+	h := &harness{
+		t:     t,
+		clock: clock,
+		steps: make(chan struct{}),
+		done:  make(chan error, 1),
+	}
+	defaults := []Option{WithDimensions(10), WithSeed(3), WithRunes(RunesOn), WithDelay(harnessDelay), WithScreen(tcell.NewSimulationScreen(""))}
+	h.game = NewGame(append(append(defaults, options...), WithClock(clock))...)
+	h.screen = h.game.Screen.(tcell.SimulationScreen)
+	h.game.OnTick(func(*Game) { h.steps <- struct{}{} })
+	h.game.OnGameOver(func(*Game, bool) { h.steps <- struct{}{} })
+	if err := h.game.Start(); err != nil {
+		t.Fatal(err)
+	}
+	// the whole board fits, so the camera is off
+	h.screen.SetSize(80, 40)
+	ctx, cancel := context.WithCancel(context.Background())
+	h.cancel = cancel
+	go func() {
+		h.done <- h.game.Play(ctx)
+	}()
+	t.Cleanup(h.stop)
+	h.wait()
+	h.sync()
+	return h
+}
+
+// wait waits for the next tick or the end of the game.
+func (h *harness) wait() {
+	h.t.Helper()
+	select {
+	case <-h.steps:
+	case <-time.After(5 * time.Second):
+		h.t.Fatal("the game loop did not tick")
+	}
+}
+
+// tick advances the clock to the next tick and waits until the board of the
+// tick was drawn.
+func (h *harness) tick() {
+	h.t.Helper()
+	h.clock.Advance(h.delay())
+	h.wait()
+	h.sync()
+}
+
+// delay returns the delay before the next tick.
+func (h *harness) delay() time.Duration {
+	var delay time.Duration
+	h.do(func(game *Game) { delay = game.TickDelay() })
+	return delay
+}
+
+// do runs action on the game loop and waits for it. Once the loop returned,
+// action runs right away.
+func (h *harness) do(action func(game *Game)) {
+	done := make(chan struct{})
+	h.game.Do(func(game *Game) {
+		action(game)
+		close(done)
+	})
+	<-done
+}
+
+// sync waits until the game loop waits for the next tick, i.e. the board of
+// the last one is shown.
+func (h *harness) sync() {
+	h.do(func(*Game) {})
+}
+
+// waitFor waits until cond holds for the game, e.g. because an injected key
+// was handled.
+func (h *harness) waitFor(what string, cond func(game *Game) bool) {
+	h.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		ok := false
+		h.do(func(game *Game) { ok = cond(game) })
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// key injects a key press into the screen.
+func (h *harness) key(key tcell.Key, r rune) {
+	h.screen.InjectKey(key, r, tcell.ModNone)
+}
+
+// turn presses the key of dir and waits until the loop queued the turn.
+func (h *harness) turn(key tcell.Key) {
+	h.t.Helper()
+	h.key(key, 0)
+	h.waitFor("the turn to be queued", func(game *Game) bool { return len(game.turns) > 0 })
+}
+
+// state returns the state of the game.
+func (h *harness) state() State {
+	var state State
+	h.do(func(game *Game) { state = game.State() })
+	return state
+}
+
+// cell returns the two runes drawn for the cell at x, y of the board.
+func (h *harness) cell(x, y int) string {
+	return h.text(h.game.Padding+x*2+1, h.game.Padding+y+1, 2)
+}
+
+// style returns the style the left column of the cell at x, y of the board
+// is drawn with.
+func (h *harness) style(x, y int) tcell.Style {
+	_, _, style, _ := h.screen.GetContent(h.game.Padding+x*2+1, h.game.Padding+y+1)
+	return style
+}
+
+// text returns the n runes drawn from col on in row.
+func (h *harness) text(col, row, n int) string {
+	var text strings.Builder
+	for i := 0; i < n; i++ {
+		r, _, _, _ := h.screen.GetContent(col+i, row)
+		text.WriteRune(r)
+	}
+	return text.String()
+}
+
+// screenText returns all rows of the screen.
+func (h *harness) screenText() string {
+	cells, width, height := h.screen.GetContents()
+	var text strings.Builder
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			runes := cells[row*width+col].Runes
+			if len(runes) == 0 {
+				text.WriteRune(' ')
+				continue
+			}
+			text.WriteRune(runes[0])
+		}
+		text.WriteRune('\n')
+	}
+	return text.String()
+}
+
+// contains reports whether text is shown anywhere on the screen.
+func (h *harness) contains(text string) bool {
+	return strings.Contains(h.screenText(), text)
+}
+
+// quit presses Escape and returns the error the game quit with.
+func (h *harness) quit() error {
+	h.t.Helper()
+	h.key(tcell.KeyEscape, 0)
+	select {
+	case err := <-h.done:
+		h.done <- err
+		return err
+	case <-time.After(5 * time.Second):
+		h.t.Fatal("the game did not quit")
+		return nil
+	}
+}
+
+// stop ends the game if the test did not quit it. It may be called more
+// than once.
+func (h *harness) stop() {
+	h.stopOnce.Do(func() {
+		h.cancel()
+		// the loop may be waiting to report a tick nobody waits for anymore
+		for {
+			select {
+			case <-h.done:
+				return
+			case <-h.steps:
+			}
+		}
+	})
+}
+
+// cellsOf returns the board positions at which runes are drawn, sorted by row
+// and column.
+func (h *harness) cellsOf(runes string) []engine.Pos {
+	var cells []engine.Pos
+	for y := 0; y < h.game.config.YDim; y++ {
+		for x := 0; x < h.game.config.XDim; x++ {
+			if h.cell(x, y) == runes {
+				cells = append(cells, engine.Pos{X: x, Y: y})
+			}
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		return cells[i].Y < cells[j].Y || cells[i].Y == cells[j].Y && cells[i].X < cells[j].X
+	})
+	return cells
+}
+
+func TestHarnessFirstBoard(t *testing.T) {
+	h := newHarness(t)
+	var head, food engine.Pos
+	h.do(func(game *Game) { head, food = game.Head(), game.Food() })
+	if got := h.cell(head.X, head.Y); got != "@@" {
+		t.Errorf("head cell %v = %q, want %q", head, got, "@@")
+	}
+	if got := h.cell(food.X, food.Y); got != "**" {
+		t.Errorf("food cell %v = %q, want %q", food, got, "**")
+	}
+	if heads := h.cellsOf("@@"); len(heads) != 1 {
+		t.Errorf("heads drawn at %v, want one", heads)
+	}
+	if state := h.state(); state != StatePlaying {
+		t.Errorf("state = %v, want %v", state, StatePlaying)
+	}
+}
+
+func TestHarnessTurn(t *testing.T) {
+	tests := []struct {
+		name string
+		key  tcell.Key
+		dir  engine.Velocity
+	}{
+		{"up", tcell.KeyUp, engine.Velocity{X: 0, Y: -1}},
+		{"down", tcell.KeyDown, engine.Velocity{X: 0, Y: 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newHarness(t)
+			var before engine.Pos
+			h.do(func(game *Game) { before = game.Head() })
+			h.turn(test.key)
+			h.tick()
+			var head engine.Pos
+			var dir engine.Velocity
+			h.do(func(game *Game) { head, dir = game.Head(), game.Snail().Direction })
+			if !dir.Equals(test.dir) {
+				t.Fatalf("direction = %v, want %v", dir, test.dir)
+			}
+			want := engine.Pos{X: before.X, Y: (before.Y + test.dir.Y + 10) % 10}
+			if head != want {
+				t.Errorf("head = %v, want %v", head, want)
+			}
+			if got := h.cell(head.X, head.Y); got != "@@" {
+				t.Errorf("head cell %v = %q, want %q", head, got, "@@")
+			}
+			if got := h.cell(before.X, before.Y); got != "oo" {
+				t.Errorf("old head cell %v = %q, want %q", before, got, "oo")
+			}
+		})
+	}
+}
+
+func TestHarnessPause(t *testing.T) {
+	h := newHarness(t)
+	h.key(tcell.KeyRune, 'p')
+	h.waitFor("the pause", func(game *Game) bool { return game.State() == StatePaused })
+	var tick int
+	h.do(func(game *Game) { tick = game.Tick() })
+	for i := 0; i < 3; i++ {
+		h.clock.Advance(harnessDelay)
+		h.sync()
+	}
+	h.do(func(game *Game) {
+		if game.Tick() != tick {
+			t.Errorf("tick = %d while paused, want %d", game.Tick(), tick)
+		}
+	})
+	h.key(tcell.KeyRune, 'p')
+	h.waitFor("the resume", func(game *Game) bool { return game.State() == StatePlaying })
+	h.tick()
+	h.do(func(game *Game) {
+		if game.Tick() != tick+1 {
+			t.Errorf("tick = %d after resuming, want %d", game.Tick(), tick+1)
+		}
+	})
+}
+
+func TestHarnessQuit(t *testing.T) {
+	h := newHarness(t)
+	h.tick()
+	if err := h.quit(); err != nil {
+		t.Fatalf("Play returned %v, want nil", err)
+	}
+}
+
+func TestHarnessGameOver(t *testing.T) {
+	h := newHarness(t, WithBounds(engine.BoundsWalls), WithDelay(harnessDelay))
+	for i := 0; h.state() != StateGameOver; i++ {
+		if i > 20 {
+			t.Fatal("the snail did not run into a wall")
+		}
+		h.clock.Advance(harnessDelay)
+		h.wait()
+		h.sync()
+	}
+	if !h.contains("Game Over") {
+		t.Errorf("game over screen not shown:\n%s", h.screenText())
+	}
+	h.key(tcell.KeyRune, 'n')
+	select {
+	case err := <-h.done:
+		h.done <- err
+		if err != nil {
+			t.Fatalf("Play returned %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the game did not quit")
+	}
+}
+
+func TestHarnessInputLogReplay(t *testing.T) {
+	tests := []struct {
+		name   string
+		config engine.Config
+		delay  time.Duration
+		rules  string
+	}{
+		{"default", engine.Config{XDim: 10, YDim: 10}, harnessDelay, ""},
+		{"zen bounce", engine.Config{XDim: 12, YDim: 10, Bounds: engine.BoundsBounce, Rules: engine.ZenRules{}}, 120 * time.Millisecond, "zen"},
+		{"classic walls", engine.Config{XDim: 14, YDim: 11, Bounds: engine.BoundsWalls, Rules: engine.DefaultRules{}}, 180 * time.Millisecond, "classic"},
+		{"obstacles portals", engine.Config{XDim: 12, YDim: 12, Growth: 2, ObstacleCount: 10, PortalPairs: 2, FoodMinMoves: 3,
+			Lives: 1, PoisonChance: 50, GoldenChance: 50, EatRule: engine.EatTouch}, harnessDelay, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "inputs.log")
+			config := test.config
+			config.Seed = 3
+			h := newHarness(t, WithConfig(config), WithDelay(test.delay), WithOutputs(Outputs{InputLogPath: path}))
+			for _, key := range []tcell.Key{tcell.KeyUp, tcell.KeyLeft, tcell.KeyDown} {
+				h.turn(key)
+				h.tick()
+				h.tick()
+			}
+			var snail engine.Snail
+			var food engine.Pos
+			var score, tick int
+			h.do(func(game *Game) { snail, food, score, tick = game.Snail(), game.Food(), game.Score(), game.Tick() })
+			if err := h.quit(); err != nil {
+				t.Fatal(err)
+			}
+
+			inputLog, err := engine.ReadInputLogFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inputLog.Seed != 3 || inputLog.Delay != test.delay || inputLog.Rules != test.rules {
+				t.Fatalf("logged seed %d delay %v rules %q, want seed 3 delay %v rules %q",
+					inputLog.Seed, inputLog.Delay, inputLog.Rules, test.delay, test.rules)
+			}
+			logged := *inputLog.Config
+			logged.Rules, config.Rules = nil, nil
+			logged.ScoreWeight, logged.Scoring = 0, ""
+			if config.Growth == 0 {
+				config.Growth = engine.DefaultGrowth
+			}
+			if !reflect.DeepEqual(logged, config) {
+				t.Fatalf("logged config %+v, want %+v", logged, config)
+			}
+			if len(inputLog.Events) != 3 {
+				t.Fatalf("logged %v, want three turns", inputLog.Events)
+			}
+
+			// the replay only knows the log
+			replayConfig := engine.Config{Replay: &inputLog}
+			if inputLog.Rules != "" {
+				if replayConfig.Rules, err = engine.LookupRules(inputLog.Rules); err != nil {
+					t.Fatal(err)
+				}
+			}
+			replay, err := engine.New(replayConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for replay.Tick() < tick {
+				if running, err := replay.Step(); err != nil || !running {
+					t.Fatalf("replay stopped at tick %d: %v", replay.Tick(), err)
+				}
+			}
+			if !reflect.DeepEqual(replay.Snail(), snail) || replay.Food() != food || replay.Score() != score {
+				t.Errorf("replayed snail %v food %v score %d, want snail %v food %v score %d",
+					replay.Snail().Body, replay.Food(), replay.Score(), snail.Body, food, score)
+			}
+		})
+	}
+}
+
+func TestHarnessPadding(t *testing.T) {
+	for _, padding := range []int{0, 1, 3} {
+		h := newHarness(t, WithTheme(Theme{Runes: RunesOn, Padding: padding}))
+		if corner := h.text(padding, padding, 1); corner != string(tcell.RuneULCorner) {
+			t.Errorf("padding %d: top-left corner is %q, want %q", padding, corner, string(tcell.RuneULCorner))
+		}
+		if corner := h.text(padding+20+2, padding+10+1, 1); corner != string(tcell.RuneLRCorner) {
+			t.Errorf("padding %d: bottom-right corner is %q, want %q", padding, corner, string(tcell.RuneLRCorner))
+		}
+		if padding > 0 {
+			if margin := h.text(0, 0, padding); strings.TrimSpace(margin) != "" {
+				t.Errorf("padding %d: margin shows %q", padding, margin)
+			}
+		}
+		var head engine.Pos
+		h.do(func(game *Game) { head = game.Head() })
+		if got := h.cell(head.X, head.Y); got != "@@" {
+			t.Errorf("padding %d: head cell %v = %q, want %q", padding, head, got, "@@")
+		}
+		h.stop()
+	}
+}
+
+func TestHarnessPauseDims(t *testing.T) {
+	h := newHarness(t, WithRunes(RunesOff))
+	// move the snail away from the row of the pause text
+	h.turn(tcell.KeyUp)
+	for i := 0; i < 3; i++ {
+		h.tick()
+	}
+	var snail engine.Snail
+	h.do(func(game *Game) { snail = game.Snail() })
+	head := snail.Body[len(snail.Body)-1]
+	tail := snail.Body[0]
+	tests := []struct {
+		state     State
+		head      tcell.Style
+		body      tcell.Style
+		pauseText bool
+	}{
+		{StatePlaying, snailHeadSytle, snailBodySytle, false},
+		{StatePaused, DimStyle(snailHeadSytle), DimStyle(snailBodySytle), true},
+		{StatePlaying, snailHeadSytle, snailBodySytle, false},
+	}
+	for index, test := range tests {
+		if index > 0 {
+			h.key(tcell.KeyRune, 'p')
+			h.waitFor(test.state.String(), func(game *Game) bool { return game.State() == test.state })
+		}
+		if test.state == StatePlaying && index > 0 {
+			// the board is drawn again on the next tick
+			h.tick()
+			h.do(func(game *Game) { snail = game.Snail() })
+			head, tail = snail.Body[len(snail.Body)-1], snail.Body[0]
+		}
+		if got := h.style(head.X, head.Y); got != test.head {
+			t.Errorf("%d %v: head drawn with %v, want %v", index, test.state, got, test.head)
+		}
+		if got := h.style(tail.X, tail.Y); got != test.body {
+			t.Errorf("%d %v: tail drawn with %v, want %v", index, test.state, got, test.body)
+		}
+		if shown := h.contains("Paused"); shown != test.pauseText {
+			t.Errorf("%d %v: pause text shown %v, want %v", index, test.state, shown, test.pauseText)
+		}
+	}
+}
+
+func TestHarnessBoardFullWins(t *testing.T) {
+	// the snail covers three of the five cells and fills the others with two
+	// foods
+	h := newHarness(t, WithConfig(engine.Config{XDim: 5, YDim: 1, Seed: 3, Growth: 1}))
+	for i := 0; h.state() != StateGameOver; i++ {
+		if i > 20 {
+			t.Fatal("the snail did not fill the board")
+		}
+		h.tick()
+	}
+	var won bool
+	var length int
+	h.do(func(game *Game) {
+		won = game.WonGame()
+		length = len(game.Snail().Body)
+	})
+	if !won || length != 5 {
+		t.Fatalf("game over with %d segments, won %t, want a won game with 5 segments", length, won)
+	}
+	if !h.contains("WON!") {
+		t.Errorf("won game over screen not shown:\n%s", h.screenText())
+	}
+	if err := h.quit(); err != nil {
+		t.Errorf("Play returned %v, want nil", err)
+	}
+}
+
+func TestHarnessAutosave(t *testing.T) {
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		dir  string
+		ok   bool
+	}{
+		{"missing dir", filepath.Join(t.TempDir(), "shots"), true},
+		{"unwritable dir", filepath.Join(blocker, "shots"), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newHarness(t, WithSize(20, 10), WithBounds(engine.BoundsWalls), WithOutputs(Outputs{AutosaveDir: test.dir}))
+			for i := 0; h.state() != StateGameOver; i++ {
+				if i > 20 {
+					t.Fatal("the snail did not run into a wall")
+				}
+				h.tick()
+			}
+			name := h.clock.Now().Format("snail-20060102-150405.txt")
+			data, err := os.ReadFile(filepath.Join(test.dir, name))
+			if !test.ok {
+				if err == nil {
+					t.Error("snapshot written into a file")
+				}
+				if !h.contains("Snapshot not saved!") {
+					t.Errorf("failed snapshot not shown:\n%s", h.screenText())
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				text := string(data)
+				if !strings.Contains(text, "oooo@@") || !strings.HasSuffix(text, "Score: 0\nSeed: 3\n") {
+					t.Errorf("snapshot %s:\n%s", name, text)
+				}
+				if h.contains("Snapshot not saved!") {
+					t.Errorf("saved snapshot shown as failed:\n%s", h.screenText())
+				}
+			}
+			if err := h.quit(); err != nil {
+				t.Errorf("Play returned %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestHarnessMaxDuration(t *testing.T) {
+	h := newHarness(t, WithSize(20, 10), WithMaxDuration(950*time.Millisecond, false))
+	start := h.clock.Now()
+	for i := 0; i < 9; i++ {
+		h.tick()
+	}
+	// the limit is reached between two ticks, the next one ends the game
+	h.clock.Advance(harnessDelay / 2)
+	h.waitFor("the time limit", func(game *Game) bool { return game.TimeUp() })
+	if state := h.state(); state != StatePlaying {
+		t.Fatalf("state %s before the next tick, want playing", state)
+	}
+	h.clock.Advance(harnessDelay / 2)
+	h.wait()
+	h.sync()
+	if state := h.state(); state != StateGameOver {
+		t.Fatalf("state %s after the time limit, want game over", state)
+	}
+	if played := h.clock.Now().Sub(start); played != time.Second {
+		t.Errorf("game ended after %v, want 1s", played)
+	}
+	var died bool
+	h.do(func(game *Game) { died = game.Died() })
+	if died || !h.contains("Game Over, time limit reached!") {
+		t.Errorf("died %t, time limit not shown:\n%s", died, h.screenText())
+	}
+}
@@ -0,0 +1,114 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/q713/snail/engine"
+)
+
+// HeadlessRenderer is a Renderer that does not show anything.
+type HeadlessRenderer struct{}
+
+func (HeadlessRenderer) DrawBoard(game *Game)              {}
+func (HeadlessRenderer) DrawPause(game *Game)              {}
+func (HeadlessRenderer) DrawGameOver(game *Game, won bool) {}
+func (HeadlessRenderer) Show()                             {}
+func (HeadlessRenderer) Fini()                             {}
+
+// Summary describes the result of the last game in a single line.
+func (game *Game) Summary() string {
+	return fmt.Sprintf("seed %d score %d moves %d foods %d %s",
+		game.CurrentSeed(), game.Score(), game.Tick(), game.FoodsEaten(), game.Outcome())
+}
+
+// RunHeadless plays a single game configured by options in real time without
+// a screen and writes its Summary to w. The game is controlled by Input, the
+// Autopilot or a Replay; every direction of Input is applied on a tick of its
+// own and the game is stopped once Input quits.
+func (game *Game) RunHeadless(w io.Writer, options ...Option) error {
+	game.Apply(options...)
+	if game.Input == nil && game.config.Autopilot == nil && game.config.Replay == nil {
+		return errors.New("a headless game needs a controller")
+	}
+	game.SetDefaults()
+	game.Renderer = HeadlessRenderer{}
+	game.NextDirection = make(chan engine.Velocity)
+	game.PauseChan = make(chan struct{})
+	game.actions = make(chan func(game *Game))
+	ctx, cancel := context.WithCancel(context.Background())
+	game.StartLoop(ctx)
+	if game.Input != nil {
+		go game.pumpCommands(ctx, cancel)
+	}
+	<-game.loopDone
+	cancel()
+	if game.err != nil {
+		return game.err
+	}
+	if _, err := fmt.Fprintln(w, game.Summary()); err != nil {
+		return err
+	}
+	if game.PrintBreakdown {
+		if err := game.WriteBreakdown(w); err != nil {
+			return err
+		}
+	}
+	return game.SaveRecording()
+}
+
+// pumpCommands passes the commands of Input on to the game loop until ctx is
+// done. Other than SendDirection it waits for the loop to take a direction,
+// so no direction of a script is dropped.
+func (game *Game) pumpCommands(ctx context.Context, cancel context.CancelFunc) {
+	for {
+		command := game.Input.NextCommand()
+		if dir, ok := commandDirections[command]; ok {
+			select {
+			case game.NextDirection <- dir:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		if dir, ok := rivalCommandDirections[command]; ok {
+			game.Do(func(game *Game) { game.QueueRivalTurn(dir) })
+			continue
+		}
+		switch command {
+		case CommandQuit:
+			cancel()
+			return
+		case CommandPause:
+			select {
+			case game.PauseChan <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
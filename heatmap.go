@@ -0,0 +1,107 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// heatRunes are the runes the visits of a cell are drawn with, from rarely to
+// most visited.
+var heatRunes = []rune{'░', '▒', '▓', tcell.RuneBlock}
+
+// heatStyles are the styles of the heatRunes.
+var heatStyles = []tcell.Style{
+	tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorNavy),
+	tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorGreen),
+	tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorYellow),
+	tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorRed),
+}
+
+// heatDigits are the characters WriteHeatmap writes for the visits of a cell,
+// from unvisited to most visited.
+const heatDigits = ".123456789"
+
+// heatLevel scales count visits to one of levels levels, the most visited
+// cell reaching the highest one.
+func heatLevel(count, hottest, levels int) int {
+	return min((count*levels-1)/hottest, levels-1)
+}
+
+// DrawHeatmap draws how often the head visited each cell of the last game in
+// place of the board, the most visited cells in red.
+func (renderer *CanvasRenderer) DrawHeatmap(game *Game) {
+	renderer.DrawBorder(game)
+	rec := game.Recording()
+	heat := rec.Heatmap()
+	hottest, visits := engine.Hottest(heat)
+	for y, row := range heat {
+		for x, count := range row {
+			if count == 0 {
+				continue
+			}
+			level := heatLevel(count, visits, len(heatStyles))
+			renderer.Canvas.DrawRunes(x, y, heatRunes[level], heatRunes[level], heatStyles[level])
+		}
+	}
+	text := fmt.Sprintf("Most visited: %d,%d %dx Back? h Play Again? y/n", hottest.X, hottest.Y, visits)
+	renderer.Canvas.DrawText(1, 0, text, blackWhiteStyle)
+}
+
+// ToggleHeatmap switches between the game over screen and the heatmap once
+// the game is over.
+func (game *Game) ToggleHeatmap() {
+	game.heatmapShown = !game.heatmapShown
+	game.breakdownShown = false
+	game.achievementsShown = false
+	if !game.heatmapShown {
+		game.Renderer.DrawBoard(game)
+	}
+	game.Renderer.DrawGameOver(game, game.WonGame())
+	game.Renderer.Show()
+}
+
+// WriteHeatmap writes heat to w as one line of digits per row, 1 to 9 from
+// rarely to most visited and a dot for unvisited cells, followed by the most
+// visited cell.
+func WriteHeatmap(w io.Writer, heat [][]int) error {
+	hottest, visits := engine.Hottest(heat)
+	for _, row := range heat {
+		line := make([]byte, len(row))
+		for x, count := range row {
+			line[x] = heatDigits[0]
+			if count > 0 {
+				line[x] = heatDigits[1+heatLevel(count, visits, len(heatDigits)-1)]
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", line); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "most visited cell %d,%d with %d visits\n", hottest.X, hottest.Y, visits)
+	return err
+}
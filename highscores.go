@@ -0,0 +1,281 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/q713/snail/engine"
+)
+
+// highScoreCount is the number of scores kept per high score table.
+const highScoreCount = 10
+
+// highScoreRows is the number of scores shown on the game over screen.
+const highScoreRows = 5
+
+// HighScoreKey tells apart the high score tables of the games that are
+// comparable: played in the same mode on the same board at the same
+// difficulty.
+type HighScoreKey struct {
+	Mode       string
+	Width      int
+	Height     int
+	Difficulty string `json:",omitempty"`
+}
+
+func (key HighScoreKey) String() string {
+	text := fmt.Sprintf("%s %dx%d", key.Mode, key.Width, key.Height)
+	if key.Difficulty != "" {
+		text += " " + key.Difficulty
+	}
+	return text
+}
+
+// HighScore is an entry of a high score table.
+type HighScore struct {
+	HighScoreKey
+	// Player is the name of the player in the tables of an Arcade.
+	Player string `json:",omitempty"`
+	Score  int
+	Date   time.Time
+	Length int
+}
+
+// HighScores are the high score tables of all keys, the scores of each key
+// best first.
+type HighScores []HighScore
+
+// DataPath returns the path of the file name in the XDG data directory,
+// $XDG_DATA_HOME/snail or, if that is not set, ~/.local/share/snail. It is
+// empty if neither is known.
+func DataPath(name string) string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "snail", name)
+}
+
+// LoadHighScores reads the high score table at path. A missing file is an
+// empty table.
+func LoadHighScores(path string) (HighScores, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var scores HighScores
+	if err := json.Unmarshal(data, &scores); err != nil {
+		return nil, fmt.Errorf("cannot read high scores %s: %w", path, err)
+	}
+	return scores, nil
+}
+
+// Table returns the scores of the table of key, best first.
+func (scores HighScores) Table(key HighScoreKey) HighScores {
+	var table HighScores
+	for _, score := range scores {
+		if score.HighScoreKey == key {
+			table = append(table, score)
+		}
+	}
+	return table
+}
+
+// Add enters score into the table of its key and returns all tables and the
+// index of score in its table, or -1 if it did not make it into the best
+// highScoreCount. Among equal scores the older one ranks higher.
+func (scores HighScores) Add(score HighScore) (HighScores, int) {
+	table := scores.Table(score.HighScoreKey)
+	index := sort.Search(len(table), func(i int) bool { return table[i].Score < score.Score })
+	if index >= highScoreCount {
+		return scores, -1
+	}
+	added := make(HighScores, 0, len(scores)+1)
+	for _, other := range scores {
+		if other.HighScoreKey != score.HighScoreKey {
+			added = append(added, other)
+		}
+	}
+	added = append(added, table[:index]...)
+	added = append(added, score)
+	added = append(added, table[index:min(len(table), highScoreCount-1)]...)
+	return added, index
+}
+
+// WriteFile stores the table as json in the file at path, creating its
+// directory if needed.
+func (scores HighScores) WriteFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(scores, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ModeName names the mode the game is played in for the high score table,
+// e.g. "classic" or "daily zen walls obstacles 10". Everything that changes
+// how hard the board is, like its edges, obstacles and level, is part of it.
+func (game *Game) ModeName() string {
+	var modes []string
+	if game.Daily {
+		modes = append(modes, "daily")
+	}
+	if game.Campaign != nil {
+		modes = append(modes, "campaign")
+	}
+	if game.TimeAttack {
+		modes = append(modes, "time-attack")
+	}
+	switch game.Config.Rules.(type) {
+	case engine.ZenRules:
+		modes = append(modes, "zen")
+	case engine.ReverseRules:
+		modes = append(modes, "reverse")
+	}
+	if game.Config.GrowthRule == engine.GrowTrail {
+		modes = append(modes, "tron")
+	}
+	if game.Config.Layers > 1 {
+		modes = append(modes, "layers")
+	}
+	switch {
+	case game.Config.Dual:
+		modes = append(modes, "dual")
+	case game.Config.Versus:
+		modes = append(modes, "versus")
+	case game.Config.TwoPlayers:
+		modes = append(modes, "two-players")
+	}
+	if game.Config.Bounds != engine.BoundsWrap {
+		modes = append(modes, game.Config.Bounds.String())
+	}
+	if game.Mirror != MirrorOff {
+		modes = append(modes, "mirror "+game.Mirror.String())
+	}
+	if game.Config.Level != nil {
+		modes = append(modes, "level "+levelID(game.Config.Level))
+	} else if obstacles := game.Config.Obstacles(); obstacles > 0 {
+		modes = append(modes, fmt.Sprintf("obstacles %d", obstacles))
+	}
+	if len(modes) == 0 {
+		return "classic"
+	}
+	return strings.Join(modes, " ")
+}
+
+// levelID tells levels apart by a hash of their board.
+func levelID(level *engine.Level) string {
+	data, _ := json.Marshal(level)
+	hash := fnv.New32a()
+	hash.Write(data)
+	return fmt.Sprintf("%08x", hash.Sum32())
+}
+
+// Difficulty names the speed the game is played at for the high score
+// tables, the delay it starts with and, in hardcore mode, the minimum delay.
+func (game *Game) Difficulty() string {
+	difficulty := fmt.Sprintf("%dms", game.startDelay.Milliseconds())
+	if game.MinDelay > 0 {
+		difficulty += fmt.Sprintf(" hardcore %dms", game.MinDelay.Milliseconds())
+	}
+	return difficulty
+}
+
+// HighScoreKey returns the key of the high score table of the game.
+func (game *Game) HighScoreKey() HighScoreKey {
+	return HighScoreKey{
+		Mode:       game.ModeName(),
+		Width:      game.XDim,
+		Height:     game.YDim,
+		Difficulty: game.Difficulty(),
+	}
+}
+
+// RecordHighScore enters the score of the last game into the table of its
+// HighScoreKey in the file at HighScorePath. The table is kept for the game
+// over screen.
+func (game *Game) RecordHighScore() error {
+	scores, err := LoadHighScores(game.HighScorePath)
+	if err != nil {
+		return err
+	}
+	key := game.HighScoreKey()
+	game.personalBest = -1
+	if table := scores.Table(key); len(table) > 0 {
+		game.personalBest = table[0].Score
+	}
+	scores, game.highScoreRank = scores.Add(HighScore{
+		HighScoreKey: key,
+		Score:        game.Score(),
+		Date:         game.Clock.Now(),
+		Length:       game.Length(),
+	})
+	game.highScores = scores.Table(key)
+	if game.highScoreRank < 0 {
+		return nil
+	}
+	return scores.WriteFile(game.HighScorePath)
+}
+
+// DrawHighScores draws the best scores of the high score table from row on
+// and marks the one of the last game.
+func (renderer *CanvasRenderer) DrawHighScores(game *Game, row int) {
+	width, _ := game.ViewSize()
+	texts := []string{"High scores " + game.HighScoreKey().String() + ":"}
+	for index, score := range game.highScores {
+		if index >= highScoreRows {
+			break
+		}
+		text := fmt.Sprintf("%d. %d, length %d, %s", index+1, score.Score, score.Length, score.Date.Format(time.DateOnly))
+		if index == game.highScoreRank {
+			text = "> " + text + " <"
+		}
+		texts = append(texts, text)
+	}
+	if game.highScoreRank >= highScoreRows {
+		texts = append(texts, fmt.Sprintf("Your score ranks %d.", game.highScoreRank+1))
+	}
+	for index, text := range texts {
+		renderer.Canvas.DrawText(width-len(text)/2+1, row+index, text, blackWhiteStyle)
+	}
+}
@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/q713/snail/engine"
+)
+
+func TestModeName(t *testing.T) {
+	level := &engine.Level{Width: 10, Height: 10, Walls: []engine.Pos{{X: 1, Y: 1}}}
+	otherLevel := &engine.Level{Width: 10, Height: 10, Walls: []engine.Pos{{X: 2, Y: 2}}}
+	tests := []struct {
+		name    string
+		options []Option
+		want    string
+	}{
+		{"classic", nil, "classic"},
+		{"zen", []Option{WithConfig(engine.Config{Rules: engine.ZenRules{}})}, "zen"},
+		{"walls", []Option{WithBounds(engine.BoundsWalls)}, "walls"},
+		{"bounce", []Option{WithBounds(engine.BoundsBounce)}, "bounce"},
+		{"mirror", []Option{WithControls(Controls{Mirror: MirrorBoth})}, "mirror all"},
+		{"obstacles", []Option{WithConfig(engine.Config{ObstacleCount: 12})}, "obstacles 12"},
+		{"density", []Option{WithConfig(engine.Config{ObstacleDensity: 10})}, "obstacles 10"},
+		{"level", []Option{WithLevel(level)}, "level " + levelID(level)},
+		{"daily", []Option{WithDaily(), WithConfig(engine.Config{ObstacleCount: 10}), WithBounds(engine.BoundsWalls)},
+			"daily walls obstacles 10"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := NewGame(append(test.options, WithDimensions(10), WithSeed(1))...)
+			if err := game.ResetState(); err != nil {
+				t.Fatal(err)
+			}
+			if got := game.ModeName(); got != test.want {
+				t.Errorf("ModeName() = %q, want %q", got, test.want)
+			}
+		})
+	}
+	if levelID(level) == levelID(otherLevel) {
+		t.Error("two levels have the same id")
+	}
+}
+
+func TestHighScoresAdd(t *testing.T) {
+	small := HighScoreKey{Mode: "classic", Width: 10, Height: 10, Difficulty: "150ms"}
+	walls := HighScoreKey{Mode: "walls", Width: 10, Height: 10, Difficulty: "150ms"}
+	date := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	var scores HighScores
+	for score := 1; score <= highScoreCount; score++ {
+		scores, _ = scores.Add(HighScore{HighScoreKey: small, Score: score * 10, Date: date})
+	}
+	scores, rank := scores.Add(HighScore{HighScoreKey: walls, Score: 5, Date: date})
+	if rank != 0 {
+		t.Errorf("the first score of a table ranks %d", rank)
+	}
+	tests := []struct {
+		score int
+		want  int
+	}{
+		{1000, 0},
+		{55, 5},
+		{10, -1},
+	}
+	for _, test := range tests {
+		added, rank := scores.Add(HighScore{HighScoreKey: small, Score: test.score, Date: date})
+		if rank != test.want {
+			t.Errorf("score %d ranks %d, want %d", test.score, rank, test.want)
+		}
+		if got := len(added.Table(small)); got != highScoreCount {
+			t.Errorf("the table has %d scores after adding %d, want %d", got, test.score, highScoreCount)
+		}
+		if got := added.Table(walls); len(got) != 1 || got[0].Score != 5 {
+			t.Errorf("adding %d changed the walls table to %v", test.score, got)
+		}
+	}
+}
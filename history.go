@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// GameRecord is the result of a single game in the history.
+type GameRecord struct {
+	Time    time.Time
+	Seed    int64
+	Width   int
+	Height  int
+	Mode    string
+	Score   int
+	Length  int
+	Seconds float64
+	Outcome string
+}
+
+var historyHeader = []string{"time", "seed", "width", "height", "mode", "score", "length", "seconds", "outcome"}
+
+// AppendHistory appends the result of the last game as a json line to the
+// history at HistoryPath, creating its directory if needed.
+func (game *Game) AppendHistory() error {
+	data, err := json.Marshal(GameRecord{
+		Time:    game.Clock.Now(),
+		Seed:    game.CurrentSeed(),
+		Width:   game.XDim,
+		Height:  game.YDim,
+		Mode:    game.ModeName(),
+		Score:   game.Score(),
+		Length:  game.Length(),
+		Seconds: game.survived.Seconds(),
+		Outcome: game.Outcome(),
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(game.HistoryPath), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(game.HistoryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	// a single write keeps the lines of games ending at the same time apart
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// ReadHistory reads the games recorded in the history at path. A missing
+// file is an empty history.
+func ReadHistory(path string) ([]GameRecord, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var records []GameRecord
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		var record GameRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("cannot read history %s line %d: %w", path, line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// ExportHistory writes records to w in the given format, csv with a header
+// row or json.
+func ExportHistory(w io.Writer, records []GameRecord, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if records == nil {
+			records = []GameRecord{}
+		}
+		return encoder.Encode(records)
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write(historyHeader); err != nil {
+			return err
+		}
+		for _, record := range records {
+			err := writer.Write([]string{
+				record.Time.Format(time.RFC3339),
+				strconv.FormatInt(record.Seed, 10),
+				strconv.Itoa(record.Width),
+				strconv.Itoa(record.Height),
+				record.Mode,
+				strconv.Itoa(record.Score),
+				strconv.Itoa(record.Length),
+				strconv.FormatFloat(record.Seconds, 'f', 1, 64),
+				record.Outcome,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+	return fmt.Errorf("unknown export format %q, the formats are csv and json", format)
+}
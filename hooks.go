@@ -0,0 +1,121 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import "slices"
+
+// Hooks holds the callbacks that are run when something happens in a game,
+// so overlays and logging can be attached without changing the game loop.
+// Callbacks run on the goroutine of the game loop in the order they were
+// added.
+type Hooks struct {
+	foodEaten   []func(game *Game)
+	death       []func(game *Game)
+	tick        []func(game *Game)
+	scoreChange []func(game *Game, old int)
+	pause       []func(game *Game, paused bool)
+	gameOver    []func(game *Game, won bool)
+}
+
+// clone returns a copy of the hooks, callbacks added to either are not added
+// to the other.
+func (hooks Hooks) clone() Hooks {
+	return Hooks{
+		foodEaten:   slices.Clip(hooks.foodEaten),
+		death:       slices.Clip(hooks.death),
+		tick:        slices.Clip(hooks.tick),
+		scoreChange: slices.Clip(hooks.scoreChange),
+		pause:       slices.Clip(hooks.pause),
+		gameOver:    slices.Clip(hooks.gameOver),
+	}
+}
+
+// OnFoodEaten adds a callback that is run after the snail ate food.
+func (hooks *Hooks) OnFoodEaten(callback func(game *Game)) {
+	hooks.foodEaten = append(hooks.foodEaten, callback)
+}
+
+// OnDeath adds a callback that is run once the snail died.
+func (hooks *Hooks) OnDeath(callback func(game *Game)) {
+	hooks.death = append(hooks.death, callback)
+}
+
+// OnTick adds a callback that is run after every tick of a running game.
+func (hooks *Hooks) OnTick(callback func(game *Game)) {
+	hooks.tick = append(hooks.tick, callback)
+}
+
+// OnScoreChange adds a callback that is run whenever the score changed. It
+// receives the score before the change.
+func (hooks *Hooks) OnScoreChange(callback func(game *Game, old int)) {
+	hooks.scoreChange = append(hooks.scoreChange, callback)
+}
+
+// OnPause adds a callback that is run whenever the game is paused or resumed.
+func (hooks *Hooks) OnPause(callback func(game *Game, paused bool)) {
+	hooks.pause = append(hooks.pause, callback)
+}
+
+// OnGameOver adds a callback that is run once a game ended, however it ended.
+func (hooks *Hooks) OnGameOver(callback func(game *Game, won bool)) {
+	hooks.gameOver = append(hooks.gameOver, callback)
+}
+
+func (hooks *Hooks) runFoodEaten(game *Game) {
+	for _, callback := range hooks.foodEaten {
+		callback(game)
+	}
+}
+
+func (hooks *Hooks) runDeath(game *Game) {
+	for _, callback := range hooks.death {
+		callback(game)
+	}
+}
+
+func (hooks *Hooks) runTick(game *Game) {
+	for _, callback := range hooks.tick {
+		callback(game)
+	}
+}
+
+func (hooks *Hooks) runScoreChange(game *Game, old int) {
+	if game.Score() == old {
+		return
+	}
+	for _, callback := range hooks.scoreChange {
+		callback(game, old)
+	}
+}
+
+func (hooks *Hooks) runPause(game *Game, paused bool) {
+	for _, callback := range hooks.pause {
+		callback(game, paused)
+	}
+}
+
+func (hooks *Hooks) runGameOver(game *Game, won bool) {
+	for _, callback := range hooks.gameOver {
+		callback(game, won)
+	}
+}
@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// Command is an instruction of the player to the game.
+type Command int
+
+const (
+	CommandNorth Command = iota
+	CommandSouth
+	CommandEast
+	CommandWest
+	CommandPause
+	// CommandQuit quits the game at any time.
+	CommandQuit
+	// CommandYes and CommandNo answer whether to play again once the game
+	// is over.
+	CommandYes
+	CommandNo
+	CommandSuspend
+	CommandGridlines
+	CommandBreakdown
+	CommandAchievements
+	CommandHeatmap
+	// CommandRivalNorth to CommandRivalWest steer the snail of the second
+	// player in two-player games.
+	CommandRivalNorth
+	CommandRivalSouth
+	CommandRivalEast
+	CommandRivalWest
+)
+
+var commandNames = map[string]Command{
+	engine.InputNorth: CommandNorth,
+	engine.InputSouth: CommandSouth,
+	engine.InputEast:  CommandEast,
+	engine.InputWest:  CommandWest,
+	engine.InputPause: CommandPause,
+	"quit":            CommandQuit,
+	"yes":             CommandYes,
+	"no":              CommandNo,
+	"suspend":         CommandSuspend,
+	"gridlines":       CommandGridlines,
+	"breakdown":       CommandBreakdown,
+	"achievements":    CommandAchievements,
+	"heatmap":         CommandHeatmap,
+
+	engine.InputRival + engine.InputNorth: CommandRivalNorth,
+	engine.InputRival + engine.InputSouth: CommandRivalSouth,
+	engine.InputRival + engine.InputEast:  CommandRivalEast,
+	engine.InputRival + engine.InputWest:  CommandRivalWest,
+}
+
+var commandDirections = map[Command]engine.Velocity{
+	CommandNorth: engine.NorthDir,
+	CommandSouth: engine.SouthDir,
+	CommandEast:  engine.EastDir,
+	CommandWest:  engine.WestDir,
+}
+
+var rivalCommandDirections = map[Command]engine.Velocity{
+	CommandRivalNorth: engine.NorthDir,
+	CommandRivalSouth: engine.SouthDir,
+	CommandRivalEast:  engine.EastDir,
+	CommandRivalWest:  engine.WestDir,
+}
+
+// rivalKeys are the keys of the second player in two-player games, the first
+// player keeps WASD.
+var rivalKeys = map[tcell.Key]Command{
+	tcell.KeyUp:    CommandRivalNorth,
+	tcell.KeyDown:  CommandRivalSouth,
+	tcell.KeyRight: CommandRivalEast,
+	tcell.KeyLeft:  CommandRivalWest,
+}
+
+// InputSource emits the commands that control the game.
+type InputSource interface {
+	// NextCommand blocks until the next command is available.
+	NextCommand() Command
+}
+
+// KeyboardInput reads the commands from the keys pressed on a tcell screen
+// and from Commands posted to it as interrupt events. With TwoPlayers the
+// arrow keys steer the snail of the second player.
+type KeyboardInput struct {
+	Screen     tcell.Screen
+	TwoPlayers bool
+}
+
+func (input KeyboardInput) NextCommand() Command {
+	for {
+		switch event := input.Screen.PollEvent().(type) {
+		case nil:
+			// the screen was finalized
+			return CommandQuit
+		case *tcell.EventResize:
+			input.Screen.Sync()
+		case *tcell.EventKey:
+			if command, ok := rivalKeys[event.Key()]; ok && input.TwoPlayers {
+				return command
+			}
+			if command, ok := keyCommand(event); ok {
+				return command
+			}
+		case *tcell.EventInterrupt:
+			if command, ok := event.Data().(Command); ok {
+				return command
+			}
+		}
+	}
+}
+
+func keyCommand(event *tcell.EventKey) (Command, bool) {
+	switch {
+	case event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC:
+		return CommandQuit, true
+	case event.Key() == tcell.KeyUp || event.Rune() == 'w':
+		return CommandNorth, true
+	case event.Key() == tcell.KeyDown || event.Rune() == 's':
+		return CommandSouth, true
+	case event.Key() == tcell.KeyLeft || event.Rune() == 'a':
+		return CommandWest, true
+	case event.Key() == tcell.KeyRight || event.Rune() == 'd':
+		return CommandEast, true
+	case event.Key() == tcell.KeyCtrlZ:
+		return CommandSuspend, true
+	case event.Rune() == 'p':
+		return CommandPause, true
+	case event.Rune() == 'y':
+		return CommandYes, true
+	case event.Rune() == 'n':
+		return CommandNo, true
+	case event.Rune() == 'g':
+		return CommandGridlines, true
+	case event.Rune() == 'b':
+		return CommandBreakdown, true
+	case event.Rune() == 't':
+		return CommandAchievements, true
+	case event.Rune() == 'h':
+		return CommandHeatmap, true
+	}
+	return 0, false
+}
+
+// ReaderInput reads one command name per line, e.g. "north" or "pause", so
+// the game can be driven by scripts, pipes and sockets. Lines that are not a
+// command are skipped. Once the reader is used up the game is quit.
+type ReaderInput struct {
+	scanner *bufio.Scanner
+}
+
+func NewReaderInput(r io.Reader) *ReaderInput {
+	return &ReaderInput{scanner: bufio.NewScanner(r)}
+}
+
+func (input *ReaderInput) NextCommand() Command {
+	for input.scanner.Scan() {
+		if command, ok := commandNames[strings.TrimSpace(input.scanner.Text())]; ok {
+			return command
+		}
+	}
+	return CommandQuit
+}
+
+// OpenCommandFile returns a ReaderInput for the file or named pipe at path.
+// The file stays open until the program exits.
+func OpenCommandFile(path string) (*ReaderInput, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReaderInput(file), nil
+}
@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/q713/snail/engine"
+)
+
+// OnLayer reports whether pos is on the layer of the head, the only layer
+// drawn on the board.
+func (game *Game) OnLayer(pos engine.Pos) bool {
+	return pos.Layer == game.Head().Layer
+}
+
+// minimapRunes are the runes the cells of the minimap are drawn with, one
+// screen column per cell.
+var minimapRunes = map[CellKind]rune{
+	CellSnailBody: 'o',
+	CellFood:      '*',
+	CellObstacle:  '#',
+	CellPortal:    'O',
+	CellLadder:    'H',
+}
+
+// DrawMinimap draws the layers the head is not on to the right of the board,
+// one below the other.
+func (renderer *CanvasRenderer) DrawMinimap(game *Game) {
+	if game.Layers < 2 {
+		return
+	}
+	cells := make(map[engine.Pos]CellKind)
+	for _, pos := range game.Obstacles() {
+		cells[pos] = CellObstacle
+	}
+	for _, pos := range game.Portals() {
+		cells[pos] = CellPortal
+		if game.IsLadder(pos) {
+			cells[pos] = CellLadder
+		}
+	}
+	cells[game.Food()] = CellFood
+	for _, rival := range game.Rivals() {
+		for _, pos := range rival.Snail.Body {
+			cells[pos] = CellSnailBody
+		}
+	}
+	for _, pos := range game.Snail().Body {
+		cells[pos] = CellSnailBody
+	}
+	width, _ := game.ViewSize()
+	col, row := 2*width+5, 0
+	for layer := 0; layer < game.Layers; layer++ {
+		if layer == game.Head().Layer {
+			continue
+		}
+		renderer.Canvas.DrawText(col, row, fmt.Sprintf("Layer %d", layer+1), blackWhiteStyle)
+		for y := 0; y < game.YDim; y++ {
+			line := make([]rune, game.XDim)
+			for x := range line {
+				line[x] = '.'
+				if kind, ok := cells[engine.Pos{X: x, Y: y, Layer: layer}]; ok {
+					line[x] = minimapRunes[kind]
+				}
+			}
+			renderer.Canvas.DrawText(col, row+1+y, string(line), blackWhiteStyle)
+		}
+		row += game.YDim + 2
+	}
+}
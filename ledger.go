@@ -0,0 +1,81 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+var ledgerHeader = []string{"time", "seed", "width", "height", "delay", "score", "moves", "foods", "outcome"}
+
+// AppendLedger appends the result of the last game as a row to the csv file
+// at LedgerPath, see ledgerPath. A header row is written first if the file is
+// new or empty.
+func (game *Game) AppendLedger() error {
+	path := game.ledgerPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	if info.Size() == 0 {
+		if err := writer.Write(ledgerHeader); err != nil {
+			return err
+		}
+	}
+	err = writer.Write([]string{
+		game.Clock.Now().Format(time.RFC3339),
+		strconv.FormatInt(game.CurrentSeed(), 10),
+		strconv.Itoa(game.XDim),
+		strconv.Itoa(game.YDim),
+		strconv.FormatInt(game.startDelay.Milliseconds(), 10),
+		strconv.Itoa(game.Score()),
+		strconv.Itoa(game.Tick()),
+		strconv.Itoa(game.FoodsEaten()),
+		game.Outcome(),
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	// a single write keeps the rows of games ending at the same time apart
+	_, err = file.Write(buffer.Bytes())
+	return err
+}
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+func TestHarnessLedger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats", "ledger.csv")
+	h := newHarness(t, WithSize(20, 10), WithBounds(engine.BoundsWalls), WithOutputs(Outputs{LedgerPath: path}))
+	want := [][]string{ledgerHeader}
+	for game := 0; game < 2; game++ {
+		if game > 0 {
+			h.key(tcell.KeyRune, 'y')
+			h.wait()
+			h.sync()
+		}
+		for i := 0; h.state() != StateGameOver; i++ {
+			if i > 20 {
+				t.Fatal("the snail did not run into a wall")
+			}
+			h.tick()
+		}
+		h.do(func(game *Game) {
+			want = append(want, []string{h.clock.Now().Format(time.RFC3339), "3", "20", "10", "100",
+				strconv.Itoa(game.Score()), strconv.Itoa(game.Tick()), strconv.Itoa(game.FoodsEaten()), "died"})
+		})
+		// the second game ends later
+		h.clock.Advance(time.Minute)
+	}
+	if err := h.quit(); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ledger rows:\n%q\nwant:\n%q", rows, want)
+	}
+}
@@ -0,0 +1,188 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// TestLoopConcurrency calls Do and SendDirection from goroutines of their own
+// while the clock drives the loop and restarts the loop with StartLoop in
+// between. Run it with -race.
+func TestLoopConcurrency(t *testing.T) {
+	tests := []struct {
+		name   string
+		rounds int
+		calls  int
+	}{
+		{"one round", 1, 200},
+		{"restarts", 4, 50},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := newFakeClock()
+			game := NewGame(WithDimensions(10), WithSeed(3), WithDelay(harnessDelay),
+				WithScreen(tcell.NewSimulationScreen("")), WithClock(clock))
+			if err := game.Start(); err != nil {
+				t.Fatal(err)
+			}
+			finished := make(chan struct{})
+			go func() {
+				defer close(finished)
+				actions := 0
+				for round := 0; round < test.rounds; round++ {
+					ctx, cancel := context.WithCancel(context.Background())
+					game.StartLoop(ctx)
+					var wg sync.WaitGroup
+					wg.Add(3)
+					go func() {
+						defer wg.Done()
+						for i := 0; i < test.calls; i++ {
+							game.Do(func(*Game) { actions++ })
+						}
+					}()
+					go func() {
+						defer wg.Done()
+						for i := 0; i < test.calls; i++ {
+							game.SendDirection(engine.Directions[i%len(engine.Directions)])
+						}
+					}()
+					go func() {
+						defer wg.Done()
+						for i := 0; i < test.calls; i++ {
+							clock.Advance(harnessDelay)
+						}
+					}()
+					wg.Wait()
+					cancel()
+					<-game.loopDone
+				}
+				if want := test.rounds * test.calls; actions != want {
+					t.Errorf("%d actions ran, want %d", actions, want)
+				}
+			}()
+			select {
+			case <-finished:
+			case <-time.After(10 * time.Second):
+				t.Fatal("the loop did not finish")
+			}
+		})
+	}
+}
+
+func TestJitter(t *testing.T) {
+	const base = 100 * time.Millisecond
+	for _, percent := range []int{0, 10, 50} {
+		game := newTextGame(t, WithDimensions(10), WithSeed(3), WithDelay(base), WithJitter(percent))
+		game.jitterRand = rand.New(rand.NewSource(42))
+		low, high := base-base*time.Duration(percent)/100, base+base*time.Duration(percent)/100
+		shortest, longest, total := time.Duration(math.MaxInt64), time.Duration(0), time.Duration(0)
+		const ticks = 2000
+		for i := 0; i < ticks; i++ {
+			delay := game.TickDelay()
+			if delay < low || delay > high {
+				t.Fatalf("jitter %d%%: delay %v out of %v to %v", percent, delay, low, high)
+			}
+			shortest, longest, total = min(shortest, delay), max(longest, delay), total+delay
+		}
+		if percent > 0 && (shortest >= base || longest <= base) {
+			t.Errorf("jitter %d%%: delays from %v to %v do not vary around %v", percent, shortest, longest, base)
+		}
+		// the average is within 2% of the delay
+		if average := total / ticks; average < base*98/100 || average > base*102/100 {
+			t.Errorf("jitter %d%%: average delay %v, want about %v", percent, average, base)
+		}
+	}
+}
+
+func TestSendDirectionDebounce(t *testing.T) {
+	north, east := engine.NorthDir, engine.EastDir
+	tests := []struct {
+		name     string
+		debounce time.Duration
+		// gap is the time between two inputs
+		gap    time.Duration
+		inputs []engine.Velocity
+		ready  int
+		want   int
+	}{
+		{"burst", 50 * time.Millisecond, time.Millisecond, []engine.Velocity{north, north, north, north, north}, 5, 1},
+		{"burst without debounce", 0, time.Millisecond, []engine.Velocity{north, north, north, north, north}, 5, 5},
+		{"repeats after the window", 50 * time.Millisecond, 60 * time.Millisecond, []engine.Velocity{north, north, north}, 5, 3},
+		{"other directions", 50 * time.Millisecond, time.Millisecond, []engine.Velocity{north, east, north}, 5, 3},
+		// sends do not block while the loop does not take them
+		{"loop busy", 50 * time.Millisecond, time.Millisecond, []engine.Velocity{north, east, north}, 1, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := newFakeClock()
+			game := NewGame(WithClock(clock), WithControls(Controls{Debounce: test.debounce}))
+			game.NextDirection = make(chan engine.Velocity, test.ready)
+			for _, dir := range test.inputs {
+				game.SendDirection(dir)
+				clock.Advance(test.gap)
+			}
+			if sent := len(game.NextDirection); sent != test.want {
+				t.Errorf("%d directions sent, want %d", sent, test.want)
+			}
+		})
+	}
+}
+
+func TestHarnessKeyRepeat(t *testing.T) {
+	h := newHarness(t, WithControls(Controls{Debounce: 50 * time.Millisecond}))
+	for i := 0; i < 20; i++ {
+		h.key(tcell.KeyUp, 0)
+	}
+	h.waitFor("the turn to be queued", func(game *Game) bool { return len(game.turns) > 0 })
+	// the keys are handled in order, so all repeats were handled once the
+	// game is paused
+	h.key(tcell.KeyRune, 'p')
+	h.waitFor("the pause", func(game *Game) bool { return game.State() == StatePaused })
+	h.do(func(game *Game) {
+		if len(game.turns) != 1 {
+			t.Errorf("%d turns queued, want 1", len(game.turns))
+		}
+	})
+	h.key(tcell.KeyRune, 'p')
+	h.waitFor("the resume", func(game *Game) bool { return game.State() == StatePlaying })
+	var before engine.Pos
+	h.do(func(game *Game) { before = game.Head() })
+	h.tick()
+	h.do(func(game *Game) {
+		if head := game.Head(); head.X != before.X || head.Y != before.Y-1 {
+			t.Errorf("head moved from %v to %v, want one cell up", before, head)
+		}
+		if len(game.turns) > 0 {
+			t.Errorf("%d turns left after the tick", len(game.turns))
+		}
+	})
+}
@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/q713/snail/engine"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// LuaScript is an engine.Script that calls the global functions of a Lua
+// script. Every function is optional and gets a table describing the game as
+// its last argument, with the fields width, height, tick, score, foods,
+// length, head and food, where positions are tables with x and y counted
+// from 0:
+//
+//	on_tick(game)                          -- after every tick
+//	place_food(x, y, game) -> x, y         -- the cell new food is placed on
+//	score(points, steps, distance, game) -> points
+//	                                       -- the points for eaten food
+type LuaScript struct {
+	state *lua.LState
+}
+
+// OpenLuaScript runs the Lua script in the file at path.
+func OpenLuaScript(path string) (*LuaScript, error) {
+	state := lua.NewState()
+	if err := state.DoFile(path); err != nil {
+		state.Close()
+		return nil, err
+	}
+	return &LuaScript{state: state}, nil
+}
+
+// Close frees the interpreter of the script.
+func (script *LuaScript) Close() {
+	script.state.Close()
+}
+
+func (script *LuaScript) Tick(game *engine.Game) error {
+	_, err := script.call("on_tick", 0, script.gameTable(game))
+	return err
+}
+
+func (script *LuaScript) PlaceFood(game *engine.Game, pos engine.Pos) (engine.Pos, error) {
+	results, err := script.call("place_food", 2, lua.LNumber(pos.X), lua.LNumber(pos.Y), script.gameTable(game))
+	if err != nil || results == nil {
+		return pos, err
+	}
+	x, xOk := results[0].(lua.LNumber)
+	y, yOk := results[1].(lua.LNumber)
+	if !xOk || !yOk {
+		return pos, fmt.Errorf("place_food returned %v, %v instead of two numbers", results[0], results[1])
+	}
+	return engine.Pos{X: int(x), Y: int(y)}, nil
+}
+
+func (script *LuaScript) Score(game *engine.Game, food engine.FoodScore) (int, error) {
+	results, err := script.call("score", 1, lua.LNumber(food.Points), lua.LNumber(food.Steps), lua.LNumber(food.Distance), script.gameTable(game))
+	if err != nil || results == nil {
+		return food.Points, err
+	}
+	points, ok := results[0].(lua.LNumber)
+	if !ok {
+		return food.Points, fmt.Errorf("score returned %v instead of a number", results[0])
+	}
+	return int(points), nil
+}
+
+// call calls the global function name with args and returns its results. The
+// results are nil if the script does not define the function.
+func (script *LuaScript) call(name string, results int, args ...lua.LValue) ([]lua.LValue, error) {
+	function, ok := script.state.GetGlobal(name).(*lua.LFunction)
+	if !ok {
+		return nil, nil
+	}
+	if err := script.state.CallByParam(lua.P{Fn: function, NRet: results, Protect: true}, args...); err != nil {
+		return nil, err
+	}
+	values := make([]lua.LValue, results)
+	for i := results - 1; i >= 0; i-- {
+		values[i] = script.state.Get(-1)
+		script.state.Pop(1)
+	}
+	return values, nil
+}
+
+// gameTable returns the table describing game that is passed to the
+// functions of the script.
+func (script *LuaScript) gameTable(game *engine.Game) *lua.LTable {
+	table := script.state.NewTable()
+	table.RawSetString("width", lua.LNumber(game.XDim))
+	table.RawSetString("height", lua.LNumber(game.YDim))
+	table.RawSetString("tick", lua.LNumber(game.Tick()))
+	table.RawSetString("score", lua.LNumber(game.Score()))
+	table.RawSetString("foods", lua.LNumber(game.FoodsEaten()))
+	table.RawSetString("length", lua.LNumber(game.Length()))
+	table.RawSetString("head", script.posTable(game.Head()))
+	table.RawSetString("food", script.posTable(game.Food()))
+	return table
+}
+
+func (script *LuaScript) posTable(pos engine.Pos) *lua.LTable {
+	table := script.state.NewTable()
+	table.RawSetString("x", lua.LNumber(pos.X))
+	table.RawSetString("y", lua.LNumber(pos.Y))
+	return table
+}
@@ -24,14 +24,13 @@ package main
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
 	"github.com/gdamore/tcell/v2"
-	"log"
-	"math"
+	"github.com/q713/snail/engine"
 	"math/rand"
 	"os"
+	"runtime/trace"
 	"time"
 )
 
@@ -40,22 +39,46 @@ func ErrExit(err error) {
 		return
 	}
 	fmt.Printf("%s", err)
-	os.Exit(1)
+	Exit(1)
 }
 
-type Velocity struct {
-	X int
-	Y int
+// Clock abstracts the passage of time so the game can be driven without
+// real delays.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
 }
 
-func (me *Velocity) Equals(other Velocity) bool {
-	return me.X == other.X && me.Y == other.Y
+// Ticker delivers ticks at a fixed interval like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Reset(d time.Duration)
+	Stop()
 }
 
-var NorthDir = Velocity{X: 0, Y: -1}
-var SouthDir = Velocity{X: 0, Y: 1}
-var EastDir = Velocity{X: 1, Y: 0}
-var WestDir = Velocity{X: -1, Y: 0}
+// RealClock is the Clock backed by the time package.
+type RealClock struct{}
+
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (ticker realTicker) C() <-chan time.Time {
+	return ticker.Ticker.C
+}
 
 var blackWhiteStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
 var backStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
@@ -63,353 +86,997 @@ var snailBodySytle = tcell.StyleDefault.Background(tcell.ColorWhite).Foreground(
 var wallStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorBlue)
 var snailHeadSytle = tcell.StyleDefault.Background(tcell.ColorGreen).Foreground(tcell.ColorGreen)
 var foodStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorRed)
+var deathStyle = tcell.StyleDefault.Background(tcell.ColorRed).Foreground(tcell.ColorRed)
+var graceHeadStyle = tcell.StyleDefault.Background(tcell.ColorYellow).Foreground(tcell.ColorYellow)
+var gridStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorDarkGray)
+var foodHintStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorMaroon)
+var wrapAnimStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorGreen)
+var powerUpStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorFuchsia)
+var effectStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorAquaMarine)
+var goldenStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorGold)
+var poisonStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorPurple)
+var portalStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorAqua)
+var rivalStyle = tcell.StyleDefault.Background(tcell.ColorTeal).Foreground(tcell.ColorTeal)
+var ladderStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorYellow)
 
-type Pos struct {
-	X int
-	Y int
+// dimStyles maps the styles of the board to the variants used while paused.
+var dimStyles = map[tcell.Style]tcell.Style{
+	blackWhiteStyle: tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorGray),
+	snailBodySytle:  tcell.StyleDefault.Background(tcell.ColorGray).Foreground(tcell.ColorGray),
+	wallStyle:       tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorNavy),
+	snailHeadSytle:  tcell.StyleDefault.Background(tcell.ColorDarkGreen).Foreground(tcell.ColorDarkGreen),
+	foodStyle:       tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorMaroon),
+	deathStyle:      tcell.StyleDefault.Background(tcell.ColorMaroon).Foreground(tcell.ColorMaroon),
+	wrapAnimStyle:   tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorDarkGreen),
 }
 
-type Scorer struct {
-	Score             int
-	movesSinceLastInc int
-	weight            int
-	gridWidth         int
-	gridHeight        int
-	maxPoints         int
-	OldHeadPos        Pos
-	OldFoodPos        Pos
+// DimStyle returns the dimmed variant of style.
+func DimStyle(style tcell.Style) tcell.Style {
+	if dimmed, ok := dimStyles[style]; ok {
+		return dimmed
+	}
+	return style.Dim(true)
 }
 
-func (scorer *Scorer) Step() {
-	scorer.movesSinceLastInc += 1
+// WrapAnimation holds the render-only state of the animation shown at the
+// edges of the board after the snail wrapped around.
+type WrapAnimation struct {
+	Exit       engine.Pos
+	Entry      engine.Pos
+	Direction  engine.Velocity
+	FramesLeft int
 }
 
-func (scorer *Scorer) ResetSteps() {
-	scorer.movesSinceLastInc = 0
+type Game struct {
+	*engine.Game
+	// config is the config every new game is started with, see WithConfig
+	config                engine.Config
+	NextDirection         chan engine.Velocity
+	PauseChan             chan struct{}
+	state                 State
+	Screen                tcell.Screen
+	GameDelayMilliSeconds time.Duration
+	WrapAnimFrames        int
+	wrapAnim              WrapAnimation
+	RecordPath            string
+	DeathAnimFrames       int
+	Clock                 Clock
+	InputLogPath          string
+	Padding               int
+	Renderer              Renderer
+	Input                 InputSource
+	Camera                Camera
+	PrintBreakdown        bool
+	breakdownShown        bool
+	heatmapShown          bool
+	Runes                 RuneMode
+	AutosaveDir           string
+	snapshotErr           error
+	LedgerPath            string
+	ledgerErr             error
+	HighScorePath         string
+	highScores            HighScores
+	highScoreRank         int
+	highScoreErr          error
+	personalBest          int
+	HistoryPath           string
+	historyErr            error
+	GhostDir              string
+	ghost                 *engine.Recording
+	ghostErr              error
+	survived              time.Duration
+	startDelay            time.Duration
+	Trainer               int
+	PerfectFlash          int
+	perfectLeft           int
+	Jitter                int
+	jitterRand            *rand.Rand
+	Gridlines             bool
+	SmoothSnake           bool
+	MaxDuration           time.Duration
+	TimeAttack            bool
+	MinDelay              time.Duration
+	Mirror                Mirror
+	Daily                 bool
+	FogRadius             int
+	seenFood              engine.Pos
+	countdown             *Countdown
+	ScoreTiers            ScoreTiers
+	TierStyles            TierStyles
+	Debounce              time.Duration
+	lastInput             engine.Velocity
+	lastInputAt           time.Time
+	SavePath              string
+	Campaign              *Campaign
+	campaignErr           error
+	// Arcade, if set, holds the best scores shown on the game over screen.
+	Arcade *Arcade
+	// Achievements, if set, are unlocked by the games played.
+	Achievements      *Achievements
+	achievementsShown bool
+	// earned are the achievements unlocked in the current game
+	earned         []Achievement
+	achievementErr error
+	toastLeft      int
+	hasWrapped     bool
+	// turns are the queued directions of the game loop, see QueueTurn
+	turns []engine.Velocity
+	// rivalTurns are the queued directions of the second player, see
+	// QueueRivalTurn
+	rivalTurns []engine.Velocity
+	// actions are run by the game loop, see Do
+	actions chan func(game *Game)
+	// resume is set while the next game loop continues the game restored
+	// with UnmarshalJSON instead of starting a new one
+	resume bool
+	// loopDone is closed once the game loop returned, it is only used by
+	// the goroutine that started the loop
+	loopDone chan struct{}
+	// err is the error the game loop stopped with, see fail
+	err error
+	Hooks
 }
 
-func (scorer *Scorer) CalculateScore() error {
-	defer scorer.ResetSteps()
-	if scorer.movesSinceLastInc < 1 {
-		return errors.New("cannot calculate score when no steps were made")
-	}
-	center := math.Abs(float64(scorer.OldHeadPos.X-scorer.OldFoodPos.X)) + math.Abs(float64(scorer.OldHeadPos.Y-scorer.OldFoodPos.Y))
-	left := float64(scorer.OldHeadPos.X+scorer.gridWidth-scorer.OldFoodPos.X) + math.Abs(float64(scorer.OldHeadPos.Y-scorer.OldFoodPos.Y))
-	right := float64(scorer.gridWidth-scorer.OldHeadPos.X+scorer.OldFoodPos.X) + math.Abs(float64(scorer.OldHeadPos.Y-scorer.OldFoodPos.Y))
-	top := math.Abs(float64(scorer.OldHeadPos.X-scorer.OldFoodPos.X)) + float64(scorer.OldHeadPos.Y+scorer.gridHeight-scorer.OldFoodPos.Y)
-	bottom := math.Abs(float64(scorer.OldHeadPos.X-scorer.OldFoodPos.X)) + float64(scorer.gridHeight-scorer.OldHeadPos.Y+scorer.OldFoodPos.Y)
-	distance := math.Min(center, left)
-	distance = math.Min(distance, right)
-	distance = math.Min(distance, top)
-	distance = math.Min(distance, bottom)
-	// this should actually never happen
-	if scorer.movesSinceLastInc < int(distance) {
-		scorer.Score += scorer.maxPoints
-		return nil
+// InitScreen sets up the screen of the terminal.
+func InitScreen() (tcell.Screen, error) {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return nil, err
 	}
-	// too many steps -> 1 point
-	if scorer.movesSinceLastInc-int(distance) >= (scorer.gridHeight*scorer.gridWidth)/2 {
-		scorer.Score += 1
-		return nil
+	return screen, SetupScreen(screen)
+}
+
+// SetupScreen initializes screen and sets the default style of the game.
+func SetupScreen(screen tcell.Screen) error {
+	if err := screen.Init(); err != nil {
+		return err
 	}
-	x := (float64(scorer.movesSinceLastInc) - distance) / float64((scorer.gridHeight*scorer.gridWidth)/2)
-	pointsFrac := (1 - math.Pow(2*x-1, 3)) / 2
-	scorer.Score += int(math.Max(math.Round(pointsFrac*float64(scorer.maxPoints)), 1))
+	defStyle := tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
+	screen.SetStyle(defStyle)
 	return nil
 }
 
-func InitScorer(width, height int) Scorer {
-	return Scorer{
-		Score:             0,
-		movesSinceLastInc: 0,
-		weight:            50,
-		gridWidth:         width,
-		gridHeight:        height,
-		maxPoints:         10,
+// hardcoreStep is the delay taken off per eaten food in hardcore mode.
+const hardcoreStep = 5 * time.Millisecond
+
+// AdjustDelay speeds the game up as the snail covers more of the board, down
+// to a delay of 100ms. In hardcore mode, if MinDelay is set, every eaten food
+// takes off another hardcoreStep down to MinDelay.
+func (game *Game) AdjustDelay() {
+	size := game.XDim * game.YDim
+	share := (100 / float32(size)) * float32(game.Length())
+	newDelay := game.GameDelayMilliSeconds.Milliseconds()
+	if share > 90 && newDelay > 100 ||
+		(share > 80 && newDelay > 110) ||
+		(share > 70 && newDelay > 120) ||
+		(share > 60 && newDelay > 130) ||
+		(share > 50 && newDelay > 140) ||
+		(share > 40 && newDelay > 150) ||
+		(share > 30 && newDelay > 160) ||
+		(share > 20 && newDelay > 170) ||
+		(share > 10 && newDelay > 180) {
+		newDelay -= 10
+	}
+	floor := int64(100)
+	if game.MinDelay > 0 {
+		floor = game.MinDelay.Milliseconds()
+		if target := (game.startDelay - time.Duration(game.FoodsEaten())*hardcoreStep).Milliseconds(); newDelay > target {
+			newDelay = target
+		}
+	}
+	if newDelay < floor {
+		newDelay = floor
 	}
+	if newDelay != game.GameDelayMilliSeconds.Milliseconds() {
+		game.Debug("delay adjusted", "delay_ms", newDelay, "share", share)
+	}
+	game.GameDelayMilliSeconds = time.Duration(newDelay) * time.Millisecond
 }
 
-type Snail struct {
-	// NOTE: the head is at the end of the slice!
-	Body      []Pos
-	Direction Velocity
-	OldTail   Pos
-}
+// slowFactor is the factor the delay is stretched by while a PickupSlow is
+// active, in percent.
+const slowFactor = 150
+
+// surgeFactor is the factor the delay is shortened to during an
+// engine.EventSurge, in percent.
+const surgeFactor = 60
 
-func (snail *Snail) NextPos(oldPos Pos, XDim int, YDim int) Pos {
-	newHead := Pos{X: 0, Y: 0}
-	newHead.X = (oldPos.X + snail.Direction.X) % XDim
-	newHead.Y = (oldPos.Y + snail.Direction.Y) % YDim
-	if newHead.X < 0 {
-		newHead.X = XDim - 1
+// TickDelay returns the delay before the next tick: GameDelayMilliSeconds,
+// stretched while a PickupSlow is active, shortened during a surge and
+// changed by a random amount of up to Jitter percent in either direction.
+func (game *Game) TickDelay() time.Duration {
+	delay := game.GameDelayMilliSeconds
+	if game.Active(engine.PickupSlow) {
+		delay = delay * slowFactor / 100
 	}
-	if newHead.Y < 0 {
-		newHead.Y = YDim - 1
+	if game.EventActive(engine.EventSurge) {
+		delay = delay * surgeFactor / 100
 	}
-	return newHead
+	if game.Jitter < 1 {
+		return delay
+	}
+	percent := game.jitterRand.Intn(2*game.Jitter+1) - game.Jitter
+	return delay + delay*time.Duration(percent)/100
+}
+
+func (renderer *CanvasRenderer) DrawBoard(game *Game) {
+	renderer.Canvas.Clear()
+	renderer.DrawBorder(game)
+	renderer.DrawGrid(game)
+	renderer.DrawGhost(game)
+	renderer.DrawObstacles(game)
+	renderer.DrawWrapAnimation(game)
+	renderer.DrawUpcomingFood(game)
+	renderer.DrawPowerUp(game)
+	renderer.DrawPickups(game)
+	renderer.DrawFood(game)
+	renderer.DrawRivals(game)
+	renderer.DrawSnail(game, game.Snail().Body, snailBodySytle)
+	renderer.DrawScore(game)
+	renderer.DrawObjective(game)
+	renderer.DrawMinimap(game)
 }
 
-func (snail *Snail) MoveForward(ate bool, XDim int, YDim int) {
-	oldHead := snail.Body[len(snail.Body)-1]
-	newHead := snail.NextPos(oldHead, XDim, YDim)
-	snail.Body = append(snail.Body, newHead)
-	if !ate {
-		snail.OldTail = snail.Body[0]
-		snail.Body = snail.Body[1:]
+// DrawFood draws the food if it is on the layer of the head.
+func (renderer *CanvasRenderer) DrawFood(game *Game) {
+	if food := game.Food(); game.OnLayer(food) {
+		renderer.Canvas.DrawCell(food.X, food.Y, CellFood, foodStyle)
 	}
 }
 
-func (snail *Snail) GetHead() Pos {
-	return snail.Body[len(snail.Body)-1]
+// DrawGrid draws a faint dot on every cell when Gridlines is set. Everything
+// else on the board is drawn over it.
+func (renderer *CanvasRenderer) DrawGrid(game *Game) {
+	if !game.Gridlines {
+		return
+	}
+	for y := 0; y < game.YDim; y++ {
+		for x := 0; x < game.XDim; x++ {
+			renderer.Canvas.DrawCell(x, y, CellGrid, gridStyle)
+		}
+	}
 }
 
-func InitSnail(width int, height int) Snail {
-	startPos := []Pos{{
-		X: int(width / 2),
-		Y: int(height / 2),
-	},
-		{
-			X: int(width/2) + 1,
-			Y: int(height / 2),
-		},
-		{
-			X: int(width/2) + 2,
-			Y: int(height / 2),
-		},
+func (renderer *CanvasRenderer) DrawObstacles(game *Game) {
+	for _, pos := range game.Obstacles() {
+		if game.OnLayer(pos) {
+			renderer.Canvas.DrawCell(pos.X, pos.Y, CellObstacle, wallStyle)
+		}
 	}
-	oldTail := Pos{
-		X: -1,
-		Y: -1,
+	for _, pos := range game.Portals() {
+		switch {
+		case !game.OnLayer(pos):
+		case game.IsLadder(pos):
+			renderer.Canvas.DrawCell(pos.X, pos.Y, CellLadder, ladderStyle)
+		default:
+			renderer.Canvas.DrawCell(pos.X, pos.Y, CellPortal, portalStyle)
+		}
 	}
-	snail := Snail{
-		Body:      startPos,
-		Direction: EastDir,
-		OldTail:   oldTail,
+}
+
+// DrawUpcomingFood draws faint markers on the next Trainer positions of the
+// food script.
+func (renderer *CanvasRenderer) DrawUpcomingFood(game *Game) {
+	for _, pos := range game.UpcomingFood(game.Trainer) {
+		if game.OnLayer(pos) {
+			renderer.Canvas.DrawCell(pos.X, pos.Y, CellFoodHint, foodHintStyle)
+		}
 	}
-	return snail
 }
 
-type Game struct {
-	Food                  Pos
-	Snail                 Snail
-	Scorer                Scorer
-	NextDirection         chan Velocity
-	PauseChan             chan struct{}
-	Paused                bool
-	Screen                tcell.Screen
-	GameDelayMilliSeconds time.Duration
-	XDim                  int
-	YDim                  int
-	GameOver              bool
+// pickupCells are the cell kinds and styles the pickups are drawn with.
+var pickupCells = map[engine.PickupKind]struct {
+	kind  CellKind
+	style tcell.Style
+}{
+	engine.PickupPoison: {CellPoison, poisonStyle},
+	engine.PickupGolden: {CellGolden, goldenStyle},
+	engine.PickupGhost:  {CellGhost, effectStyle},
+	engine.PickupSlow:   {CellSlow, effectStyle},
+	engine.PickupShrink: {CellShrink, effectStyle},
+	engine.PickupDouble: {CellDouble, effectStyle},
 }
 
-func InitScreen() tcell.Screen {
-	screen, err := tcell.NewScreen()
-	if err != nil {
-		log.Fatalf("%+v", err)
-	}
-	if err := screen.Init(); err != nil {
-		log.Fatalf("%+v", err)
-	}
-	defStyle := tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite)
-	screen.SetStyle(defStyle)
-	return screen
+// effectNames are the names the active effects are shown with next to the
+// score.
+var effectNames = map[engine.PickupKind]string{
+	engine.PickupGhost:  "Ghost",
+	engine.PickupSlow:   "Slow",
+	engine.PickupDouble: "2x",
 }
 
-func (game *Game) CreateFood() error {
-	potentialFree := game.XDim*game.YDim - len(game.Snail.Body)
-	if potentialFree < 1 {
-		return errors.New("no free cell for food left")
-	}
-	next := rand.Intn(potentialFree)
-	cur := 0
-	for x := 0; x < game.XDim; x++ {
-		for y := 0; y < game.YDim; y++ {
-			toCheck := Pos{X: x, Y: y}
-			if game.CheckCollisions(toCheck, game.Snail.Body) {
-				continue
-			}
-			cur += 1
-			if cur >= next {
-				game.Food = toCheck
-				return nil
-			}
+// DrawPickups draws the pickups on the board.
+func (renderer *CanvasRenderer) DrawPickups(game *Game) {
+	for _, pickup := range game.Pickups() {
+		if !game.OnLayer(pickup.Pos) {
+			continue
 		}
+		cell := pickupCells[pickup.Kind]
+		renderer.Canvas.DrawCell(pickup.Pos.X, pickup.Pos.Y, cell.kind, cell.style)
 	}
-	return errors.New("no free cell for food left, unreachable")
 }
 
-func (game *Game) CheckCollisions(posToCheck Pos, potentialCollision []Pos) bool {
-	for _, pos := range potentialCollision {
-		if pos.X == posToCheck.X && pos.Y == posToCheck.Y {
-			return true
+// DrawRivals draws the living rivals of the snail.
+func (renderer *CanvasRenderer) DrawRivals(game *Game) {
+	for _, rival := range game.Rivals() {
+		if !rival.Dead {
+			renderer.DrawSnail(game, rival.Snail.Body, rivalStyle)
 		}
 	}
-	return false
 }
 
-func (game *Game) WonGame() bool {
-	return game.XDim*game.YDim <= len(game.Snail.Body)
+// DrawPowerUp draws the power-up if there is one on the board.
+func (renderer *CanvasRenderer) DrawPowerUp(game *Game) {
+	if pos, ok := game.PowerUp(); ok && game.OnLayer(pos) {
+		renderer.Canvas.DrawCell(pos.X, pos.Y, CellPowerUp, powerUpStyle)
+	}
 }
 
-func (game *Game) AdjustDelay() {
-	size := game.XDim * game.YDim
-	share := (100 / float32(size)) * float32(len(game.Snail.Body))
-	newDelay := game.GameDelayMilliSeconds.Milliseconds()
-	if share > 90 && newDelay > 100 ||
-		(share > 80 && newDelay > 110) ||
-		(share > 70 && newDelay > 120) ||
-		(share > 60 && newDelay > 130) ||
-		(share > 50 && newDelay > 140) ||
-		(share > 40 && newDelay > 150) ||
-		(share > 30 && newDelay > 160) ||
-		(share > 20 && newDelay > 170) ||
-		(share > 10 && newDelay > 180) {
-		newDelay -= 10
+func (renderer *CanvasRenderer) DrawBorder(game *Game) {
+	width, height := game.ViewSize()
+	renderer.Canvas.DrawBorder(width, height, wallStyle)
+}
+
+// DrawSnail draws body with bodyStyle, its last segment is drawn as the head.
+func (renderer *CanvasRenderer) DrawSnail(game *Game, body []engine.Pos, bodyStyle tcell.Style) {
+	if game.SmoothSnake {
+		renderer.DrawSmoothSnail(game, body, bodyStyle)
+		return
 	}
-	if newDelay < 100 {
-		newDelay = 100
+	for index, pos := range body {
+		if !game.OnLayer(pos) {
+			continue
+		}
+		if index == len(body)-1 {
+			renderer.Canvas.DrawCell(pos.X, pos.Y, CellSnailHead, game.headStyle())
+		} else {
+			renderer.Canvas.DrawCell(pos.X, pos.Y, CellSnailBody, bodyStyle)
+		}
 	}
-	game.GameDelayMilliSeconds = time.Duration(newDelay) * time.Millisecond
 }
 
-func (game *Game) DrawBoard() {
-	for c := 0; c < game.XDim+2; c++ {
-		var ru = tcell.RuneHLine
-		var rl = tcell.RuneHLine
-		var double = true
-		if c == 0 {
-			double = false
-			ru = tcell.RuneULCorner
-			rl = tcell.RuneLLCorner
-		} else if c == game.XDim+1 {
-			double = false
-			ru = tcell.RuneURCorner
-			rl = tcell.RuneLRCorner
+func (game *Game) headStyle() tcell.Style {
+	if game.GraceLeft() > 0 && game.Tick()%2 == 1 {
+		// the head flashes while collisions are ignored
+		return graceHeadStyle
+	}
+	return snailHeadSytle
+}
+
+// rivalNames are the names the scores of the rivals that do not score for the
+// player are shown with.
+var rivalNames = map[engine.RivalControl]string{
+	engine.RivalAutopilot: "AI",
+	engine.RivalPlayer:    "P2",
+}
+
+func (renderer *CanvasRenderer) DrawScore(game *Game) {
+	score := fmt.Sprintf("Score: %d", game.Score())
+	if game.PendingGrowth() > 0 {
+		score += fmt.Sprintf(" Growth: +%d", game.PendingGrowth())
+	}
+	if game.Combo() > 1 {
+		score += fmt.Sprintf(" Combo: x%d", game.Combo())
+	}
+	if game.Rewinds() > 0 {
+		score += fmt.Sprintf(" Rewind: %d", game.Rewinds())
+	}
+	if game.Lives > 0 {
+		score += fmt.Sprintf(" Lives: %d", game.LivesLeft())
+	}
+	for _, rival := range game.Rivals() {
+		if name, ok := rivalNames[rival.Control]; ok {
+			score += fmt.Sprintf(" %s: %d", name, rival.Scorer.Score)
 		}
-		game.Screen.SetContent(c*2, 0, ru, nil, wallStyle)
-		game.Screen.SetContent(c*2, game.YDim+1, rl, nil, wallStyle)
-		if double {
-			game.Screen.SetContent(c*2+1, 0, ru, nil, wallStyle)
-			game.Screen.SetContent(c*2+1, game.YDim+1, rl, nil, wallStyle)
+	}
+	effects := game.Effects()
+	for _, kind := range engine.PowerUps {
+		if ticks, ok := effects[kind]; ok {
+			score += fmt.Sprintf(" %s: %d", effectNames[kind], ticks)
 		}
 	}
-	game.Screen.SetContent(1, game.YDim+1, tcell.RuneHLine, nil, wallStyle)
+	if golden, ok := game.Golden(); ok {
+		score += fmt.Sprintf(" Gold: %d", golden.TicksLeft)
+	}
+	if event, ok := game.Event(); ok {
+		score += fmt.Sprintf(" %s! %d", event.Kind, event.TicksLeft)
+	}
+	if game.MinDelay > 0 {
+		score += fmt.Sprintf(" Speed: %.1f/s", float64(time.Second)/float64(game.GameDelayMilliSeconds))
+	}
+	if game.countdown != nil {
+		score += " Time: " + formatClock(game.countdown.Left())
+	}
+	if game.perfectLeft > 0 {
+		score += " PERFECT!"
+	}
+	renderer.Canvas.DrawText(1, 0, score, blackWhiteStyle)
+}
 
-	for r := 1; r < game.YDim+1; r++ {
-		game.Screen.SetContent(0, r, tcell.RuneVLine, nil, wallStyle)
-		game.Screen.SetContent(game.XDim*2+2, r, tcell.RuneVLine, nil, wallStyle)
+// DrawObjective shows the level of a campaign, the eaten and required foods,
+// the moves left, the mirror mode and the daily challenge on the bottom
+// border, as far as they are set.
+func (renderer *CanvasRenderer) DrawObjective(game *Game) {
+	status := ""
+	if game.Campaign != nil {
+		status += fmt.Sprintf(" Level: %d/%d", game.Campaign.Level+1, len(CampaignLevels))
+	}
+	if game.ObjectiveFoods > 0 {
+		status += fmt.Sprintf(" Food: %d/%d", game.FoodsEaten(), game.ObjectiveFoods)
+	}
+	if game.MoveBudget > 0 {
+		status += fmt.Sprintf(" Moves: %d", game.MovesLeft())
 	}
+	if game.Mirror != MirrorOff {
+		status += " Mirror: " + game.Mirror.String()
+	}
+	if game.Daily {
+		status += " Daily"
+	}
+	if game.Layers > 1 {
+		status += fmt.Sprintf(" Layer: %d/%d", game.Head().Layer+1, game.Layers)
+	}
+	if game.toastLeft > 0 && len(game.earned) > 0 {
+		status += " Unlocked: " + game.earned[len(game.earned)-1].Name + "!"
+	}
+	_, height := game.ViewSize()
+	renderer.Canvas.DrawText(1, height+1, status, blackWhiteStyle)
+}
 
-	game.Screen.SetContent(game.Food.X*2+1, game.Food.Y+1, tcell.RuneBlock, nil, foodStyle)
-	game.Screen.SetContent(game.Food.X*2+2, game.Food.Y+1, tcell.RuneBlock, nil, foodStyle)
-	for index, pos := range game.Snail.Body {
-		var style = snailBodySytle
-		if index == len(game.Snail.Body)-1 {
-			style = snailHeadSytle
-		}
-		game.Screen.SetContent(pos.X*2+1, pos.Y+1, tcell.RuneBlock, nil, style)
-		game.Screen.SetContent(pos.X*2+2, pos.Y+1, tcell.RuneBlock, nil, style)
+// StartWrapAnimation arms the edge animation after the head moved from exit
+// to entry by wrapping around the board.
+func (game *Game) StartWrapAnimation(exit, entry engine.Pos) {
+	if game.WrapAnimFrames < 1 {
+		return
 	}
+	game.wrapAnim = WrapAnimation{
+		Exit:       exit,
+		Entry:      entry,
+		Direction:  game.Snail().Direction,
+		FramesLeft: game.WrapAnimFrames,
+	}
+}
+
+// DrawWrapAnimation draws the exiting and entering markers on the border. The
+// first frame is drawn solid, the following one faded. The animation advances
+// by one frame per tick in Step, not per draw.
+func (renderer *CanvasRenderer) DrawWrapAnimation(game *Game) {
+	anim := game.wrapAnim
+	if anim.FramesLeft < 1 {
+		return
+	}
+	kind := CellWrapFaded
+	if anim.FramesLeft == game.WrapAnimFrames {
+		kind = CellWrap
+	}
+	exit := engine.Pos{X: anim.Exit.X + anim.Direction.X, Y: anim.Exit.Y + anim.Direction.Y}
+	entry := engine.Pos{X: anim.Entry.X - anim.Direction.X, Y: anim.Entry.Y - anim.Direction.Y}
+	renderer.Canvas.DrawCell(exit.X, exit.Y, kind, wrapAnimStyle)
+	renderer.Canvas.DrawCell(entry.X, entry.Y, kind, wrapAnimStyle)
+}
 
-	score := fmt.Sprintf("Score: %d", game.Scorer.Score)
-	for index, l := range score {
-		game.Screen.SetContent(1+index, 0, l, nil, blackWhiteStyle)
+// PlayDeathAnimation dissolves the snail segment by segment, starting at the
+// tail, over DeathAnimFrames frames while the remaining body flashes. It
+// returns false if ctx is done before the animation finished.
+func (renderer *CanvasRenderer) PlayDeathAnimation(ctx context.Context, game *Game) bool {
+	body := game.Snail().Body
+	for frame := 1; frame <= game.DeathAnimFrames; frame++ {
+		style := snailBodySytle
+		if frame%2 == 1 {
+			style = deathStyle
+		}
+		renderer.Canvas.Clear()
+		renderer.DrawBorder(game)
+		renderer.DrawObstacles(game)
+		renderer.DrawFood(game)
+		renderer.DrawSnail(game, body[len(body)*frame/game.DeathAnimFrames:], style)
+		renderer.DrawScore(game)
+		renderer.Canvas.Present()
+		if !game.Wait(ctx, game.GameDelayMilliSeconds) {
+			return false
+		}
 	}
+	return true
 }
 
-func (game *Game) DrawPause() {
+// DrawPause redraws the board in dimmed styles with the pause text on top.
+func (renderer *CanvasRenderer) DrawPause(game *Game) {
+	dimmed := CanvasRenderer{Canvas: DimCanvas{renderer.Canvas}}
+	dimmed.DrawBoard(game)
 	text := "Paused, wanna resume? p"
-	row := game.YDim/2 + 1
-	col := game.XDim/2 - len(text)/2 + 1
-	for _, r := range text {
-		game.Screen.SetContent(col, row, r, nil, blackWhiteStyle)
-		col++
+	width, height := game.ViewSize()
+	row := height/2 + 1
+	col := width - len(text)/2 + 1
+	renderer.Canvas.DrawText(col, row, text, blackWhiteStyle)
+}
+
+// DrawArcade draws the best scores of the Arcade from row on.
+func (renderer *CanvasRenderer) DrawArcade(game *Game, row int) {
+	width, _ := game.ViewSize()
+	texts := []string{"Arcade best:"}
+	for index, score := range game.Arcade.Best(game.HighScoreKey()) {
+		texts = append(texts, fmt.Sprintf("%d. %s %d", index+1, score.Player, score.Score))
 	}
+	for index, text := range texts {
+		renderer.Canvas.DrawText(width-len(text)/2+1, row+index, text, blackWhiteStyle)
+	}
+}
+
+// versusOutcomeTexts describe the outcome of a game against the computer
+// snail, see engine.Config.Versus.
+var versusOutcomeTexts = map[engine.SnailOutcome]string{
+	engine.OutcomeFirstDies:  "the computer snail wins!",
+	engine.OutcomeSecondDies: "you beat the computer snail!",
+	engine.OutcomeBothDie:    "both snails died!",
 }
 
-func (game *Game) DrawGameOver(won bool) {
+// DrawGameOver draws the game over texts, the screen between two levels of a
+// campaign or, if it was toggled, the score breakdown.
+func (renderer *CanvasRenderer) DrawGameOver(game *Game, won bool) {
+	if game.breakdownShown {
+		renderer.Canvas.Clear()
+		renderer.DrawBreakdown(game)
+		return
+	}
+	if game.achievementsShown {
+		renderer.Canvas.Clear()
+		renderer.DrawAchievements(game)
+		return
+	}
+	if game.heatmapShown {
+		renderer.Canvas.Clear()
+		renderer.DrawHeatmap(game)
+		return
+	}
+	if game.Campaign != nil {
+		renderer.DrawCampaign(game, won)
+		return
+	}
+	if game.TimeAttack {
+		renderer.DrawTimeAttack(game)
+		return
+	}
 	first := "Game Over, you suck!"
 	if won {
 		first = "Game Over, you have WON!"
+	} else if game.OutOfMoves() {
+		first = "Game Over, out of moves!"
+	} else if game.TimeUp() {
+		first = "Game Over, time limit reached!"
+	} else if game.TwoPlayers && game.RivalOutcome() != engine.OutcomeNone {
+		first = "Game Over, " + game.RivalOutcome().String()
+	} else if game.Versus && game.RivalOutcome() != engine.OutcomeNone {
+		first = "Game Over, " + versusOutcomeTexts[game.RivalOutcome()]
 	}
-	texts := [3]string{
+	texts := [5]string{
 		first,
-		fmt.Sprintf("You reached a score of %d points.", game.Scorer.Score),
+		fmt.Sprintf("You reached a score of %d points.", game.Score()),
+		fmt.Sprintf("You ate %d food perfectly.", game.Perfects()),
+		"Score breakdown? b Heatmap? h",
 		"Play Again? y/n",
 	}
+	width, height := game.ViewSize()
 	for index, text := range texts {
-		row := game.YDim/2 + 1 + index
-		col := game.XDim - len(text)/2 + 1
-		for _, r := range text {
-			game.Screen.SetContent(col, row, r, nil, blackWhiteStyle)
-			col++
+		row := height/2 + 1 + index
+		col := width - len(text)/2 + 1
+		style := blackWhiteStyle
+		if index == 1 {
+			style = game.ScoreTiers.Style(game.Score(), game.TierStyles)
+		}
+		renderer.Canvas.DrawText(col, row, text, style)
+	}
+	row := height/2 + 1 + len(texts)
+	if game.snapshotErr != nil {
+		text := "Snapshot not saved!"
+		renderer.Canvas.DrawText(width-len(text)/2+1, row, text, blackWhiteStyle)
+		row++
+	}
+	if game.ledgerErr != nil {
+		text := "Ledger not updated!"
+		renderer.Canvas.DrawText(width-len(text)/2+1, row, text, blackWhiteStyle)
+		row++
+	}
+	if game.highScoreErr != nil {
+		text := "High scores not updated!"
+		renderer.Canvas.DrawText(width-len(text)/2+1, row, text, blackWhiteStyle)
+		row++
+	}
+	if game.historyErr != nil {
+		text := "History not updated!"
+		renderer.Canvas.DrawText(width-len(text)/2+1, row, text, blackWhiteStyle)
+		row++
+	}
+	if game.ghostErr != nil {
+		text := "Ghost not saved!"
+		renderer.Canvas.DrawText(width-len(text)/2+1, row, text, blackWhiteStyle)
+		row++
+	}
+	if game.Achievements != nil {
+		texts := []string{"Achievements? t"}
+		for _, achievement := range game.earned {
+			texts = append(texts, "Achievement unlocked: "+achievement.Name)
+		}
+		if game.achievementErr != nil {
+			texts = append(texts, "Achievements not saved!")
+		}
+		for _, text := range texts {
+			renderer.Canvas.DrawText(width-len(text)/2+1, row, text, blackWhiteStyle)
+			row++
+		}
+	}
+	if len(game.highScores) > 0 {
+		renderer.DrawHighScores(game, row+1)
+		row += min(len(game.highScores), highScoreRows) + 2
+		if game.highScoreRank >= highScoreRows {
+			row++
 		}
 	}
+	if game.Arcade != nil {
+		renderer.DrawArcade(game, row+1)
+	}
 }
 
-func (game *Game) IsValidNewDir(newDir Velocity) bool {
-	if NorthDir.Equals(game.Snail.Direction) {
-		return !SouthDir.Equals(newDir)
-	} else if SouthDir.Equals(game.Snail.Direction) {
-		return !NorthDir.Equals(newDir)
-	} else if EastDir.Equals(game.Snail.Direction) {
-		return !WestDir.Equals(newDir)
-	} else if WestDir.Equals(game.Snail.Direction) {
-		return !EastDir.Equals(newDir)
+// Loop plays a game until it is over or ctx is done. If it fails, the error
+// is kept for the one who started the loop, see fail.
+func (game *Game) Loop(ctx context.Context) {
+	game.err = nil
+	if err := game.ResetState(); err != nil {
+		game.fail(err)
+		return
+	}
+	if game.InputLogPath != "" {
+		header, err := game.InputLogHeader()
+		if err != nil {
+			game.fail(err)
+			return
+		}
+		logger, err := engine.CreateInputLogger(game.InputLogPath, header)
+		if err != nil {
+			game.fail(err)
+			return
+		}
+		defer logger.Close()
+		game.SetInputLogger(logger)
+	}
+	game.countdown = nil
+	if game.MaxDuration > 0 {
+		game.countdown = StartCountdown(game.Clock, game.MaxDuration)
+	}
+	// ticks are started at a fixed rate, so the time spent on a tick does not
+	// add to the delay and inputs are taken while waiting for the next one
+	interval := game.TickDelay()
+	ticker := game.Clock.NewTicker(interval)
+	defer ticker.Stop()
+	for game.tracedStep(ctx) {
+		trace.WithRegion(ctx, "draw", func() {
+			game.UpdateCamera()
+			game.Renderer.DrawBoard(game)
+			game.Renderer.Show()
+		})
+		if delay := game.TickDelay(); delay != interval {
+			interval = delay
+			ticker.Reset(interval)
+		}
+		if !game.awaitTick(ctx, ticker, interval) {
+			return
+		}
+		game.survived += interval
 	}
-	return NorthDir.Equals(newDir) || SouthDir.Equals(newDir) || WestDir.Equals(newDir) || EastDir.Equals(newDir)
+	if game.err != nil {
+		return
+	}
+	game.countdown.Stop()
+	won := game.EndGame()
+	game.CheckAchievements()
+	if err := game.enter(StateGameOver); err != nil {
+		game.fail(err)
+		return
+	}
+	if game.AutosaveDir != "" {
+		// a failed snapshot is shown on the game over screen instead of
+		// ending the program
+		_, game.snapshotErr = game.Snapshot()
+	}
+	if game.LedgerPath != "" {
+		game.ledgerErr = game.AppendLedger()
+	}
+	// only games played by the player make it into the high scores
+	if game.HighScorePath != "" && game.Autopilot == nil && game.Replay == nil {
+		game.highScoreErr = game.RecordHighScore()
+	}
+	if game.HistoryPath != "" {
+		game.historyErr = game.AppendHistory()
+	}
+	if game.GhostDir != "" && game.ghostErr == nil {
+		game.ghostErr = game.SaveGhost()
+	}
+	if game.Campaign != nil {
+		game.campaignErr = game.Campaign.Finish(game, won)
+	}
+	if animator, ok := game.Renderer.(DeathAnimator); ok && game.Died() && !animator.PlayDeathAnimation(ctx, game) {
+		return
+	}
+	game.Renderer.DrawGameOver(game, won)
+	game.Renderer.Show()
 }
 
-func (game *Game) Loop(ctx context.Context) {
-	game.ResetState()
-	game.Scorer.OldHeadPos = game.Snail.GetHead()
-	game.Scorer.OldFoodPos = game.Food
+// InputLogHeader returns the seed and the config of the current game that are
+// written to the input log, so replaying it plays the same game. Games with a
+// script or rules that are not registered cannot be replayed and are not
+// logged.
+func (game *Game) InputLogHeader() (engine.InputLog, error) {
+	config := game.Config
+	header := engine.InputLog{
+		Seed:   game.CurrentSeed(),
+		Config: &config,
+		Delay:  game.startDelay,
+	}
+	if game.Script != nil {
+		return header, fmt.Errorf("games with a script cannot be logged")
+	}
+	if game.Rules != nil {
+		name, ok := engine.RulesName(game.Rules)
+		if !ok {
+			return header, fmt.Errorf("games with the rules %T cannot be logged, they are not registered", game.Rules)
+		}
+		header.Rules = name
+	}
+	return header, nil
+}
+
+// fail keeps err as the error the game loop stopped with, unless it failed
+// before. The loop stops after the tick or action that failed.
+func (game *Game) fail(err error) {
+	if game.err == nil {
+		game.err = err
+	}
+}
+
+// tracedStep runs Step as a region of the execution trace.
+func (game *Game) tracedStep(ctx context.Context) bool {
+	running := false
+	trace.WithRegion(ctx, "step", func() { running = game.Step() })
+	return running
+}
+
+// awaitTick handles the inputs, actions and pauses until the next tick of a
+// playing game. No more directions are taken while the queue of turns is
+// full. It returns false if ctx is done or the game failed first.
+func (game *Game) awaitTick(ctx context.Context, ticker Ticker, interval time.Duration) bool {
 	for {
+		var directions <-chan engine.Velocity
+		var tick <-chan time.Time
+		if game.state == StatePlaying {
+			tick = ticker.C()
+			if len(game.turns) < maxQueuedTurns {
+				directions = game.NextDirection
+			}
+		}
+		paused := game.state == StatePaused
 		select {
 		case <-ctx.Done():
 			// The context is over, stop processing results
-			return
-		case newDir := <-game.NextDirection:
-			if game.IsValidNewDir(newDir) {
-				game.Snail.Direction = newDir
+			return false
+		case <-game.countdown.C():
+			game.SetTimeUp()
+		case newDir := <-directions:
+			if game.Replay == nil && game.Autopilot == nil {
+				game.QueueTurn(newDir)
 			}
 		case <-game.PauseChan:
-			game.Paused = !game.Paused
-			if game.Paused {
-				select {
-				case <-game.PauseChan:
-					game.Paused = !game.Paused
-				}
-			}
-		default:
-			// dont block
+			game.TogglePause()
+		case action := <-game.actions:
+			action(game)
+		case <-tick:
+			return true
 		}
-		var ate = false
-		if game.CheckCollisions(game.Food, game.Snail.Body) {
-			ate = true
-			ErrExit(game.Scorer.CalculateScore())
-			ErrExit(game.CreateFood())
-			game.Scorer.OldHeadPos = game.Snail.GetHead()
-			game.Scorer.OldFoodPos = game.Food
+		if game.err != nil {
+			return false
 		}
-		if game.CheckCollisions(game.Snail.Body[len(game.Snail.Body)-1], game.Snail.Body[:len(game.Snail.Body)-1]) {
-			break
+		if paused && game.state == StatePlaying {
+			// a full tick passes before the game continues
+			ticker.Reset(interval)
+			select {
+			case <-ticker.C():
+			default:
+			}
 		}
-		if game.WonGame() {
-			break
+	}
+}
+
+// TogglePause pauses a playing game and resumes a paused one. It must be
+// called by the game loop, e.g. with Do.
+func (game *Game) TogglePause() {
+	switch game.state {
+	case StatePlaying:
+		game.fail(game.enter(StatePaused))
+	case StatePaused:
+		game.fail(game.enter(StatePlaying))
+	}
+}
+
+// maxQueuedTurns is the number of directions queued for the next ticks, so a
+// quick corner like up-then-left within one tick takes both turns.
+const maxQueuedTurns = 2
+
+// QueueTurn queues dir to be taken on one of the next ticks, one turn per
+// tick. Turns that do not change the direction or reverse it compared to the
+// turn before are dropped, as are turns beyond maxQueuedTurns.
+func (game *Game) QueueTurn(dir engine.Velocity) {
+	last := game.Snail().Direction
+	if len(game.turns) > 0 {
+		last = game.turns[len(game.turns)-1]
+	}
+	if len(game.turns) >= maxQueuedTurns || dir.Equals(last) || dir.Equals(engine.Velocity{X: -last.X, Y: -last.Y}) {
+		return
+	}
+	game.turns = append(game.turns, dir)
+}
+
+// QueueRivalTurn queues dir for the snail of the second player like
+// QueueTurn does for the first one.
+func (game *Game) QueueRivalTurn(dir engine.Velocity) {
+	rivals := game.Rivals()
+	if game.state != StatePlaying || len(rivals) == 0 {
+		return
+	}
+	last := rivals[0].Snail.Direction
+	if len(game.rivalTurns) > 0 {
+		last = game.rivalTurns[len(game.rivalTurns)-1]
+	}
+	if len(game.rivalTurns) >= maxQueuedTurns || dir.Equals(last) || dir.Equals(engine.Velocity{X: -last.X, Y: -last.Y}) {
+		return
+	}
+	game.rivalTurns = append(game.rivalTurns, dir)
+}
+
+// takeTurn turns the snails into their first queued direction, if any.
+func (game *Game) takeTurn() {
+	if len(game.rivalTurns) > 0 {
+		game.fail(game.TurnRival(0, game.rivalTurns[0]))
+		game.rivalTurns = game.rivalTurns[1:]
+	}
+	if len(game.turns) == 0 {
+		return
+	}
+	dir := game.turns[0]
+	game.turns = game.turns[1:]
+	if game.EventActive(engine.EventInvert) {
+		dir = MirrorBoth.Apply(dir)
+	}
+	game.fail(game.ChangeDirection(dir))
+}
+
+// Step takes the next queued turn, advances the game by one tick without
+// drawing or waiting and starts the animations caused by it. It returns false
+// once the game is over, see EndGame, or failed, see fail.
+func (game *Game) Step() bool {
+	game.takeTurn()
+	if game.err != nil {
+		return false
+	}
+	perfects, foods, score := game.Perfects(), game.FoodsEaten(), game.Score()
+	running, err := game.Game.Step()
+	if err != nil {
+		game.fail(err)
+		return false
+	}
+	if game.FoodsEaten() > foods {
+		game.runFoodEaten(game)
+	}
+	game.runScoreChange(game, score)
+	if game.wrapAnim.FramesLeft > 0 {
+		game.wrapAnim.FramesLeft -= 1
+	}
+	if exit, entry, ok := game.Wrapped(); ok {
+		game.hasWrapped = true
+		game.StartWrapAnimation(exit, entry)
+	}
+	if game.perfectLeft > 0 {
+		game.perfectLeft -= 1
+	}
+	if game.Perfects() > perfects {
+		// shown on the board of this tick and the PerfectFlash-1 following
+		game.perfectLeft = game.PerfectFlash
+	}
+	game.DiscoverFood()
+	if !running {
+		return false
+	}
+	if game.toastLeft > 0 {
+		game.toastLeft -= 1
+	}
+	game.CheckAchievements()
+	game.AdjustDelay()
+	game.Debug("tick", "head", game.Head(), "length", game.Length(), "score", game.Score())
+	game.runTick(game)
+	return true
+}
+
+// EndGame ends the game like engine.Game.EndGame and runs the hooks for the
+// win bonus, the death of the snail and the end of the game.
+func (game *Game) EndGame() bool {
+	score := game.Score()
+	won := game.Game.EndGame()
+	game.runScoreChange(game, score)
+	if game.Died() {
+		game.runDeath(game)
+	}
+	game.runGameOver(game, won)
+	return won
+}
+
+// SendDirection passes dir on to the game loop without blocking. Inputs are
+// dropped while an earlier one was not consumed yet and repeats of the last
+// sent direction are dropped within the Debounce window, so holding a key
+// does not flood the loop.
+func (game *Game) SendDirection(dir engine.Velocity) {
+	now := game.Clock.Now()
+	if dir.Equals(game.lastInput) && now.Sub(game.lastInputAt) < game.Debounce {
+		return
+	}
+	select {
+	case game.NextDirection <- dir:
+		game.lastInput = dir
+		game.lastInputAt = now
+	default:
+		// the loop has not consumed the previous input yet
+	}
+}
+
+// PauseGame pauses a running game. It does nothing if the game is already
+// paused or over.
+func (game *Game) PauseGame() {
+	if game.LoopDone() {
+		return
+	}
+	game.Do(func(game *Game) {
+		if game.state == StatePlaying {
+			game.TogglePause()
 		}
-		game.Snail.MoveForward(ate, game.XDim, game.YDim)
-		game.Scorer.Step()
-		game.AdjustDelay()
-		game.Screen.Clear()
-		game.DrawBoard()
-		game.Screen.Show()
-		time.Sleep(game.GameDelayMilliSeconds)
+	})
+}
+
+// Do runs action on the goroutine of the game loop, so it can change the
+// state of the game while the loop runs. Once the loop returned, action is
+// run right away. Do must be called from the goroutine that started the loop
+// with StartLoop or one started by it afterwards.
+func (game *Game) Do(action func(game *Game)) {
+	select {
+	case game.actions <- action:
+	case <-game.loopDone:
+		action(game)
+	}
+}
+
+// StartLoop runs Loop on a goroutine of its own.
+func (game *Game) StartLoop(ctx context.Context) {
+	done := make(chan struct{})
+	game.loopDone = done
+	go func() {
+		game.Loop(ctx)
+		close(done)
+	}()
+}
+
+// LoopDone reports whether the loop started with StartLoop returned.
+func (game *Game) LoopDone() bool {
+	select {
+	case <-game.loopDone:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait blocks for d on the game clock. It returns false if ctx is done first.
+func (game *Game) Wait(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-game.Clock.After(d):
+		return true
 	}
-	game.GameOver = true
-	game.DrawGameOver(game.WonGame())
-	game.Screen.Show()
 }
 
 func (game *Game) CreateGameContext(ctx context.Context) (context.Context, context.CancelFunc) {
@@ -417,67 +1084,244 @@ func (game *Game) CreateGameContext(ctx context.Context) (context.Context, conte
 	return toCancel, cancelFunc
 }
 
-func (game *Game) Run(delayMilliseconds, dimensions int) {
-	ctx := context.Background()
-	var toCancel, cancelFunc = game.CreateGameContext(ctx)
+// Run plays games configured by options in the terminal until the player
+// quits or the process receives SIGINT, SIGTERM or SIGHUP.
+func (game *Game) Run(options ...Option) error {
+	if err := game.Start(options...); err != nil {
+		return err
+	}
+	ctx, stop := NotifyShutdown(context.Background())
+	defer stop()
+	defer game.NotifySuspend()()
+	return game.Play(ctx)
+}
 
-	game.InitGame(delayMilliseconds, dimensions)
-	go game.Loop(toCancel)
+// Start applies options and sets up the screen for the games of Play.
+func (game *Game) Start(options ...Option) error {
+	game.Apply(options...)
+	if err := game.InitGame(); err != nil {
+		return err
+	}
+	if game.Input == nil {
+		game.Input = KeyboardInput{Screen: game.Screen, TwoPlayers: game.config.TwoPlayers}
+	}
+	return nil
+}
+
+// Play plays games set up by Start on its screen until the player quits or
+// ctx is done. It returns the error a game failed with or the outputs of the
+// last game could not be written with, see Quit.
+func (game *Game) Play(ctx context.Context) error {
+	var toCancel, cancelFunc = game.CreateGameContext(ctx)
+	done := make(chan struct{})
+	defer close(done)
+	commands := game.readCommands(done)
+	game.StartLoop(toCancel)
 
 	for {
-		switch event := game.Screen.PollEvent().(type) {
-		case *tcell.EventResize:
-			game.Screen.Sync()
-		case *tcell.EventKey:
-			if event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC {
+		running := game.loopDone
+		if game.LoopDone() {
+			if game.err != nil {
 				cancelFunc()
-				game.Screen.Fini()
-				return
-			} else if event.Key() == tcell.KeyUp || event.Rune() == 'w' {
-				game.NextDirection <- NorthDir
-			} else if event.Key() == tcell.KeyDown || event.Rune() == 's' {
-				game.NextDirection <- SouthDir
-			} else if event.Key() == tcell.KeyLeft || event.Rune() == 'a' {
-				game.NextDirection <- WestDir
-			} else if event.Key() == tcell.KeyRight || event.Rune() == 'd' {
-				game.NextDirection <- EastDir
-			} else if event.Rune() == 'p' {
-				dummy := struct{}{}
-				game.PauseChan <- dummy
-			} else if event.Rune() == 'y' && game.GameOver {
+				game.Renderer.Fini()
+				return game.err
+			}
+			// the game is over, only the player is waited for
+			running = nil
+		}
+		var command Command
+		select {
+		case command = <-commands:
+		case <-ctx.Done():
+			command = CommandQuit
+		case <-running:
+			continue
+		}
+		if dir, ok := commandDirections[command]; ok {
+			game.SendDirection(game.Mirror.Apply(dir))
+			continue
+		}
+		if dir, ok := rivalCommandDirections[command]; ok {
+			game.Do(func(game *Game) { game.QueueRivalTurn(dir) })
+			continue
+		}
+		switch command {
+		case CommandQuit:
+			cancelFunc()
+			<-game.loopDone
+			if game.err != nil {
+				game.Renderer.Fini()
+				return game.err
+			}
+			return game.Quit()
+		case CommandSuspend:
+			game.Suspend()
+		case CommandPause:
+			select {
+			case game.PauseChan <- struct{}{}:
+			case <-game.loopDone:
+			}
+		case CommandYes:
+			if game.LoopDone() {
 				cancelFunc()
 				toCancel, cancelFunc = game.CreateGameContext(ctx)
-				go game.Loop(toCancel)
-			} else if event.Rune() == 'n' && game.GameOver {
+				game.StartLoop(toCancel)
+			}
+		case CommandGridlines:
+			game.Do(func(game *Game) { game.Gridlines = !game.Gridlines })
+		case CommandBreakdown:
+			if game.LoopDone() {
+				game.ToggleBreakdown()
+			}
+		case CommandAchievements:
+			if game.LoopDone() {
+				game.ToggleAchievements()
+			}
+		case CommandHeatmap:
+			if game.LoopDone() {
+				game.ToggleHeatmap()
+			}
+		case CommandNo:
+			if game.LoopDone() {
 				cancelFunc()
-				game.Screen.Fini()
-				return
+				return game.Quit()
 			}
 		}
 	}
 }
 
-func (game *Game) UpdateDimesnions(dimension int) {
-	//width, height := game.Screen.Size()
-	game.Screen.SetSize(dimension+2, dimension+2)
-	game.XDim = dimension
-	game.YDim = dimension
+// Quit restores the terminal and writes the outputs of the last game,
+// including its state if it was not over.
+func (game *Game) Quit() error {
+	game.Renderer.Fini()
+	if game.PrintBreakdown {
+		if err := game.WriteBreakdown(os.Stdout); err != nil {
+			return err
+		}
+	}
+	if err := game.SaveRecording(); err != nil {
+		return err
+	}
+	return game.SaveState()
+}
+
+// UpdateDimensions resizes the grid to width x height cells.
+func (game *Game) UpdateDimensions(width, height int) {
+	game.Screen.SetSize(width+2, height+2)
+	game.config.XDim = width
+	game.config.YDim = height
+}
+
+// ResetState starts a new game with the config set by WithConfig and the
+// other options. A game that is not over yet is abandoned.
+func (game *Game) ResetState() error {
+	if game.state == StatePlaying || game.state == StatePaused {
+		if err := game.enter(StateGameOver); err != nil {
+			return err
+		}
+	}
+	if game.resume {
+		// the restored game is continued
+		game.resume = false
+	} else {
+		if game.Campaign != nil {
+			game.Campaign.Setup(game)
+		}
+		engineGame, err := engine.New(game.config)
+		if err != nil {
+			return err
+		}
+		game.Game = engineGame
+	}
+	// the jitter has its own source so it does not change the food placement
+	game.jitterRand = rand.New(rand.NewSource(game.CurrentSeed()))
+	game.breakdownShown = false
+	game.heatmapShown = false
+	game.snapshotErr = nil
+	game.ledgerErr = nil
+	game.highScores = nil
+	game.highScoreRank = -1
+	game.highScoreErr = nil
+	game.personalBest = -1
+	game.survived = 0
+	game.historyErr = nil
+	game.ghostErr = nil
+	if game.GhostDir != "" {
+		game.ghostErr = game.LoadGhost()
+	}
+	game.achievementsShown = false
+	game.earned = nil
+	game.achievementErr = nil
+	game.toastLeft = 0
+	game.hasWrapped = false
+	game.campaignErr = nil
+	game.perfectLeft = 0
+	game.wrapAnim = WrapAnimation{}
+	game.turns = nil
+	game.rivalTurns = nil
+	game.seenFood = engine.Pos{X: -1, Y: -1}
+	game.DiscoverFood()
+	return game.enter(StatePlaying)
+}
+
+// SaveRecording writes the recording of the last game to RecordPath, if set.
+func (game *Game) SaveRecording() error {
+	if game.RecordPath == "" {
+		return nil
+	}
+	rec := game.Recording()
+	return rec.WriteFile(game.RecordPath)
 }
 
-func (game *Game) ResetState() {
-	game.Snail = InitSnail(game.XDim, game.YDim)
-	game.Scorer = InitScorer(game.XDim, game.YDim)
-	ErrExit(game.CreateFood())
-	game.GameOver = false
+// SetDefaults fills in the options that must not be left at zero.
+func (game *Game) SetDefaults() {
+	if game.Clock == nil {
+		game.Clock = RealClock{}
+	}
+	if game.ScoreTiers == (ScoreTiers{}) {
+		game.ScoreTiers = DefaultScoreTiers
+	}
+	if game.TierStyles == (TierStyles{}) {
+		game.TierStyles = DefaultTierStyles
+	}
+	if game.config.XDim == 0 || game.config.YDim == 0 {
+		game.Apply(WithDimensions(DefaultDimensions))
+	}
+	if game.GameDelayMilliSeconds == 0 {
+		game.Apply(WithDelay(DefaultDelay))
+	}
 }
 
-func (game *Game) InitGame(delayMilliseconds, dimensions int) {
-	game.Screen = InitScreen()
-	game.UpdateDimesnions(dimensions)
-	game.ResetState()
-	game.GameDelayMilliSeconds = time.Duration(delayMilliseconds) * time.Millisecond
-	game.NextDirection = make(chan Velocity)
+// InitGame sets up the screen and the renderer, the first game is started by
+// Loop like every other one. A Screen set before, e.g. a
+// tcell.SimulationScreen in tests, is used instead of the terminal.
+func (game *Game) InitGame() error {
+	if game.Screen == nil {
+		screen, err := InitScreen()
+		if err != nil {
+			return err
+		}
+		game.Screen = screen
+	} else if err := SetupScreen(game.Screen); err != nil {
+		return err
+	}
+	if game.Renderer == nil {
+		var canvas Canvas = CameraCanvas{
+			Canvas: NewTcellCanvas(game.Screen, engine.Pos{X: game.Padding, Y: game.Padding}, game.Runes),
+			Camera: &game.Camera,
+		}
+		if game.FogRadius > 0 {
+			canvas = FogCanvas{Canvas: canvas, Game: game}
+		}
+		game.Renderer = &CanvasRenderer{Canvas: canvas, Screen: game.Screen}
+	}
+	game.SetDefaults()
+	game.UpdateDimensions(game.config.XDim, game.config.YDim)
+	// one more input waits while the queue of turns is full, see SendDirection
+	game.NextDirection = make(chan engine.Velocity, 1)
 	game.PauseChan = make(chan struct{})
+	game.actions = make(chan func(game *Game))
+	return nil
 }
 
 var Version = "development"
@@ -487,14 +1331,207 @@ func main() {
 	var gameDelayMilliSeconds = flag.Int("delay", 150,
 		"starting delay in milliseconds of the game (min=100,max=200)")
 	var dimensions = flag.Int("dimensions", 20, "x and y dimension of the game grid (min=10, max=50)")
+	var width = flag.Int("width", 0, "number of columns of the game grid, 0 uses -dimensions (min=10, max=100)")
+	var height = flag.Int("height", 0, "number of rows of the game grid, 0 uses -dimensions (min=10, max=50)")
+	var wrapAnimFrames = flag.Int("wrap-anim", 0, "frames of the animation shown when the snail wraps around an edge (0=off, max=2)")
+	var deathAnimFrames = flag.Int("death-anim", 0, "frames of the animation shown when the snail dies (0=off, max=10)")
+	var boundsName = flag.String("bounds", "wrap", "behavior at the edges of the grid (wrap, bounce, walls)")
+	var walls = flag.Bool("walls", false, "the edges of the grid are walls that end the game, same as -bounds walls")
+	var eatRuleName = flag.String("eat-rule", "exact", "when the snail eats food: exact when the head is on it, touch when the head is next to it")
+	var growthRuleName = flag.String("growth-rule", "eat", "when the snail grows: eat per eaten food, trail on every move so its trail becomes a wall")
+	var scoreRuleName = flag.String("score-rule", "food", "what the snail scores for: food per eaten food, survival per tick survived")
+	var foodWallMargin = flag.Int("food-wall-margin", 0, "minimum distance of food to the edges with lethal walls (min=0, max=5)")
+	var scoring = flag.String("scoring", engine.DefaultScoring, "how food is scored: efficiency for more points the faster it is reached, classic for a point per food, length for a point per segment of the snail")
+	var comboWindow = flag.Int("combo", 0, "multiply the points of food eaten in a row within the given number of moves beyond the shortest path, 0 for no combos (min=0, max=100)")
+	var scoreWeight = flag.Int("score-weight", engine.DefaultScoreWeight, "scales the points awarded per food, 50 awards them unchanged (min=1, max=500)")
+	var foodMinMoves = flag.Int("food-min-moves", 0, "minimum number of moves between the head and new food (0=off, max=10)")
+	var antiStall = flag.Int("anti-stall", 0, "moves beyond the shortest path after which uneaten food is moved (0=off, max=1000)")
+	var runeModeName = flag.String("runes", "auto", "draw cells with characters instead of colors (auto, on, off), auto does on terminals without colors")
+	var growthLag = flag.Int("growth-lag", 0, "extra ticks the tail pauses after eating before it catches up (0=off, max=10)")
+	var autosaveDir = flag.String("autosave-dir", "", "save the final board of every game as text into the given directory")
+	var foodScriptPath = flag.String("food-script", "", "place food on the positions listed in the given file, one \"x y\" per line")
+	var rulesName = flag.String("rules", "classic", "rules to play with, the name of rules compiled in or the path of a Go plugin (.so) exporting Rules")
+	var mirrorName = flag.String("mirror", "off", "swap the direction keys, left and right (lr) or all of them (all), off keeps them")
+	var fogRadius = flag.Int("fog", 0, "only show the board within the given number of moves of the head (0=off, min=2, max=20)")
+	var reverse = flag.Bool("reverse", false, "the snail starts long and shrinks with every food, same as -rules reverse")
+	var dual = flag.Bool("dual", false, "play two snails at once, the second one mirrors the first on the lower half of the board, ignored with -level")
+	var versus = flag.Bool("versus", false, "play against a snail steered by the computer on the lower half of the board, ignored with -level")
+	var twoPlayers = flag.Bool("two-players", false, "play against a second player on the same keyboard, WASD against the arrow keys, ignored with -level")
+	var longerWins = flag.Bool("longer-wins", false, "with -versus or -two-players the longer snail survives running into the body of the shorter one, which dies")
+	var tron = flag.Bool("tron", false, "the snail never shrinks and scores per tick survived, same as -growth-rule trail -score-rule survival")
+	var zen = flag.Bool("zen", false, "the snail never dies, running into itself cuts off the tail, same as -rules zen")
+	var scriptPath = flag.String("script", "", "run the given Lua script to change where food is placed and how it is scored, see LuaScript")
+	var trainer = flag.Int("trainer", 0, "upcoming food positions of the food script that are marked (0=off, max=5)")
+	var perfectFlash = flag.Int("perfect-flash", 5, "ticks PERFECT! is shown after food was reached on a shortest path (0=off, max=20)")
+	var jitter = flag.Int("jitter", 0, "random change of the delay per tick in percent (0=off, max=50)")
+	var gridlines = flag.Bool("gridlines", false, "draw a faint dot on every empty cell, toggled with g")
+	var maxDuration = flag.Duration("max-duration", 0, "end a game after the given time, e.g. 5m (0=off)")
+	var hardcore = flag.Int("hardcore", 0, "speed up with every food down to the given delay in milliseconds (0=off, min=20, max=100)")
+	var timeAttack = flag.Int("time-attack", 0, "score as much as possible in the given number of seconds, replaces -max-duration (0=off, max=600)")
+	var scoreTiers = flag.String("score-tiers", "100,300,600", "minimum scores for a bronze, silver and gold score on the game over screen")
+	var debounce = flag.Duration("debounce", 50*time.Millisecond, "ignore repeats of the same direction key within the given time")
+	var rewindChance = flag.Int("rewind-chance", 0, "chance in percent that eating food spawns a power-up that rewinds the next death (0=off, max=100)")
+	var foodTTL = flag.Int("food-ttl", 0, "ticks after which uneaten food moves to another cell (0=never, max=1000)")
+	var foodTTLPenalty = flag.Int("food-ttl-penalty", 0, "points lost when food moves because of -food-ttl (min=0, max=100)")
+	var simulate = flag.Int("simulate", 0, "play the given number of games with the autopilot without a screen and print the results")
+	var smoothSnake = flag.Bool("smooth-snake", false, "draw the body of the snail as a connected line")
+	var ledgerPath = flag.String("ledger", "", "append the result of every game as a row to the given csv file")
+	var achievementsPath = flag.String("achievements", DataPath("achievements.json"), "keep the unlocked achievements in the given json file, empty to not unlock any")
+	var historyPath = flag.String("history", DataPath("history.jsonl"), "append the result of every game as a json line to the given file, see snail stats export, empty to keep no history")
+	var heatmap = flag.Bool("heatmap", false, "with snail replay, print how often the head visited each cell of the recording")
+	var exportFormat = flag.String("format", "csv", "the format of snail stats export: csv or json")
+	var ghost = flag.Bool("ghost", false, "race against a dimmed ghost of your best game with the same seed and settings, e.g. with -seed or in the daily challenge")
+	var highScorePath = flag.String("highscores", DataPath("highscores.json"), "keep the best scores in the given json file and show them on the game over screen, empty to not keep them")
+	var grace = flag.Int("grace", 0, "ticks at the start of a game in which collisions are ignored (0=off, max=20)")
+	var lives = flag.Int("lives", 0, "times the snail respawns at its start after dying, keeping the score (min=0, max=9)")
+	var commandPath = flag.String("commands", "", "read commands like north or pause line by line from the given file or pipe instead of the keyboard")
+	var printBreakdown = flag.Bool("breakdown", false, "print the points awarded per food of the last game on exit")
+	var seed = flag.Int64("seed", 0, "seed for all random choices of a game, the same seed and inputs play the same game, 0 picks a new seed per game")
+	var inputLogPath = flag.String("input-log", "", "log all inputs of the last game to the given file")
+	var replayInputPath = flag.String("replay-input", "", "replay the inputs of a game logged with -input-log")
+	var padding = flag.Int("padding", 0, "empty rows and columns between the board and the terminal edges (min=0, max=20)")
+	var growth = flag.Int("growth", 1, "segments the snail grows per eaten food (min=0, max=10)")
+	var autopilot = flag.Bool("auto", false, "let the autopilot play while you watch")
+	var autoError = flag.Float64("auto-error", 0, "probability that the autopilot picks a suboptimal move (min=0, max=1)")
+	var objectiveFoods = flag.Int("objective", 0, "foods to eat to win the game (0=off)")
+	var moveBudget = flag.Int("budget", 0, "moves available to reach the objective (0=unlimited)")
+	var obstacleCount = flag.Int("obstacles", 0, "number of obstacles scattered on the board (min=0, max=500)")
+	var obstacleDensity = flag.Int("obstacle-density", 0, "percentage of the cells covered by obstacles, replaces -obstacles (0=off, max=30)")
+	var portalPairs = flag.Int("portals", 0, "number of portal pairs placed on the board, entering one end leaves from the other (min=0, max=5)")
+	var poisonChance = flag.Int("poison", 0, "chance in percent that eating spawns poison, which shrinks the snail and costs points (min=0, max=100)")
+	var powerUpChance = flag.Int("power-ups", 0, "chance in percent that eating spawns a power-up: ghost, slow-down, shrink or double points (min=0, max=100)")
+	var goldenChance = flag.Int("golden", 0, "chance in percent that eating spawns golden food worth a bonus for a few ticks (min=0, max=100)")
+	var foodWander = flag.Int("food-wander", 0, "ticks after which the food takes a step, fleeing from the head when it is close (0=off, min=2, max=50)")
+	var eventInterval = flag.Int("events", 0, "average number of ticks between random events: food reshuffles, speed surges, walls and inverted controls (0=off, max=1000)")
+	var layers = flag.Int("layers", 1, "number of stacked board layers connected by ladders, only the layer of the head is shown (min=1, max=3)")
+	var ladders = flag.Int("ladders", 2, "number of ladders between every two layers (min=1, max=5)")
+	var levelPath = flag.String("level", "", "play on the board of the given ascii map file, see Level")
+	var campaignPath = flag.String("campaign", "", "play the built-in campaign, its progress is kept in the given file")
+	var winBonus = flag.Int("win-bonus", 0, "points for winning a game, the same again at most for winning it quickly (min=0, max=1000)")
+	var headless = flag.Bool("headless", false, "play a single game without a screen, controlled by -auto, -commands or -replay-input, and print its result")
+	var grpcAddr = flag.String("grpc", "", "serve a gRPC api on the given address, e.g. :7130, to play games from other programs instead of the keyboard")
+	var sshAddr = flag.String("ssh", defaultSSHAddr, "address snail serve accepts ssh connections on")
+	var sshHostKey = flag.String("ssh-host-key", "", "private host key of snail serve, a new one is generated on every start if not set")
+	var streamAddr = flag.String("stream", "", "stream the board as json frames over WebSocket on the given address, e.g. :7131")
+	var spectateAddr = flag.String("spectate", "", "let others watch the game with snail watch on the given address, e.g. :7133")
+	var dryRun = flag.Bool("dry-run", false, "print the starting board and a json summary of it and exit")
+	var savePath = flag.String("save-on-exit", "", "write the state of an unfinished game as json to the given file when quitting or on SIGINT, SIGTERM and SIGHUP")
+	var resumePath = flag.String("resume", "", "continue the unfinished game written with -save-on-exit to the given file")
+	var recordPath = flag.String("record", "", "record the last game to the given file")
+	var verifyReplayPath = flag.String("verify-replay", "", "recompute the score of a recorded game and exit")
 	var printVersion = flag.Bool("version", false, "print version information")
-	flag.Parse()
+	var pprofAddr = flag.String("pprof", "", "serve the runtime profiles of net/http/pprof on the given address, e.g. localhost:6060")
+	var tracePath = flag.String("trace", "", "write an execution trace of the game to the given file, see go tool trace")
+	var debug DebugFlag
+	flag.Var(&debug, "debug", "write a structured log of every tick to "+defaultDebugPath+" or, with -debug=file, to the given file")
+	// modes are started as snail daily [flags], snail host [flags] [addr],
+	// snail join [flags] addr, snail watch [flags] addr, snail serve [flags]
+	// snail stats export [flags] and snail replay [flags] file
+	args := os.Args[1:]
+	mode := ""
+	if len(args) > 0 && (args[0] == "daily" || args[0] == "host" || args[0] == "join" || args[0] == "watch" || args[0] == "serve" || args[0] == "stats" || args[0] == "replay") {
+		mode = args[0]
+		args = args[1:]
+	}
+	if mode == "stats" {
+		if len(args) == 0 || args[0] != "export" {
+			ErrExit(fmt.Errorf("snail stats needs a command, e.g. snail stats export --format json"))
+		}
+		args = args[1:]
+	}
+	daily := mode == "daily"
+	ErrExit(flag.CommandLine.Parse(args))
+	addr := defaultHostAddr
+	switch {
+	case mode == "join" && flag.NArg() != 1:
+		ErrExit(fmt.Errorf("snail join needs the address of the host, e.g. snail join 192.168.1.2%s", defaultHostAddr))
+	case mode == "replay" && flag.NArg() != 1:
+		ErrExit(fmt.Errorf("snail replay needs the file of a recording, e.g. snail replay -heatmap game.json"))
+	case mode == "watch" && flag.NArg() != 1:
+		ErrExit(fmt.Errorf("snail watch needs the address of the game, e.g. snail watch localhost:7133"))
+	case mode == "host" && flag.NArg() > 1:
+		ErrExit(fmt.Errorf("snail host takes at most the address to listen on"))
+	case mode == "host" || mode == "join" || mode == "watch":
+		if flag.NArg() == 1 {
+			addr = flag.Arg(0)
+		}
+	case mode == "replay":
+		// the file of the recording is read below
+	case flag.NArg() > 0:
+		ErrExit(fmt.Errorf("unknown mode %q, the modes are daily, host, join, watch, serve, stats and replay", flag.Arg(0)))
+	}
+	if daily {
+		flag.Visit(func(f *flag.Flag) {
+			if f.Name == "seed" {
+				ErrExit(fmt.Errorf("snail daily is seeded from the date and cannot be played with -seed"))
+			}
+		})
+		*seed = DailySeed(time.Now())
+		*dimensions, *width, *height = dailyDimensions, 0, 0
+		*gameDelayMilliSeconds = int(dailyDelay.Milliseconds())
+		if *obstacleCount == 0 && *obstacleDensity == 0 {
+			*obstacleCount = dailyObstacles
+		}
+		if *ledgerPath == "" {
+			*ledgerPath = DataPath(defaultDailyLedger)
+		}
+	}
+	// a logged game is replayed with the config it was played with
+	var replay *engine.InputLog
+	if *replayInputPath != "" {
+		inputLog, err := engine.ReadInputLogFile(*replayInputPath)
+		ErrExit(err)
+		replay = &inputLog
+		if inputLog.Config != nil {
+			*dimensions, *width, *height = inputLog.Config.XDim, inputLog.Config.XDim, inputLog.Config.YDim
+			*boundsName, *walls = inputLog.Config.Bounds.String(), false
+		}
+		if inputLog.Delay > 0 {
+			*gameDelayMilliSeconds = int(inputLog.Delay.Milliseconds())
+		}
+		if inputLog.Rules != "" {
+			*rulesName, *zen, *reverse = inputLog.Rules, false, false
+		}
+	}
 
 	if *printVersion {
 		fmt.Printf("snail version %s\n", Version)
 		os.Exit(0)
 	}
 
+	if mode == "replay" {
+		if !*heatmap {
+			ErrExit(engine.VerifyReplayFile(flag.Arg(0)))
+			fmt.Println("replay verified")
+			os.Exit(0)
+		}
+		rec, err := engine.ReadRecordingFile(flag.Arg(0))
+		ErrExit(err)
+		ErrExit(WriteHeatmap(os.Stdout, rec.Heatmap()))
+		os.Exit(0)
+	}
+
+	if mode == "stats" {
+		records, err := ReadHistory(*historyPath)
+		ErrExit(err)
+		ErrExit(ExportHistory(os.Stdout, records, *exportFormat))
+		os.Exit(0)
+	}
+
+	if *verifyReplayPath != "" {
+		ErrExit(engine.VerifyReplayFile(*verifyReplayPath))
+		fmt.Println("replay verified")
+		os.Exit(0)
+	}
+
+	if *pprofAddr != "" {
+		ErrExit(ServePprof(*pprofAddr))
+	}
+
+	if *tracePath != "" {
+		ErrExit(StartTrace(*tracePath))
+	}
+
 	if *gameDelayMilliSeconds < 100 || *gameDelayMilliSeconds > 200 {
 		*gameDelayMilliSeconds = 150
 	}
@@ -505,8 +1542,470 @@ func main() {
 		*dimensions = 50
 	}
 
-	game := Game{}
-	game.Run(*gameDelayMilliSeconds, *dimensions)
+	if *width == 0 {
+		*width = *dimensions
+	} else if *width < 10 {
+		*width = 10
+	} else if *width > 100 {
+		*width = 100
+	}
+
+	if *height == 0 {
+		*height = *dimensions
+	} else if *height < 10 {
+		*height = 10
+	} else if *height > 50 {
+		*height = 50
+	}
+
+	if *wrapAnimFrames < 0 {
+		*wrapAnimFrames = 0
+	} else if *wrapAnimFrames > 2 {
+		*wrapAnimFrames = 2
+	}
+
+	if *deathAnimFrames < 0 {
+		*deathAnimFrames = 0
+	} else if *deathAnimFrames > 10 {
+		*deathAnimFrames = 10
+	}
+
+	if *scoreWeight < 1 {
+		*scoreWeight = 1
+	} else if *scoreWeight > 500 {
+		*scoreWeight = 500
+	}
+
+	if *comboWindow < 0 {
+		*comboWindow = 0
+	} else if *comboWindow > 100 {
+		*comboWindow = 100
+	}
+
+	if *foodMinMoves < 0 {
+		*foodMinMoves = 0
+	} else if *foodMinMoves > 10 {
+		*foodMinMoves = 10
+	}
+
+	if *padding < 0 {
+		*padding = 0
+	} else if *padding > 20 {
+		*padding = 20
+	}
+
+	if *growth < 1 {
+		*growth = engine.NoGrowth
+	} else if *growth > 10 {
+		*growth = 10
+	}
+
+	if *autoError < 0 {
+		*autoError = 0
+	} else if *autoError > 1 {
+		*autoError = 1
+	}
+
+	if *objectiveFoods < 0 {
+		*objectiveFoods = 0
+	}
+	if *moveBudget < 0 {
+		*moveBudget = 0
+	}
+
+	if *foodWallMargin < 0 {
+		*foodWallMargin = 0
+	} else if *foodWallMargin > 5 {
+		*foodWallMargin = 5
+	}
+
+	if *obstacleCount < 0 {
+		*obstacleCount = 0
+	} else if *obstacleCount > 500 {
+		*obstacleCount = 500
+	}
+
+	if *obstacleDensity < 0 {
+		*obstacleDensity = 0
+	} else if *obstacleDensity > 30 {
+		*obstacleDensity = 30
+	}
+
+	if *winBonus < 0 {
+		*winBonus = 0
+	} else if *winBonus > 1000 {
+		*winBonus = 1000
+	}
+
+	if *growthLag < 0 {
+		*growthLag = 0
+	} else if *growthLag > 10 {
+		*growthLag = 10
+	}
+	if *trainer < 0 {
+		*trainer = 0
+	} else if *trainer > 5 {
+		*trainer = 5
+	}
+	if *perfectFlash < 0 {
+		*perfectFlash = 0
+	} else if *perfectFlash > 20 {
+		*perfectFlash = 20
+	}
+	if *jitter < 0 {
+		*jitter = 0
+	} else if *jitter > 50 {
+		*jitter = 50
+	}
+	if *rewindChance < 0 {
+		*rewindChance = 0
+	} else if *rewindChance > 100 {
+		*rewindChance = 100
+	}
+	if *foodTTL < 0 {
+		*foodTTL = 0
+	} else if *foodTTL > 1000 {
+		*foodTTL = 1000
+	}
+	if *foodTTLPenalty < 0 {
+		*foodTTLPenalty = 0
+	} else if *foodTTLPenalty > 100 {
+		*foodTTLPenalty = 100
+	}
+	if *grace < 0 {
+		*grace = 0
+	} else if *grace > 20 {
+		*grace = 20
+	}
+	if *lives < 0 {
+		*lives = 0
+	} else if *lives > 9 {
+		*lives = 9
+	}
+	if *hardcore < 0 {
+		*hardcore = 0
+	} else if *hardcore > 0 && *hardcore < 20 {
+		*hardcore = 20
+	} else if *hardcore > 100 {
+		*hardcore = 100
+	}
+	if *poisonChance < 0 {
+		*poisonChance = 0
+	} else if *poisonChance > 100 {
+		*poisonChance = 100
+	}
+	if *powerUpChance < 0 {
+		*powerUpChance = 0
+	} else if *powerUpChance > 100 {
+		*powerUpChance = 100
+	}
+	if *goldenChance < 0 {
+		*goldenChance = 0
+	} else if *goldenChance > 100 {
+		*goldenChance = 100
+	}
+	if *foodWander < 0 {
+		*foodWander = 0
+	} else if *foodWander > 0 && *foodWander < 2 {
+		*foodWander = 2
+	} else if *foodWander > 50 {
+		*foodWander = 50
+	}
+	if *eventInterval < 0 {
+		*eventInterval = 0
+	} else if *eventInterval > 1000 {
+		*eventInterval = 1000
+	}
+	if *layers < 1 {
+		*layers = 1
+	} else if *layers > engine.MaxLayers {
+		*layers = engine.MaxLayers
+	}
+	if *ladders < 1 {
+		*ladders = 1
+	} else if *ladders > 5 {
+		*ladders = 5
+	}
+	if *portalPairs < 0 {
+		*portalPairs = 0
+	} else if *portalPairs > 5 {
+		*portalPairs = 5
+	}
+	if *fogRadius < 0 {
+		*fogRadius = 0
+	} else if *fogRadius > 0 && *fogRadius < 2 {
+		*fogRadius = 2
+	} else if *fogRadius > 20 {
+		*fogRadius = 20
+	}
+	if *timeAttack < 0 {
+		*timeAttack = 0
+	} else if *timeAttack > 600 {
+		*timeAttack = 600
+	}
+	if *timeAttack > 0 {
+		*maxDuration = time.Duration(*timeAttack) * time.Second
+	}
+	if *antiStall < 0 {
+		*antiStall = 0
+	} else if *antiStall > 1000 {
+		*antiStall = 1000
+	}
+
+	if *walls {
+		*boundsName = "walls"
+	}
+	bounds, err := engine.ParseBounds(*boundsName)
+	ErrExit(err)
+	eatRule, err := engine.ParseEatRule(*eatRuleName)
+	ErrExit(err)
+	if *tron {
+		*growthRuleName = "trail"
+		*scoreRuleName = "survival"
+	}
+	growthRule, err := engine.ParseGrowthRule(*growthRuleName)
+	ErrExit(err)
+	scoreRule, err := engine.ParseScoreRule(*scoreRuleName)
+	ErrExit(err)
+	_, err = engine.ParseScoringPolicy(*scoring)
+	ErrExit(err)
+	runeMode, err := ParseRuneMode(*runeModeName)
+	ErrExit(err)
+	mirror, err := ParseMirror(*mirrorName)
+	ErrExit(err)
+	tiers, err := ParseScoreTiers(*scoreTiers)
+	ErrExit(err)
+
+	if mode == "join" {
+		ErrExit(JoinGame(addr, runeMode))
+		Exit(0)
+	}
+	if mode == "watch" {
+		ErrExit(WatchGame(addr, runeMode))
+		Exit(0)
+	}
+
+	config := engine.Config{
+		Growth:          *growth,
+		ObjectiveFoods:  *objectiveFoods,
+		MoveBudget:      *moveBudget,
+		EatRule:         eatRule,
+		GrowthRule:      growthRule,
+		ScoreRule:       scoreRule,
+		ScoreWeight:     *scoreWeight,
+		ComboWindow:     *comboWindow,
+		Scoring:         *scoring,
+		FoodMinMoves:    *foodMinMoves,
+		FoodWallMargin:  *foodWallMargin,
+		ObstacleCount:   *obstacleCount,
+		ObstacleDensity: *obstacleDensity,
+		PortalPairs:     *portalPairs,
+		Layers:          *layers,
+		EventInterval:   *eventInterval,
+		Ladders:         *ladders,
+		Dual:            *dual,
+		Versus:          *versus,
+		TwoPlayers:      *twoPlayers,
+		LongerWins:      *longerWins,
+		FoodWander:      *foodWander,
+		PoisonChance:    *poisonChance,
+		GoldenChance:    *goldenChance,
+		PowerUpChance:   *powerUpChance,
+		WinBonus:        *winBonus,
+		AntiStall:       *antiStall,
+		Grace:           *grace,
+		Lives:           *lives,
+		GrowthLag:       *growthLag,
+		RewindChance:    *rewindChance,
+		FoodTTL:         *foodTTL,
+		FoodTTLPenalty:  *foodTTLPenalty,
+	}
+
+	if *autopilot || *simulate > 0 {
+		config.Autopilot = &engine.Autopilot{ErrorRate: *autoError}
+	}
+
+	if *foodScriptPath != "" {
+		script, err := engine.ReadFoodScriptFile(*foodScriptPath)
+		ErrExit(err)
+		config.FoodScript = script
+	}
+
+	if debug.Path != "" {
+		logger, err := OpenDebugLogger(debug.Path)
+		ErrExit(err)
+		config.Logger = logger
+	}
+
+	if *zen {
+		*rulesName = "zen"
+	}
+	if *reverse {
+		*rulesName = "reverse"
+	}
+	rules, err := LoadRules(*rulesName)
+	ErrExit(err)
+	config.Rules = rules
+	if *rulesName == "reverse" {
+		config.StartLength = ReverseStartLength(*width, *height)
+	}
+
+	if *scriptPath != "" {
+		script, err := OpenLuaScript(*scriptPath)
+		ErrExit(err)
+		config.Script = script
+	}
+
+	config.Replay = replay
+
+	// the options set by WithConfig come first, the ones after change them
+	options := []Option{
+		WithConfig(config),
+		WithSize(*width, *height),
+		WithDelay(time.Duration(*gameDelayMilliSeconds) * time.Millisecond),
+		WithBounds(bounds),
+		WithSeed(*seed),
+		WithTheme(Theme{
+			Runes:           runeMode,
+			Padding:         *padding,
+			Gridlines:       *gridlines,
+			SmoothSnake:     *smoothSnake,
+			WrapAnimFrames:  *wrapAnimFrames,
+			DeathAnimFrames: *deathAnimFrames,
+			PerfectFlash:    *perfectFlash,
+		}),
+		WithControls(Controls{Mirror: mirror, Debounce: *debounce}),
+		WithOutputs(Outputs{
+			RecordPath:     *recordPath,
+			InputLogPath:   *inputLogPath,
+			SavePath:       *savePath,
+			LedgerPath:     *ledgerPath,
+			HighScorePath:  *highScorePath,
+			HistoryPath:    *historyPath,
+			AutosaveDir:    *autosaveDir,
+			PrintBreakdown: *printBreakdown,
+		}),
+		WithMaxDuration(*maxDuration, *timeAttack > 0),
+		WithHardcore(time.Duration(*hardcore) * time.Millisecond),
+		WithJitter(*jitter),
+		WithFog(*fogRadius),
+		WithTrainer(*trainer),
+		WithScoreTiers(tiers),
+	}
+	if daily {
+		options = append(options, WithDaily())
+	}
+
+	if *layers > 1 && (*levelPath != "" || *campaignPath != "") {
+		ErrExit(fmt.Errorf("levels and the campaign have a single layer, -layers cannot be used with them"))
+	}
+	if *levelPath != "" {
+		level, err := engine.ReadLevelFile(*levelPath)
+		ErrExit(err)
+		if err := level.CheckStart(bounds); err != nil {
+			ErrExit(fmt.Errorf("%s: %w", *levelPath, err))
+		}
+		options = append(options, WithLevel(level))
+	}
+
+	if *campaignPath != "" {
+		campaign, err := LoadCampaign(*campaignPath)
+		ErrExit(err)
+		options = append(options, WithCampaign(campaign))
+	}
+
+	if *commandPath != "" {
+		input, err := OpenCommandFile(*commandPath)
+		ErrExit(err)
+		options = append(options, WithInput(input))
+	}
+
+	// only games played by the player unlock achievements
+	if *achievementsPath != "" && config.Autopilot == nil && config.Replay == nil {
+		achievements, err := LoadAchievements(*achievementsPath)
+		ErrExit(err)
+		options = append(options, WithAchievements(achievements))
+	}
+	if *ghost {
+		dir := DataPath("ghosts")
+		if dir == "" {
+			ErrExit(fmt.Errorf("there is no data directory to keep the ghosts in, set XDG_DATA_HOME"))
+		}
+		options = append(options, WithGhosts(dir))
+	}
+
+	game := NewGame(options...)
+	if *resumePath != "" {
+		if mode == "serve" || *grpcAddr != "" {
+			ErrExit(fmt.Errorf("-resume continues a single game, it cannot be shared by the games of a server"))
+		}
+		ErrExit(game.LoadState(*resumePath))
+	}
+
+	if *streamAddr != "" {
+		streamer := NewStreamer()
+		ErrExit(streamer.Listen(*streamAddr))
+		streamer.Attach(&game.Hooks)
+	}
+	if *spectateAddr != "" {
+		if mode == "serve" {
+			ErrExit(fmt.Errorf("snail serve cannot share its sessions with watchers"))
+		}
+		spectators, err := ListenSpectators(*spectateAddr)
+		ErrExit(err)
+		spectators.Attach(&game.Hooks)
+	}
+
+	if *simulate > 0 {
+		ErrExit(game.Simulate(os.Stdout, *simulate))
+		Exit(0)
+	}
+
+	if *headless {
+		ErrExit(game.RunHeadless(os.Stdout))
+		Exit(0)
+	}
+
+	if *grpcAddr != "" {
+		ErrExit(NewGameServer(*game).Serve(*grpcAddr))
+		Exit(0)
+	}
+
+	if *dryRun {
+		ErrExit(game.DryRun(os.Stdout))
+		Exit(0)
+	}
+
+	if mode == "serve" {
+		if *campaignPath != "" || *scriptPath != "" {
+			ErrExit(fmt.Errorf("snail serve cannot share a campaign or a script between sessions"))
+		}
+		// the signals are handled once for all sessions, which each only
+		// quit their own game
+		ctx, stop := NotifyShutdown(context.Background())
+		server, err := NewSSHServer(*game)
+		if err == nil {
+			err = server.Serve(ctx, *sshAddr, *sshHostKey)
+		}
+		stop()
+		ErrExit(err)
+		Exit(0)
+	}
+
+	if mode == "host" {
+		host, err := HostGame(addr)
+		ErrExit(err)
+		host.Attach(game)
+		err = game.Run()
+		host.Close()
+		ErrExit(err)
+		if host.Left() {
+			fmt.Println("The second player left the game.")
+		}
+		Exit(0)
+	}
+
+	ErrExit(game.Run())
 
-	os.Exit(0)
+	Exit(0)
 }
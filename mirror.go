@@ -0,0 +1,77 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/q713/snail/engine"
+)
+
+// Mirror decides which direction keys are swapped in mirror mode.
+type Mirror int
+
+const (
+	// MirrorOff keeps the controls.
+	MirrorOff Mirror = iota
+	// MirrorHorizontal swaps left and right.
+	MirrorHorizontal
+	// MirrorBoth swaps left and right as well as up and down.
+	MirrorBoth
+)
+
+var mirrorNames = map[string]Mirror{
+	"off": MirrorOff,
+	"lr":  MirrorHorizontal,
+	"all": MirrorBoth,
+}
+
+// ParseMirror returns the Mirror for the given name.
+func ParseMirror(name string) (Mirror, error) {
+	if mirror, ok := mirrorNames[name]; ok {
+		return mirror, nil
+	}
+	return MirrorOff, fmt.Errorf("unknown mirror mode %q", name)
+}
+
+func (mirror Mirror) String() string {
+	for name, value := range mirrorNames {
+		if value == mirror {
+			return name
+		}
+	}
+	return ""
+}
+
+// Apply returns the direction the snail turns into for the direction key
+// dir.
+func (mirror Mirror) Apply(dir engine.Velocity) engine.Velocity {
+	switch {
+	case mirror == MirrorOff:
+	case dir.Equals(engine.EastDir) || dir.Equals(engine.WestDir):
+		dir.X = -dir.X
+	case mirror == MirrorBoth:
+		dir.Y = -dir.Y
+	}
+	return dir
+}
@@ -0,0 +1,439 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// defaultHostAddr is the address snail host listens on if none is given.
+const defaultHostAddr = ":7132"
+
+// netProtocol is the version of the protocol between host and client, both
+// have to speak the same one.
+const netProtocol = 1
+
+// netWriteTimeout is how long the host waits for a frame to be written before
+// it gives up on the client.
+const netWriteTimeout = 5 * time.Second
+
+// netFrameBuffer is the number of frames queued for the client before older
+// ones are dropped.
+const netFrameBuffer = 4
+
+// NetHello is the first line the host sends to a client that joined.
+type NetHello struct {
+	Protocol int
+	Version  string
+}
+
+// NetFrame is the authoritative state of the game the host sends to the
+// client after every tick. Every frame is complete, so a client that missed
+// frames catches up with the next one.
+type NetFrame struct {
+	StreamFrame
+	// Round counts the games played since the client joined.
+	Round      int
+	Rival      []engine.Pos
+	RivalScore int
+	Result     string `json:",omitempty"`
+}
+
+// NetFrame returns the NetFrame of the current state of the game in round.
+func (game *Game) NetFrame(round int) NetFrame {
+	frame := NetFrame{StreamFrame: game.Frame(), Round: round}
+	if rivals := game.Rivals(); len(rivals) > 0 {
+		frame.Rival = rivals[0].Snail.Body
+		frame.RivalScore = rivals[0].Scorer.Score
+	}
+	if frame.Over {
+		frame.Result = game.RivalOutcome().String()
+	}
+	return frame
+}
+
+// newer reports whether frame is more recent than last.
+func (frame NetFrame) newer(last NetFrame) bool {
+	return frame.Round > last.Round || frame.Round == last.Round && frame.Tick >= last.Tick
+}
+
+// NetHost plays a two-player game in which the second snail is steered by a
+// client that joined over TCP. The host runs the game and sends its state to
+// the client, the client only sends its inputs.
+type NetHost struct {
+	conn   net.Conn
+	frames chan []byte
+	remote chan Command
+	round  int
+	// done is closed by Close, once nothing reads remote or the keyboard of
+	// the host anymore
+	done      chan struct{}
+	closeOnce sync.Once
+
+	mu   sync.Mutex
+	left bool
+}
+
+// HostGame waits for a client to join on addr and returns the NetHost for
+// it. Only one client is accepted.
+func HostGame(addr string) (*NetHost, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+	fmt.Printf("Waiting for the second player to join on %s...\n", listener.Addr())
+	return acceptHost(listener)
+}
+
+// acceptHost waits for a client to join on listener and returns the NetHost
+// for it.
+func acceptHost(listener net.Listener) (*NetHost, error) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	host := &NetHost{
+		conn:   conn,
+		frames: make(chan []byte, netFrameBuffer),
+		remote: make(chan Command),
+		done:   make(chan struct{}),
+	}
+	if err := host.write(NetHello{Protocol: netProtocol, Version: Version}); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	go host.readCommands()
+	go host.writeFrames()
+	return host, nil
+}
+
+func (host *NetHost) write(value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	host.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	_, err = host.conn.Write(append(data, '\n'))
+	return err
+}
+
+// readCommands passes the directions of the client on as the ones of the
+// second player. The host is quit once the client leaves, unless it was
+// closed before.
+func (host *NetHost) readCommands() {
+	scanner := bufio.NewScanner(host.conn)
+	for scanner.Scan() {
+		command, ok := commandNames[scanner.Text()]
+		if !ok {
+			continue
+		}
+		if dir, ok := commandDirections[command]; ok && !host.pass(rivalCommands[dir]) {
+			return
+		}
+	}
+	host.mu.Lock()
+	host.left = true
+	host.mu.Unlock()
+	host.pass(CommandQuit)
+}
+
+// pass hands command to the game and reports false if the host was closed
+// instead.
+func (host *NetHost) pass(command Command) bool {
+	select {
+	case host.remote <- command:
+		return true
+	case <-host.done:
+		return false
+	}
+}
+
+// writeFrames writes the queued frames to the client until it leaves or the
+// host is closed.
+func (host *NetHost) writeFrames() {
+	for {
+		var data []byte
+		select {
+		case data = <-host.frames:
+		case <-host.done:
+			return
+		}
+		host.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+		if _, err := host.conn.Write(append(data, '\n')); err != nil {
+			// the client is gone, reading notices it as well
+			host.conn.Close()
+			return
+		}
+	}
+}
+
+// Send queues the current state of game for the client. It does not block,
+// the oldest queued frame is dropped if the client falls behind.
+func (host *NetHost) Send(game *Game) {
+	data, err := json.Marshal(game.NetFrame(host.round))
+	if err != nil {
+		return
+	}
+	queueFrame(host.frames, data)
+}
+
+// queueFrame queues data in frames, dropping the oldest queued frame if
+// frames is full.
+func queueFrame(frames chan []byte, data []byte) {
+	for {
+		select {
+		case frames <- data:
+			return
+		default:
+		}
+		select {
+		case <-frames:
+		default:
+		}
+	}
+}
+
+// Attach makes game a two-player game steered by the keyboard of the host and
+// the client, and sends a frame to the client after every tick and at the
+// end of every game.
+func (host *NetHost) Attach(game *Game) {
+	game.config.TwoPlayers = true
+	game.Input = &HostInput{Game: game, Remote: host.remote, Done: host.done}
+	game.Hooks.OnTick(host.Send)
+	game.Hooks.OnGameOver(func(game *Game, won bool) {
+		host.Send(game)
+		host.round += 1
+	})
+}
+
+// Left reports whether the client left the game.
+func (host *NetHost) Left() bool {
+	host.mu.Lock()
+	defer host.mu.Unlock()
+	return host.left
+}
+
+// Close ends the connection to the client and stops passing on its
+// commands. It is called once the game loop returned.
+func (host *NetHost) Close() error {
+	host.closeOnce.Do(func() { close(host.done) })
+	return host.conn.Close()
+}
+
+// HostInput merges the keyboard of the host, on which all direction keys
+// steer the first snail, with the Remote commands of the client. The keyboard
+// stops being read once Done is closed.
+type HostInput struct {
+	Game   *Game
+	Remote <-chan Command
+	Done   <-chan struct{}
+
+	once sync.Once
+	keys chan Command
+}
+
+func (input *HostInput) NextCommand() Command {
+	input.once.Do(func() {
+		// the screen is only set up once the game runs
+		keyboard := KeyboardInput{Screen: input.Game.Screen}
+		input.keys = make(chan Command)
+		go func() {
+			for {
+				command := keyboard.NextCommand()
+				select {
+				case input.keys <- command:
+				case <-input.Done:
+					return
+				}
+			}
+		}()
+	})
+	select {
+	case command := <-input.keys:
+		return command
+	case command := <-input.Remote:
+		return command
+	}
+}
+
+// rivalCommands are the commands of the second player for the directions
+// sent by the client.
+var rivalCommands = map[engine.Velocity]Command{
+	engine.NorthDir: CommandRivalNorth,
+	engine.SouthDir: CommandRivalSouth,
+	engine.EastDir:  CommandRivalEast,
+	engine.WestDir:  CommandRivalWest,
+}
+
+// netClosed is posted to the screen of the client once the connection to
+// the host is lost.
+type netClosed struct {
+	err error
+}
+
+// JoinGame joins the game hosted on addr and plays the second snail with any
+// of the direction keys until Escape is pressed or the host leaves.
+func JoinGame(addr string, mode RuneMode) error {
+	conn, scanner, err := dialHost(addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	screen, err := InitScreen()
+	if err != nil {
+		return err
+	}
+	defer screen.Fini()
+	canvas := NewTcellCanvas(screen, engine.Pos{}, mode)
+	go postFrames(scanner, screen)
+
+	var last NetFrame
+	for {
+		switch event := screen.PollEvent().(type) {
+		case nil:
+			return nil
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			command, ok := keyCommand(event)
+			if !ok {
+				continue
+			}
+			if command == CommandQuit {
+				return nil
+			}
+			if dir, ok := commandDirections[command]; ok {
+				conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+				if _, err := fmt.Fprintln(conn, engine.DirectionInput(dir)); err != nil {
+					return err
+				}
+			}
+		case *tcell.EventInterrupt:
+			switch data := event.Data().(type) {
+			case NetFrame:
+				if data.newer(last) {
+					last = data
+					DrawNetFrame(canvas, last)
+				}
+			case netClosed:
+				if data.err != nil && !errors.Is(data.err, net.ErrClosed) {
+					return fmt.Errorf("connection to the host lost: %w", data.err)
+				}
+				return fmt.Errorf("the host left the game")
+			}
+		}
+	}
+}
+
+// dialHost connects to the host on addr and reads its hello. The returned
+// scanner reads the frames that follow.
+func dialHost(addr string) (net.Conn, *bufio.Scanner, error) {
+	conn, err := net.DialTimeout("tcp", addr, netWriteTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	scanner := bufio.NewScanner(conn)
+	// frames of large boards are longer than the default limit of a line
+	scanner.Buffer(nil, 1<<20)
+	if !scanner.Scan() {
+		conn.Close()
+		return nil, nil, fmt.Errorf("the host closed the connection")
+	}
+	var hello NetHello
+	if err := json.Unmarshal(scanner.Bytes(), &hello); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("cannot read hello of the host: %w", err)
+	}
+	if hello.Protocol != netProtocol {
+		conn.Close()
+		return nil, nil, fmt.Errorf("the host speaks protocol %d (snail %s), expected %d", hello.Protocol, hello.Version, netProtocol)
+	}
+	return conn, scanner, nil
+}
+
+// postFrames posts the frames read by scanner to screen, followed by
+// netClosed once the connection is lost.
+func postFrames(scanner *bufio.Scanner, screen tcell.Screen) {
+	for scanner.Scan() {
+		var frame NetFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			screen.PostEvent(tcell.NewEventInterrupt(netClosed{err: err}))
+			return
+		}
+		screen.PostEvent(tcell.NewEventInterrupt(frame))
+	}
+	screen.PostEvent(tcell.NewEventInterrupt(netClosed{err: scanner.Err()}))
+}
+
+// DrawNetFrame draws frame as the client sees it: the own snail is the
+// second one.
+func DrawNetFrame(canvas Canvas, frame NetFrame) {
+	status := fmt.Sprintf("Score: %d P1: %d", frame.RivalScore, frame.Score)
+	drawNetFrame(canvas, frame, frame.Rival, frame.Snail, status, "Waiting for the host... Quit? Esc")
+}
+
+// drawNetFrame draws the board of frame with own in the colors of the player
+// and other in the ones of the rival.
+func drawNetFrame(canvas Canvas, frame NetFrame, own, other []engine.Pos, status, waiting string) {
+	canvas.Clear()
+	canvas.DrawBorder(frame.Width, frame.Height, wallStyle)
+	for _, pos := range frame.Obstacles {
+		canvas.DrawCell(pos.X, pos.Y, CellObstacle, wallStyle)
+	}
+	canvas.DrawCell(frame.Food.X, frame.Food.Y, CellFood, foodStyle)
+	for _, snail := range []struct {
+		body  []engine.Pos
+		style tcell.Style
+	}{{other, rivalStyle}, {own, snailBodySytle}} {
+		for index, pos := range snail.body {
+			if index == len(snail.body)-1 {
+				canvas.DrawCell(pos.X, pos.Y, CellSnailHead, snailHeadSytle)
+			} else {
+				canvas.DrawCell(pos.X, pos.Y, CellSnailBody, snail.style)
+			}
+		}
+	}
+	canvas.DrawText(1, 0, status, blackWhiteStyle)
+	if frame.Over {
+		first := "Game Over!"
+		if frame.Result != "" {
+			first = "Game Over, " + frame.Result
+		}
+		texts := []string{first, waiting}
+		for index, text := range texts {
+			canvas.DrawText(frame.Width-len(text)/2+1, frame.Height/2+1+index, text, blackWhiteStyle)
+		}
+	}
+	canvas.Present()
+}
@@ -0,0 +1,132 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// readFrame reads the frames sent on conn until the one of tick.
+func readFrame(t *testing.T, conn net.Conn, scanner *bufio.Scanner, tick int) NetFrame {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for scanner.Scan() {
+		var frame NetFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			t.Fatal(err)
+		}
+		if frame.Tick >= tick {
+			return frame
+		}
+	}
+	t.Fatalf("no frame of tick %d: %v", tick, scanner.Err())
+	return NetFrame{}
+}
+
+func TestNetPlay(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	hosts := make(chan *NetHost, 1)
+	go func() {
+		host, err := acceptHost(listener)
+		if err != nil {
+			t.Error(err)
+		}
+		hosts <- host
+	}()
+	conn, scanner, err := dialHost(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	host := <-hosts
+	if host == nil {
+		t.FailNow()
+	}
+	defer host.Close()
+
+	// both snails head east, the one of the host in row 2 and the one of
+	// the client in row 7
+	h := newHarness(t, func(game *Game) { host.Attach(game) })
+	h.key(tcell.KeyRune, 's')
+	fmt.Fprintln(conn, engine.DirectionInput(engine.NorthDir))
+	h.waitFor("both turns to be queued", func(game *Game) bool {
+		return len(game.turns) > 0 && len(game.rivalTurns) > 0
+	})
+	for tick := 2; tick <= 4; tick++ {
+		h.tick()
+		frame := readFrame(t, conn, scanner, tick)
+		own, rival := frame.Snail[len(frame.Snail)-1], frame.Rival[len(frame.Rival)-1]
+		if want := (engine.Pos{X: 8, Y: 1 + tick}); own != want {
+			t.Errorf("tick %d: snail of the host at %v, want %v", tick, own, want)
+		}
+		if want := (engine.Pos{X: 8, Y: 8 - tick}); rival != want {
+			t.Errorf("tick %d: snail of the client at %v, want %v", tick, rival, want)
+		}
+	}
+
+	conn.Close()
+	select {
+	case err := <-h.done:
+		h.done <- err
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the host did not quit once the client left")
+	}
+	if !host.Left() {
+		t.Error("the host did not notice that the client left")
+	}
+}
+
+func TestNetHostClose(t *testing.T) {
+	conn, client := net.Pipe()
+	defer client.Close()
+	host := &NetHost{conn: conn, remote: make(chan Command), done: make(chan struct{})}
+	returned := make(chan struct{})
+	go func() {
+		host.readCommands()
+		close(returned)
+	}()
+	// the direction is read but the game loop already returned, so nothing
+	// takes it
+	fmt.Fprintln(client, engine.DirectionInput(engine.NorthDir))
+	host.Close()
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reading the commands did not stop once the host was closed")
+	}
+}
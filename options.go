@@ -0,0 +1,309 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// DefaultDimensions is the number of rows and columns of the grid if no
+// dimensions are set.
+const DefaultDimensions = 20
+
+// DefaultDelay is the delay between two ticks if no delay is set.
+const DefaultDelay = 150 * time.Millisecond
+
+// Option configures a game before it is started with Run, RunHeadless,
+// Simulate, DryRun or NewGameServer.
+type Option func(game *Game)
+
+// NewGame returns a game configured by options.
+func NewGame(options ...Option) *Game {
+	game := &Game{}
+	game.Apply(options...)
+	return game
+}
+
+// sessionCopy returns a copy of game for a session of a server. It plays like
+// game, but shares neither the config nor the hooks with game or other
+// sessions.
+func (game *Game) sessionCopy() *Game {
+	session := *game
+	session.Game = nil
+	session.config = game.config.Clone()
+	session.Hooks = game.Hooks.clone()
+	return &session
+}
+
+// WithConfig plays by the rules of config. Options applied after it change
+// single settings of it, like WithSize or WithSeed.
+func WithConfig(config engine.Config) Option {
+	return func(game *Game) {
+		game.config = config
+	}
+}
+
+// WithDimensions plays on a grid of dimensions x dimensions cells.
+func WithDimensions(dimensions int) Option {
+	return WithSize(dimensions, dimensions)
+}
+
+// WithSize plays on a grid of width columns and height rows.
+func WithSize(width, height int) Option {
+	return func(game *Game) {
+		game.config.XDim = width
+		game.config.YDim = height
+	}
+}
+
+// WithLevel plays on level, its size replaces the dimensions.
+func WithLevel(level *engine.Level) Option {
+	return func(game *Game) {
+		game.config.Level = level
+		game.config.XDim = level.Width
+		game.config.YDim = level.Height
+	}
+}
+
+// WithDelay starts every game with delay between two ticks.
+func WithDelay(delay time.Duration) Option {
+	return func(game *Game) {
+		game.GameDelayMilliSeconds = delay
+		game.startDelay = delay
+	}
+}
+
+// WithBounds sets what happens at the edges of the grid.
+func WithBounds(bounds engine.Bounds) Option {
+	return func(game *Game) {
+		game.config.Bounds = bounds
+	}
+}
+
+// WithSeed seeds all random choices of a game, zero picks a new seed per
+// game.
+func WithSeed(seed int64) Option {
+	return func(game *Game) {
+		game.config.Seed = seed
+	}
+}
+
+// WithRunes sets whether cells are drawn with characters instead of colors.
+func WithRunes(mode RuneMode) Option {
+	return func(game *Game) {
+		game.Runes = mode
+	}
+}
+
+// Theme is how the board is drawn.
+type Theme struct {
+	// Runes sets whether cells are drawn with characters instead of colors.
+	Runes RuneMode
+	// Padding is the number of empty rows and columns around the board.
+	Padding int
+	// Gridlines draws a faint dot on every empty cell.
+	Gridlines bool
+	// SmoothSnake draws the body of the snail as a connected line.
+	SmoothSnake bool
+	// WrapAnimFrames and DeathAnimFrames are the frames of the animations
+	// shown when the snail wraps around an edge and when it dies.
+	WrapAnimFrames  int
+	DeathAnimFrames int
+	// PerfectFlash is the number of ticks PERFECT! is shown.
+	PerfectFlash int
+	// TierStyles are the styles of the final scores that reach a tier, zero
+	// uses DefaultTierStyles.
+	TierStyles TierStyles
+}
+
+// WithTheme draws the board with theme.
+func WithTheme(theme Theme) Option {
+	return func(game *Game) {
+		game.Runes = theme.Runes
+		game.Padding = theme.Padding
+		game.Gridlines = theme.Gridlines
+		game.SmoothSnake = theme.SmoothSnake
+		game.WrapAnimFrames = theme.WrapAnimFrames
+		game.DeathAnimFrames = theme.DeathAnimFrames
+		game.PerfectFlash = theme.PerfectFlash
+		game.TierStyles = theme.TierStyles
+	}
+}
+
+// Controls is how the keys steer the snail.
+type Controls struct {
+	// Mirror swaps the direction keys.
+	Mirror Mirror
+	// Debounce drops repeats of the same direction within its window.
+	Debounce time.Duration
+}
+
+// WithControls steers the snail with controls.
+func WithControls(controls Controls) Option {
+	return func(game *Game) {
+		game.Mirror = controls.Mirror
+		game.Debounce = controls.Debounce
+	}
+}
+
+// Outputs are the files a game writes its results to, empty paths are not
+// written.
+type Outputs struct {
+	// RecordPath is the recording of the last game, see SaveRecording.
+	RecordPath string
+	// InputLogPath is the input log of the last game.
+	InputLogPath string
+	// SavePath is the state of an unfinished game on quitting, see SaveState.
+	SavePath string
+	// LedgerPath, HighScorePath and HistoryPath keep the results of all games.
+	LedgerPath    string
+	HighScorePath string
+	HistoryPath   string
+	// AutosaveDir keeps the final board of every game as text.
+	AutosaveDir string
+	// PrintBreakdown prints the points per food of the last game on exit.
+	PrintBreakdown bool
+}
+
+// WithOutputs writes the results of the games to outputs.
+func WithOutputs(outputs Outputs) Option {
+	return func(game *Game) {
+		game.RecordPath = outputs.RecordPath
+		game.InputLogPath = outputs.InputLogPath
+		game.SavePath = outputs.SavePath
+		game.LedgerPath = outputs.LedgerPath
+		game.HighScorePath = outputs.HighScorePath
+		game.HistoryPath = outputs.HistoryPath
+		game.AutosaveDir = outputs.AutosaveDir
+		game.PrintBreakdown = outputs.PrintBreakdown
+	}
+}
+
+// WithMaxDuration ends every game after d, zero plays without a time limit.
+// With timeAttack the game is a time attack, see DrawTimeAttack.
+func WithMaxDuration(d time.Duration, timeAttack bool) Option {
+	return func(game *Game) {
+		game.MaxDuration = d
+		game.TimeAttack = timeAttack
+	}
+}
+
+// WithHardcore speeds the game up with every eaten food down to minDelay,
+// see AdjustDelay. Zero turns hardcore mode off.
+func WithHardcore(minDelay time.Duration) Option {
+	return func(game *Game) {
+		game.MinDelay = minDelay
+	}
+}
+
+// WithJitter changes the delay of every tick by a random amount of up to
+// percent in either direction.
+func WithJitter(percent int) Option {
+	return func(game *Game) {
+		game.Jitter = percent
+	}
+}
+
+// WithFog only shows the board within radius moves of the head, zero shows
+// all of it.
+func WithFog(radius int) Option {
+	return func(game *Game) {
+		game.FogRadius = radius
+	}
+}
+
+// WithTrainer marks the next count food positions of the food script.
+func WithTrainer(count int) Option {
+	return func(game *Game) {
+		game.Trainer = count
+	}
+}
+
+// WithScoreTiers rates the final scores by tiers.
+func WithScoreTiers(tiers ScoreTiers) Option {
+	return func(game *Game) {
+		game.ScoreTiers = tiers
+	}
+}
+
+// WithDaily plays the daily challenge, see DailySeed.
+func WithDaily() Option {
+	return func(game *Game) {
+		game.Daily = true
+	}
+}
+
+// WithCampaign plays the levels of campaign one after the other.
+func WithCampaign(campaign *Campaign) Option {
+	return func(game *Game) {
+		game.Campaign = campaign
+	}
+}
+
+// WithAchievements unlocks achievements in the games played.
+func WithAchievements(achievements *Achievements) Option {
+	return func(game *Game) {
+		game.Achievements = achievements
+	}
+}
+
+// WithGhosts races against the best games kept in dir, see LoadGhost.
+func WithGhosts(dir string) Option {
+	return func(game *Game) {
+		game.GhostDir = dir
+	}
+}
+
+// WithInput reads the commands from input instead of the keyboard.
+func WithInput(input InputSource) Option {
+	return func(game *Game) {
+		game.Input = input
+	}
+}
+
+// WithScreen draws on screen instead of the terminal, e.g. on a
+// tcell.SimulationScreen that tests inject keys into and read cells from. The
+// screen is initialized by Run.
+func WithScreen(screen tcell.Screen) Option {
+	return func(game *Game) {
+		game.Screen = screen
+	}
+}
+
+// WithClock measures the time with clock, e.g. one that runs faster in
+// tests.
+func WithClock(clock Clock) Option {
+	return func(game *Game) {
+		game.Clock = clock
+	}
+}
+
+// Apply applies options to the game.
+func (game *Game) Apply(options ...Option) {
+	for _, option := range options {
+		option(game)
+	}
+}
@@ -0,0 +1,75 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"net"
+	"net/http"
+	_ "net/http/pprof"
+	"os"
+	"runtime/trace"
+)
+
+// traceFile is the file the execution trace is written to, if tracing.
+var traceFile *os.File
+
+// ServePprof serves the profiles of net/http/pprof on addr in the background.
+func ServePprof(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go http.Serve(listener, http.DefaultServeMux)
+	return nil
+}
+
+// StartTrace writes an execution trace to the file at path until StopTrace
+// is called. The ticks and redraws of the game loop are marked as regions.
+func StartTrace(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := trace.Start(file); err != nil {
+		file.Close()
+		return err
+	}
+	traceFile = file
+	return nil
+}
+
+// StopTrace finishes the execution trace started with StartTrace, if any.
+func StopTrace() {
+	if traceFile == nil {
+		return
+	}
+	trace.Stop()
+	traceFile.Close()
+	traceFile = nil
+}
+
+// Exit stops the execution trace and exits with code.
+func Exit(code int) {
+	StopTrace()
+	os.Exit(code)
+}
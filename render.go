@@ -0,0 +1,277 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// CellKind describes what is drawn in a cell of the board.
+type CellKind int
+
+const (
+	CellSnailBody CellKind = iota
+	CellSnailHead
+	CellFood
+	CellObstacle
+	// CellWrap and CellWrapFaded mark the border where the snail wrapped
+	// around the board.
+	CellWrap
+	CellWrapFaded
+	// CellFoodHint marks where food will appear next.
+	CellFoodHint
+	// CellGrid is an empty cell of the background grid.
+	CellGrid
+	// CellPowerUp is a power-up the snail can collect.
+	CellPowerUp
+	// CellPortal is an end of a portal.
+	CellPortal
+	// CellPoison is poison the snail should avoid.
+	CellPoison
+	// CellGolden is golden food worth a bonus.
+	CellGolden
+	// CellGhost, CellSlow, CellShrink and CellDouble are the timed
+	// power-ups.
+	CellGhost
+	CellSlow
+	CellShrink
+	CellDouble
+	// CellLadder is an end of a ladder to another layer.
+	CellLadder
+)
+
+// Renderer shows the game. The game loop only talks to its Renderer, so
+// frontends other than the terminal can be plugged in by implementing it.
+type Renderer interface {
+	// DrawBoard draws a frame of the running game.
+	DrawBoard(game *Game)
+	// DrawPause draws the paused game.
+	DrawPause(game *Game)
+	// DrawGameOver draws the end of the game on top of the last frame.
+	DrawGameOver(game *Game, won bool)
+	// Show makes everything drawn since the last call visible.
+	Show()
+	// Fini releases the resources of the renderer once the game is quit.
+	Fini()
+}
+
+// DeathAnimator is implemented by Renderers that animate the death of the
+// snail. PlayDeathAnimation returns false if ctx is done before the animation
+// finished.
+type DeathAnimator interface {
+	PlayDeathAnimation(ctx context.Context, game *Game) bool
+}
+
+// CanvasRenderer is the Renderer that draws the game cell by cell onto a
+// Canvas, which is how the game is shown in the terminal.
+type CanvasRenderer struct {
+	Canvas Canvas
+	// Screen, if set, is the screen behind Canvas and finalized by Fini.
+	Screen tcell.Screen
+}
+
+// Show presents the canvas.
+func (renderer *CanvasRenderer) Show() {
+	renderer.Canvas.Present()
+}
+
+// Fini finalizes the screen, if any.
+func (renderer *CanvasRenderer) Fini() {
+	if renderer.Screen != nil {
+		renderer.Screen.Fini()
+	}
+}
+
+// Canvas receives the draw calls of the game. Cells are addressed in grid
+// coordinates, the border runs along the columns -1 and XDim and the rows -1
+// and YDim. Text is addressed in screen columns and rows relative to the
+// top-left corner of the border.
+type Canvas interface {
+	DrawCell(x, y int, kind CellKind, style tcell.Style)
+	// DrawRunes draws a cell with the two given runes instead of the ones
+	// of a CellKind.
+	DrawRunes(x, y int, left, right rune, style tcell.Style)
+	DrawBorder(w, h int, style tcell.Style)
+	DrawText(col, row int, text string, style tcell.Style)
+	Clear()
+	Present()
+}
+
+// RuneMode decides whether cells are told apart by runes instead of colors.
+type RuneMode int
+
+const (
+	// RunesAuto uses runes on terminals with less than MinColors colors.
+	RunesAuto RuneMode = iota
+	// RunesOn always uses runes.
+	RunesOn
+	// RunesOff always uses colors.
+	RunesOff
+)
+
+// MinColors is the number of colors a terminal needs to tell the cells apart
+// by their colors.
+const MinColors = 8
+
+var runeModeNames = map[string]RuneMode{
+	"auto": RunesAuto,
+	"on":   RunesOn,
+	"off":  RunesOff,
+}
+
+// ParseRuneMode returns the RuneMode for the given name.
+func ParseRuneMode(name string) (RuneMode, error) {
+	if mode, ok := runeModeNames[name]; ok {
+		return mode, nil
+	}
+	return RunesAuto, fmt.Errorf("unknown rune mode %q", name)
+}
+
+// Enabled reports whether runes are used on a terminal with the given number
+// of colors.
+func (mode RuneMode) Enabled(colors int) bool {
+	if mode == RunesAuto {
+		return colors < MinColors
+	}
+	return mode == RunesOn
+}
+
+// TcellCanvas draws onto a tcell screen. Every grid cell is two screen
+// columns wide so the cells look square.
+type TcellCanvas struct {
+	Screen tcell.Screen
+	// Origin is the screen position of the top-left corner of the border.
+	Origin engine.Pos
+	// Runes, if set, are the two characters each kind of cell is drawn with
+	// in the default style instead of a colored block.
+	Runes map[CellKind]string
+}
+
+// NewTcellCanvas returns a TcellCanvas for screen that draws cells with
+// the runes of the TextCanvas if mode is enabled for the screen.
+func NewTcellCanvas(screen tcell.Screen, origin engine.Pos, mode RuneMode) *TcellCanvas {
+	canvas := &TcellCanvas{Screen: screen, Origin: origin}
+	if mode.Enabled(screen.Colors()) {
+		canvas.Runes = textRunes
+	}
+	return canvas
+}
+
+func (canvas *TcellCanvas) setContent(col, row int, r rune, style tcell.Style) {
+	canvas.Screen.SetContent(canvas.Origin.X+col, canvas.Origin.Y+row, r, nil, style)
+}
+
+func (canvas *TcellCanvas) DrawCell(x, y int, kind CellKind, style tcell.Style) {
+	left, right := tcell.RuneBlock, tcell.RuneBlock
+	if kind == CellWrapFaded || kind == CellFoodHint {
+		left, right = tcell.RuneCkBoard, tcell.RuneCkBoard
+	} else if kind == CellGrid {
+		left, right = ' ', tcell.RuneBullet
+	}
+	if canvas.Runes != nil {
+		runes := []rune(canvas.Runes[kind])
+		left, right = runes[0], runes[1]
+	}
+	canvas.DrawRunes(x, y, left, right, style)
+}
+
+func (canvas *TcellCanvas) DrawRunes(x, y int, left, right rune, style tcell.Style) {
+	if canvas.Runes != nil {
+		style = tcell.StyleDefault
+	}
+	if x >= 0 {
+		// the left border is only a single column wide
+		canvas.setContent(x*2+1, y+1, left, style)
+	}
+	canvas.setContent(x*2+2, y+1, right, style)
+}
+
+func (canvas *TcellCanvas) DrawBorder(w, h int, style tcell.Style) {
+	for c := 0; c < w+2; c++ {
+		var ru = tcell.RuneHLine
+		var rl = tcell.RuneHLine
+		var double = true
+		if c == 0 {
+			double = false
+			ru = tcell.RuneULCorner
+			rl = tcell.RuneLLCorner
+		} else if c == w+1 {
+			double = false
+			ru = tcell.RuneURCorner
+			rl = tcell.RuneLRCorner
+		}
+		canvas.setContent(c*2, 0, ru, style)
+		canvas.setContent(c*2, h+1, rl, style)
+		if double {
+			canvas.setContent(c*2+1, 0, ru, style)
+			canvas.setContent(c*2+1, h+1, rl, style)
+		}
+	}
+	canvas.setContent(1, h+1, tcell.RuneHLine, style)
+
+	for r := 1; r < h+1; r++ {
+		canvas.setContent(0, r, tcell.RuneVLine, style)
+		canvas.setContent(w*2+2, r, tcell.RuneVLine, style)
+	}
+}
+
+func (canvas *TcellCanvas) DrawText(col, row int, text string, style tcell.Style) {
+	for _, r := range text {
+		canvas.setContent(col, row, r, style)
+		col++
+	}
+}
+
+func (canvas *TcellCanvas) Clear() {
+	canvas.Screen.Clear()
+}
+
+func (canvas *TcellCanvas) Present() {
+	canvas.Screen.Show()
+}
+
+// DimCanvas draws through the wrapped Canvas using the dimmed variant of
+// every style.
+type DimCanvas struct {
+	Canvas
+}
+
+func (canvas DimCanvas) DrawCell(x, y int, kind CellKind, style tcell.Style) {
+	canvas.Canvas.DrawCell(x, y, kind, DimStyle(style))
+}
+
+func (canvas DimCanvas) DrawRunes(x, y int, left, right rune, style tcell.Style) {
+	canvas.Canvas.DrawRunes(x, y, left, right, DimStyle(style))
+}
+
+func (canvas DimCanvas) DrawBorder(w, h int, style tcell.Style) {
+	canvas.Canvas.DrawBorder(w, h, DimStyle(style))
+}
+
+func (canvas DimCanvas) DrawText(col, row int, text string, style tcell.Style) {
+	canvas.Canvas.DrawText(col, row, text, DimStyle(style))
+}
@@ -0,0 +1,555 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// recordingCanvas records the draw calls made on it, one string per call.
+type recordingCanvas struct {
+	calls []string
+}
+
+func (canvas *recordingCanvas) record(format string, args ...any) {
+	canvas.calls = append(canvas.calls, fmt.Sprintf(format, args...))
+}
+
+func (canvas *recordingCanvas) DrawCell(x, y int, kind CellKind, style tcell.Style) {
+	canvas.record("cell %d,%d %s", x, y, textRunes[kind])
+}
+
+func (canvas *recordingCanvas) DrawRunes(x, y int, left, right rune, style tcell.Style) {
+	canvas.record("runes %d,%d %c%c", x, y, left, right)
+}
+
+func (canvas *recordingCanvas) DrawBorder(w, h int, style tcell.Style) {
+	canvas.record("border %dx%d", w, h)
+}
+
+func (canvas *recordingCanvas) DrawText(col, row int, text string, style tcell.Style) {
+	canvas.record("text %d,%d %s", col, row, text)
+}
+
+func (canvas *recordingCanvas) Clear()   { canvas.record("clear") }
+func (canvas *recordingCanvas) Present() { canvas.record("present") }
+
+// newTextGame sets up a game configured by options without a screen, like
+// DryRun does, so its board can be drawn with RenderText.
+func newTextGame(t *testing.T, options ...Option) *Game {
+	t.Helper()
+	game := NewGame(options...)
+	game.SetDefaults()
+	if err := game.ResetState(); err != nil {
+		t.Fatal(err)
+	}
+	return game
+}
+
+func TestWrapAnimation(t *testing.T) {
+	tests := []struct {
+		frames int
+		want   []string
+	}{
+		{0, []string{"", ""}},
+		// the exit and the entry marker of every tick
+		{1, []string{"::", ""}},
+		{2, []string{"::", "..", ""}},
+	}
+	for _, test := range tests {
+		game := newTextGame(t, WithDimensions(10), WithSeed(3), WithTheme(Theme{WrapAnimFrames: test.frames}))
+		for ticks := 0; ; ticks++ {
+			if ticks > 20 || !game.Step() {
+				t.Fatalf("%d frames: the snail did not wrap", test.frames)
+			}
+			if _, _, ok := game.Wrapped(); ok {
+				break
+			}
+		}
+		exit, entry, _ := game.Wrapped()
+		dir := game.Snail().Direction
+		for frame, want := range test.want {
+			// drawing the board again does not advance the animation
+			for draw := 0; draw < 2; draw++ {
+				text := game.RenderText()
+				got := markerAt(text, engine.Pos{X: exit.X + dir.X, Y: exit.Y + dir.Y}) +
+					markerAt(text, engine.Pos{X: entry.X - dir.X, Y: entry.Y - dir.Y})
+				if got != want {
+					t.Errorf("%d frames: frame %d draw %d shows %q at the edges, want %q", test.frames, frame, draw, got, want)
+				}
+			}
+			game.Step()
+		}
+	}
+}
+
+func TestGrowthIndicator(t *testing.T) {
+	tests := []struct {
+		growth int
+		want   []string
+	}{
+		// the first segment grows on the tick the food is eaten
+		{1, []string{""}},
+		{3, []string{"Growth: +2", "Growth: +1", ""}},
+		{5, []string{"Growth: +4", "Growth: +3", "Growth: +2", "Growth: +1", ""}},
+	}
+	for _, test := range tests {
+		// the head starts at 7, 5 heading east, the food is right in front of it
+		game := newTextGame(t, WithConfig(engine.Config{Growth: test.growth, FoodScript: []engine.Pos{{X: 8, Y: 5}}}),
+			WithDimensions(10), WithSeed(3))
+		length := game.Length()
+		for game.FoodsEaten() == 0 {
+			if game.Tick() > 5 || !game.Step() {
+				t.Fatalf("growth %d: the snail did not eat", test.growth)
+			}
+		}
+		for tick, want := range test.want {
+			status := statusLine(game.RenderText())
+			if got := growthIndicator(status); got != want {
+				t.Errorf("growth %d: tick %d shows %q in %q, want %q", test.growth, tick, got, status, want)
+			}
+			if want == "" {
+				break
+			}
+			if !game.Step() {
+				t.Fatalf("growth %d: the snail died", test.growth)
+			}
+		}
+		if grown := game.Length() - length; grown != test.growth {
+			t.Errorf("growth %d: grew by %d segments, want %d", test.growth, grown, test.growth)
+		}
+	}
+}
+
+func TestObjectiveStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		budget   int
+		statuses []string
+		gameOver string
+	}{
+		{"won", 10, []string{"Food: 0/2 Moves: 10", "Food: 0/2 Moves: 9", "Food: 1/2 Moves: 8"}, "Game Over, you have WON!"},
+		{"out of moves", 1, []string{"Food: 0/2 Moves: 1", "Food: 0/2 Moves: 0"}, "Game Over, out of moves!"},
+	}
+	for _, test := range tests {
+		// the head starts at 12, 5 heading east, the food is right in front of
+		// it, the board is wide enough for the game over texts
+		config := engine.Config{ObjectiveFoods: 2, MoveBudget: test.budget, FoodScript: []engine.Pos{{X: 13, Y: 5}, {X: 14, Y: 5}}}
+		game := newTextGame(t, WithConfig(config), WithSize(20, 10), WithSeed(3))
+		for tick, want := range test.statuses {
+			if text := game.RenderText(); !strings.Contains(bottomLine(text), want) {
+				t.Errorf("%s: tick %d shows %q, want %q", test.name, tick, bottomLine(text), want)
+			}
+			if tick < len(test.statuses)-1 && !game.Step() {
+				t.Fatalf("%s: the game ended on tick %d", test.name, tick)
+			}
+		}
+		for game.Step() {
+		}
+		won := game.EndGame()
+		text := NewTextCanvas()
+		renderer := CanvasRenderer{Canvas: text}
+		renderer.DrawGameOver(game, won)
+		if !strings.Contains(text.String(), test.gameOver) {
+			t.Errorf("%s: game over screen shows\n%s\nwant %q", test.name, text, test.gameOver)
+		}
+	}
+}
+
+func TestVersusGameOver(t *testing.T) {
+	// the snail heads east into the wall while the computer snail survives
+	config := engine.Config{Versus: true, Bounds: engine.BoundsWalls}
+	game := newTextGame(t, WithConfig(config), WithSize(40, 10), WithSeed(3))
+	for game.Step() {
+	}
+	won := game.EndGame()
+	if outcome := game.RivalOutcome(); outcome != engine.OutcomeFirstDies {
+		t.Fatalf("the game ended with outcome %d, want the snail to die", outcome)
+	}
+	text := NewTextCanvas()
+	renderer := CanvasRenderer{Canvas: text}
+	renderer.DrawGameOver(game, won)
+	if want := "Game Over, the computer snail wins!"; !strings.Contains(text.String(), want) {
+		t.Errorf("game over screen shows\n%s\nwant %q", text, want)
+	}
+}
+
+// bottomLine returns the status drawn on the bottom border.
+func bottomLine(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	return lines[len(lines)-1]
+}
+
+// statusLine returns the status drawn on the top border.
+func statusLine(text string) string {
+	return strings.SplitN(text, "\n", 2)[0]
+}
+
+// growthIndicator returns the pending growth shown in status, if any.
+func growthIndicator(status string) string {
+	index := strings.Index(status, "Growth: ")
+	if index < 0 {
+		return ""
+	}
+	fields := strings.Fields(status[index:])
+	return fields[0] + " " + strings.TrimRight(fields[1], "-+")
+}
+
+// frameCanvas is a TextCanvas that passes the text of every presented frame
+// on to frames.
+type frameCanvas struct {
+	*TextCanvas
+	frames chan string
+}
+
+func (canvas frameCanvas) Present() {
+	canvas.frames <- canvas.String()
+}
+
+// dieOnWall plays the game, which must have lethal walls, until the snail
+// dies.
+func dieOnWall(t *testing.T, game *Game) {
+	t.Helper()
+	for ticks := 0; game.Step(); ticks++ {
+		if ticks > 20 {
+			t.Fatal("the snail did not run into a wall")
+		}
+	}
+	game.EndGame()
+	if !game.Died() {
+		t.Fatal("the snail did not die")
+	}
+}
+
+func TestDeathAnimation(t *testing.T) {
+	tests := []struct {
+		frames int
+		cancel bool
+	}{
+		{1, false},
+		{3, false},
+		{4, true},
+	}
+	for _, test := range tests {
+		clock := newFakeClock()
+		game := newTextGame(t, WithDimensions(10), WithSeed(3), WithBounds(engine.BoundsWalls),
+			WithDelay(harnessDelay), WithTheme(Theme{DeathAnimFrames: test.frames}), WithClock(clock))
+		dieOnWall(t, game)
+		length := game.Length()
+		ctx, cancel := context.WithCancel(context.Background())
+		canvas := frameCanvas{NewTextCanvas(), make(chan string)}
+		renderer := CanvasRenderer{Canvas: canvas}
+		result := make(chan bool)
+		go func() {
+			result <- renderer.PlayDeathAnimation(ctx, game)
+		}()
+		var frames []string
+		finished, ok := false, false
+		for !finished {
+			select {
+			case frame := <-canvas.frames:
+				frames = append(frames, frame)
+				if test.cancel {
+					cancel()
+				}
+			case ok = <-result:
+				finished = true
+			case <-time.After(time.Millisecond):
+				// the animation waits for the delay of the frame
+				clock.Advance(harnessDelay)
+			}
+		}
+		cancel()
+		want := test.frames
+		if test.cancel {
+			want = 1
+		}
+		if len(frames) != want || ok == test.cancel {
+			t.Fatalf("%d frames: drew %d frames and returned %v, want %d frames and %v",
+				test.frames, len(frames), ok, want, !test.cancel)
+		}
+		// the snail dissolves from the tail, on the last frame it is gone
+		for index, frame := range frames {
+			segments := strings.Count(frame, "oo") + strings.Count(frame, "@@")
+			if remaining := length - length*(index+1)/test.frames; segments != remaining {
+				t.Errorf("%d frames: frame %d shows %d segments, want %d", test.frames, index, segments, remaining)
+			}
+		}
+	}
+}
+
+// markerAt returns the rune drawn at the left of the cell at pos, which may
+// be next to the board, or "" if nothing but the border is drawn there.
+func markerAt(text string, pos engine.Pos) string {
+	lines := strings.Split(text, "\n")
+	row, col := pos.Y+1, pos.X*2+1
+	if col < 0 {
+		// only the right rune of a cell left of the board is on the canvas
+		col = 0
+	}
+	if row < 0 || row >= len(lines) || col >= len([]rune(lines[row])) {
+		return ""
+	}
+	r := []rune(lines[row])[col]
+	if strings.ContainsRune(" |-+", r) {
+		return ""
+	}
+	return string(r)
+}
+
+func TestRecordingCanvas(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		want    []string
+	}{
+		{"board", nil, []string{
+			"clear",
+			"border 10x10",
+			"cell 1,1 **",
+			"cell 5,5 oo",
+			"cell 6,5 oo",
+			"cell 7,5 @@",
+			"text 1,0 Score: 0",
+			"text 1,11 ",
+			"present",
+		}},
+		{"obstacles", []Option{WithConfig(engine.Config{ObstacleCount: 1})}, []string{
+			"clear",
+			"border 10x10",
+			"cell 1,1 ##",
+			"cell 3,9 **",
+			"cell 5,5 oo",
+			"cell 6,5 oo",
+			"cell 7,5 @@",
+			"text 1,0 Score: 0",
+			"text 1,11 ",
+			"present",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := newTextGame(t, append(test.options, WithDimensions(10), WithSeed(3))...)
+			canvas := &recordingCanvas{}
+			renderer := &CanvasRenderer{Canvas: canvas}
+			renderer.DrawBoard(game)
+			renderer.Show()
+			if !reflect.DeepEqual(canvas.calls, test.want) {
+				t.Errorf("recorded\n%s\nwant\n%s", strings.Join(canvas.calls, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+// colorScreen is a simulation screen that reports colors colors.
+type colorScreen struct {
+	tcell.SimulationScreen
+	colors int
+}
+
+func (screen colorScreen) Colors() int {
+	return screen.colors
+}
+
+func TestRuneFallback(t *testing.T) {
+	tests := []struct {
+		mode   RuneMode
+		colors int
+		want   string
+	}{
+		{RunesAuto, 0, "**"},
+		{RunesAuto, 2, "**"},
+		{RunesAuto, MinColors, "██"},
+		{RunesAuto, 256, "██"},
+		{RunesOn, 256, "**"},
+		{RunesOff, 0, "██"},
+		{RunesOff, 256, "██"},
+		{RunesOn, 0, "**"},
+	}
+	for _, test := range tests {
+		screen := colorScreen{SimulationScreen: tcell.NewSimulationScreen(""), colors: test.colors}
+		if err := screen.Init(); err != nil {
+			t.Fatal(err)
+		}
+		screen.SetSize(10, 5)
+		canvas := NewTcellCanvas(screen, engine.Pos{}, test.mode)
+		canvas.DrawCell(1, 1, CellFood, tcell.StyleDefault)
+		left, _, _, _ := screen.GetContent(3, 2)
+		right, _, _, _ := screen.GetContent(4, 2)
+		if got := string([]rune{left, right}); got != test.want {
+			t.Errorf("mode %d with %d colors: food drawn as %q, want %q", test.mode, test.colors, got, test.want)
+		}
+		screen.Fini()
+	}
+}
+
+func TestTrainerMarkers(t *testing.T) {
+	script := []engine.Pos{{X: 2, Y: 2}, {X: 4, Y: 7}, {X: 8, Y: 1}}
+	tests := []struct {
+		trainer int
+		want    []string
+	}{
+		{0, []string{"**", "  ", "  "}},
+		{1, []string{"**", "++", "  "}},
+		{2, []string{"**", "++", "++"}},
+		// the script runs out of positions
+		{5, []string{"**", "++", "++"}},
+	}
+	for _, test := range tests {
+		game := newTextGame(t, WithConfig(engine.Config{XDim: 10, YDim: 10, Seed: 3, FoodScript: script}), WithTrainer(test.trainer))
+		text := game.RenderText()
+		lines := strings.Split(text, "\n")
+		for index, pos := range script {
+			line := []rune(lines[pos.Y+1])
+			if got := string(line[pos.X*2+1 : pos.X*2+3]); got != test.want[index] {
+				t.Errorf("trainer %d: %q drawn at %v, want %q\n%s", test.trainer, got, pos, test.want[index], text)
+			}
+		}
+	}
+}
+
+func TestPerfectFlash(t *testing.T) {
+	tests := []struct {
+		name string
+		// turns are the directions taken on the first ticks
+		turns        []engine.Velocity
+		perfects     int
+		wantFlashing int
+	}{
+		{"shortest path", []engine.Velocity{engine.NorthDir, engine.EastDir}, 1, 2},
+		{"detour", []engine.Velocity{engine.NorthDir, engine.NorthDir, engine.EastDir, engine.EastDir, engine.SouthDir}, 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			game := newTextGame(t, WithConfig(engine.Config{XDim: 10, YDim: 10, Seed: 3,
+				FoodScript: []engine.Pos{{X: 9, Y: 4}, {X: 0, Y: 0}}}), WithTheme(Theme{Runes: RunesOn, PerfectFlash: 2}))
+			flashing := 0
+			for tick := 0; tick < 10; tick++ {
+				if tick < len(test.turns) {
+					if err := game.ChangeDirection(test.turns[tick]); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if !game.Step() {
+					t.Fatal("the game ended")
+				}
+				if strings.Contains(statusLine(game.RenderText()), "PERFECT!") {
+					flashing++
+				}
+			}
+			if game.FoodsEaten() != 1 || game.Perfects() != test.perfects {
+				t.Errorf("%d foods eaten with %d perfects, want 1 with %d", game.FoodsEaten(), game.Perfects(), test.perfects)
+			}
+			if flashing != test.wantFlashing {
+				t.Errorf("PERFECT! shown on %d boards, want %d", flashing, test.wantFlashing)
+			}
+		})
+	}
+}
+
+func TestGridlines(t *testing.T) {
+	for _, gridlines := range []bool{false, true} {
+		game := newTextGame(t, WithDimensions(10), WithSeed(3), WithTheme(Theme{Runes: RunesOn, Gridlines: gridlines}))
+		lines := strings.Split(game.RenderText(), "\n")
+		occupied := map[engine.Pos]string{game.Food(): "**"}
+		for _, pos := range game.Snail().Body {
+			occupied[pos] = "oo"
+		}
+		occupied[game.Head()] = "@@"
+		for y := 0; y < 10; y++ {
+			line := []rune(lines[y+1])
+			for x := 0; x < 10; x++ {
+				want, ok := occupied[engine.Pos{X: x, Y: y}]
+				if !ok {
+					want = "  "
+					if gridlines {
+						want = " ."
+					}
+				}
+				if got := string(line[x*2+1 : x*2+3]); got != want {
+					t.Errorf("gridlines %t: cell %d,%d drawn as %q, want %q", gridlines, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestHarnessGridlinesToggle(t *testing.T) {
+	h := newHarness(t)
+	// 0,9 stays empty for the first ticks
+	if got := h.cell(0, 9); got != "  " {
+		t.Fatalf("empty cell drawn as %q without gridlines", got)
+	}
+	h.key(tcell.KeyRune, 'g')
+	h.waitFor("the gridlines", func(game *Game) bool { return game.Gridlines })
+	h.tick()
+	if got := h.cell(0, 9); got != " ." {
+		t.Errorf("empty cell drawn as %q with gridlines, want \" .\"", got)
+	}
+	var head engine.Pos
+	h.do(func(game *Game) { head = game.Head() })
+	if got := h.cell(head.X, head.Y); got != "@@" {
+		t.Errorf("head drawn as %q with gridlines", got)
+	}
+}
+
+func TestSmoothSnail(t *testing.T) {
+	tests := []struct {
+		name string
+		body []engine.Pos
+		want []string
+	}{
+		{"L", []engine.Pos{{X: 2, Y: 2}, {X: 3, Y: 2}, {X: 4, Y: 2}, {X: 4, Y: 3}, {X: 4, Y: 4}},
+			[]string{"runes 2,2 ╶─", "runes 3,2 ──", "runes 4,2 ┐ ", "runes 4,3 │ ", "cell 4,4 @@"}},
+		{"turning up", []engine.Pos{{X: 2, Y: 5}, {X: 2, Y: 4}, {X: 3, Y: 4}, {X: 3, Y: 3}},
+			[]string{"runes 2,5 ╵ ", "runes 2,4 ┌─", "runes 3,4 ┘ ", "cell 3,3 @@"}},
+		{"across the edge", []engine.Pos{{X: 8, Y: 1}, {X: 9, Y: 1}, {X: 0, Y: 1}, {X: 0, Y: 2}},
+			[]string{"runes 8,1 ╶─", "runes 9,1 ──", "runes 0,1 ┐ ", "cell 0,2 @@"}},
+	}
+	game := newTextGame(t, WithDimensions(10), WithSeed(3))
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			canvas := &recordingCanvas{}
+			renderer := &CanvasRenderer{Canvas: canvas}
+			renderer.DrawSmoothSnail(game, test.body, tcell.StyleDefault)
+			if !reflect.DeepEqual(canvas.calls, test.want) {
+				t.Errorf("draw calls:\n%s\nwant:\n%s", strings.Join(canvas.calls, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+	// the board draws the snail smooth only with SmoothSnake
+	for smooth, want := range map[bool]string{false: "|          oooo@@     |", true: "|          ----@@     |"} {
+		game := newTextGame(t, WithDimensions(10), WithSeed(3), WithTheme(Theme{Runes: RunesOn, SmoothSnake: smooth}))
+		if line := strings.Split(game.RenderText(), "\n")[6]; line != want {
+			t.Errorf("smooth %t: snail drawn as %q, want %q", smooth, line, want)
+		}
+	}
+}
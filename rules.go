@@ -0,0 +1,65 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"plugin"
+	"strings"
+
+	"github.com/q713/snail/engine"
+)
+
+// LoadRules returns the rules registered under name with
+// engine.RegisterRules or, if name ends in .so, the engine.RuleSet exported
+// as Rules by the Go plugin at that path.
+func LoadRules(name string) (engine.RuleSet, error) {
+	if !strings.HasSuffix(name, ".so") {
+		return engine.LookupRules(name)
+	}
+	mod, err := plugin.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	symbol, err := mod.Lookup("Rules")
+	if err != nil {
+		return nil, err
+	}
+	switch rules := symbol.(type) {
+	case *engine.RuleSet:
+		return *rules, nil
+	case engine.RuleSet:
+		return rules, nil
+	}
+	return nil, fmt.Errorf("Rules of plugin %s is a %T instead of an engine.RuleSet", name, symbol)
+}
+
+// reverseShare is the share of the cells in percent covered by the snail at
+// the start of a game with the reverse rules.
+const reverseShare = 70
+
+// ReverseStartLength returns the length the snail starts with on a width x
+// height grid under the reverse rules. The last row is always left free.
+func ReverseStartLength(width, height int) int {
+	return minInt(width*height*reverseShare/100, width*(height-1))
+}
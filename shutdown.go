@@ -0,0 +1,90 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// NotifyShutdown returns a copy of ctx that is done once the process receives
+// SIGINT, SIGTERM or SIGHUP, on which games quit like on the quit command.
+// The signals are handled as usual again once stop is called.
+func NotifyShutdown(ctx context.Context) (shutdown context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+}
+
+// readCommands passes the commands of Input on to the returned channel until
+// Input quits or done is closed, so they can be waited for together with
+// other events.
+func (game *Game) readCommands(done <-chan struct{}) <-chan Command {
+	commands := make(chan Command)
+	go func() {
+		for {
+			command := game.Input.NextCommand()
+			select {
+			case commands <- command:
+			case <-done:
+				return
+			}
+			if command == CommandQuit {
+				return
+			}
+		}
+	}()
+	return commands
+}
+
+// LoadState reads the unfinished game written by SaveState from path, so it
+// is continued by the next game loop. The script and the logger of the game
+// are kept, as they are not stored.
+func (game *Game) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	script, logger := game.config.Script, game.config.Logger
+	if err := json.Unmarshal(data, game); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	game.config.Script, game.config.Logger = script, logger
+	game.Script, game.Logger = script, logger
+	return nil
+}
+
+// SaveState writes the state of an unfinished game as json to SavePath, if
+// set, see Game.MarshalJSON.
+func (game *Game) SaveState() error {
+	if game.SavePath == "" || game.Game == nil || game.Over() {
+		return nil
+	}
+	data, err := json.Marshal(game)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(game.SavePath, data, 0644)
+}
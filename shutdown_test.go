@@ -0,0 +1,136 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+func TestSaveStateResume(t *testing.T) {
+	for _, name := range []string{"classic", "zen", "reverse"} {
+		t.Run(name, func(t *testing.T) {
+			rules, err := LoadRules(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			config := engine.Config{Rules: rules}
+			if name == "reverse" {
+				config.StartLength = ReverseStartLength(10, 10)
+			}
+			path := filepath.Join(t.TempDir(), "state.json")
+			game := NewGame(WithConfig(config), WithDimensions(10), WithSeed(7),
+				WithDelay(120*time.Millisecond), WithOutputs(Outputs{SavePath: path}))
+			if err := game.ResetState(); err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < 3; i++ {
+				if !game.Step() {
+					t.Fatalf("the game ended on tick %d", game.Tick())
+				}
+			}
+			if err := game.SaveState(); err != nil {
+				t.Fatal(err)
+			}
+
+			resumed := NewGame(WithDimensions(20), WithDelay(DefaultDelay))
+			if err := resumed.LoadState(path); err != nil {
+				t.Fatal(err)
+			}
+			if err := resumed.ResetState(); err != nil {
+				t.Fatal(err)
+			}
+			if resumed.Tick() != game.Tick() || resumed.Score() != game.Score() || resumed.XDim != 10 ||
+				!reflect.DeepEqual(resumed.Snail(), game.Snail()) {
+				t.Errorf("resumed tick %d score %d width %d, want tick %d score %d width 10",
+					resumed.Tick(), resumed.Score(), resumed.XDim, game.Tick(), game.Score())
+			}
+			if !reflect.DeepEqual(resumed.Rules, rules) {
+				t.Errorf("resumed rules %#v, want %#v", resumed.Rules, rules)
+			}
+			if resumed.GameDelayMilliSeconds != 120*time.Millisecond {
+				t.Errorf("resumed delay %s, want 120ms", resumed.GameDelayMilliSeconds)
+			}
+
+			// the games after the resumed one are new games with its config
+			if err := resumed.ResetState(); err != nil {
+				t.Fatal(err)
+			}
+			if resumed.Tick() != 0 || !reflect.DeepEqual(resumed.Rules, rules) {
+				t.Errorf("next game has tick %d and rules %#v, want tick 0 and rules %#v", resumed.Tick(), resumed.Rules, rules)
+			}
+		})
+	}
+}
+
+func TestSaveStateSkipsFinishedGames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	game := NewGame(WithDimensions(10), WithSeed(7), WithOutputs(Outputs{SavePath: path}))
+	if err := game.ResetState(); err != nil {
+		t.Fatal(err)
+	}
+	game.EndGame()
+	if err := game.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+	if err := game.LoadState(path); err == nil {
+		t.Error("the state of a finished game was saved")
+	}
+}
+
+func TestStartResumesGame(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	game := NewGame(WithDimensions(10), WithSeed(7), WithOutputs(Outputs{SavePath: path}))
+	if err := game.ResetState(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if !game.Step() {
+			t.Fatalf("the game ended on tick %d", game.Tick())
+		}
+	}
+	if err := game.SaveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first loop plays the resumed game instead of a new one
+	resumed := NewGame(WithScreen(tcell.NewSimulationScreen("")), WithClock(newFakeClock()))
+	if err := resumed.LoadState(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := resumed.Start(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resumed.Loop(ctx)
+	if resumed.Tick() < game.Tick() {
+		t.Errorf("the first loop plays tick %d, want the resumed game at tick %d or later", resumed.Tick(), game.Tick())
+	}
+}
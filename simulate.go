@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// simulateMaxTicksPerCell limits the length of a simulated game to this many
+// ticks per cell of the grid, so an autopilot that never dies cannot run
+// forever.
+const simulateMaxTicksPerCell = 100
+
+// Simulate plays count games configured by options with the Autopilot,
+// which must be set, as fast as possible, without a screen and without
+// waiting, and writes the result of every game followed by aggregate stats
+// to w. Game i uses Seed+i as its seed if Seed is set.
+func (game *Game) Simulate(w io.Writer, count int, options ...Option) error {
+	game.Apply(options...)
+	game.SetDefaults()
+	seed := game.config.Seed
+	won, total, totalMoves := 0, 0, 0
+	minScore, maxScore := 0, 0
+	for index := 0; index < count; index++ {
+		if seed != 0 {
+			game.config.Seed = seed + int64(index)
+		}
+		if err := game.ResetState(); err != nil {
+			return err
+		}
+		maxTicks := game.XDim * game.YDim * simulateMaxTicksPerCell
+		for game.Tick() < maxTicks && game.Step() {
+		}
+		if game.err != nil {
+			return game.err
+		}
+		if game.Tick() < maxTicks && game.EndGame() {
+			won += 1
+		}
+		score := game.Score()
+		if index == 0 || score < minScore {
+			minScore = score
+		}
+		if index == 0 || score > maxScore {
+			maxScore = score
+		}
+		total += score
+		totalMoves += game.Tick()
+		_, err := fmt.Fprintf(w, "game %d %s\n", index+1, game.Summary())
+		if err != nil {
+			return err
+		}
+	}
+	game.config.Seed = seed
+	if count < 1 {
+		return nil
+	}
+	_, err := fmt.Fprintf(w, "games %d won %d score avg %.1f min %d max %d moves avg %.1f\n",
+		count, won, float64(total)/float64(count), minScore, maxScore, float64(totalMoves)/float64(count))
+	return err
+}
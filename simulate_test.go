@@ -0,0 +1,70 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/q713/snail/engine"
+)
+
+func TestSimulateDeterministic(t *testing.T) {
+	tests := []struct {
+		name      string
+		seed      int64
+		errorRate float64
+	}{
+		{"perfect autopilot", 5, 0},
+		{"sloppy autopilot", 11, 0.2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			simulate := func(seed int64, count int) []string {
+				t.Helper()
+				var out bytes.Buffer
+				config := engine.Config{XDim: 10, YDim: 10, Seed: seed, Growth: 1, Autopilot: &engine.Autopilot{ErrorRate: test.errorRate}}
+				if err := NewGame().Simulate(&out, count, WithConfig(config)); err != nil {
+					t.Fatal(err)
+				}
+				return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			}
+			first, second := simulate(test.seed, 3), simulate(test.seed, 3)
+			if strings.Join(first, "\n") != strings.Join(second, "\n") {
+				t.Fatalf("two simulations differ:\n%s\n\n%s", strings.Join(first, "\n"), strings.Join(second, "\n"))
+			}
+			if len(first) != 4 || !strings.HasPrefix(first[3], "games 3 ") {
+				t.Fatalf("simulation output:\n%s\nwant three games and the stats", strings.Join(first, "\n"))
+			}
+			// game i plays like a single game with seed Seed+i
+			for index := 0; index < 3; index++ {
+				single := simulate(test.seed+int64(index), 1)
+				want := strings.TrimPrefix(single[0], "game 1 ")
+				if got := first[index][strings.Index(first[index], "seed"):]; got != want {
+					t.Errorf("game %d: %q, want %q", index+1, got, want)
+				}
+			}
+		})
+	}
+}
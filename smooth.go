@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// Bits of the directions a segment of a smooth snail connects to.
+const (
+	linkNorth = 1 << iota
+	linkEast
+	linkSouth
+	linkWest
+)
+
+var linkBits = map[engine.Velocity]int{
+	engine.NorthDir: linkNorth,
+	engine.EastDir:  linkEast,
+	engine.SouthDir: linkSouth,
+	engine.WestDir:  linkWest,
+}
+
+// linkRunes are the runes drawn in the left column of a segment for the
+// directions it connects to. Tail caps connect to a single direction.
+var linkRunes = map[int]rune{
+	linkEast | linkWest:   tcell.RuneHLine,
+	linkNorth | linkSouth: tcell.RuneVLine,
+	linkNorth | linkEast:  tcell.RuneLLCorner,
+	linkNorth | linkWest:  tcell.RuneLRCorner,
+	linkSouth | linkEast:  tcell.RuneULCorner,
+	linkSouth | linkWest:  tcell.RuneURCorner,
+	linkNorth:             '╵',
+	linkEast:              '╶',
+	linkSouth:             '╷',
+	linkWest:              '╴',
+}
+
+// link returns the bit of the direction from a to the adjacent cell b, taking
+// wrapping around the edges into account.
+func (game *Game) link(a, b engine.Pos) int {
+	for _, dir := range engine.Directions {
+		if engine.Neighbor(a, dir, game.XDim, game.YDim) == b {
+			return linkBits[dir]
+		}
+	}
+	return 0
+}
+
+// DrawSmoothSnail draws body as a connected tube. Every segment is drawn with
+// the connector rune for its neighboring segments in its left column and a
+// horizontal line in its right column if it connects to the east, so the
+// connectors of adjacent cells meet. The head is drawn as a block.
+func (renderer *CanvasRenderer) DrawSmoothSnail(game *Game, body []engine.Pos, bodyStyle tcell.Style) {
+	// the runes are drawn in the color the blocks are filled with
+	_, color, _ := bodyStyle.Decompose()
+	style := backStyle.Foreground(color)
+	for index, pos := range body[:len(body)-1] {
+		if !game.OnLayer(pos) {
+			continue
+		}
+		links := game.link(pos, body[index+1])
+		if index > 0 {
+			links |= game.link(pos, body[index-1])
+		}
+		right := ' '
+		if links&linkEast != 0 {
+			right = tcell.RuneHLine
+		}
+		renderer.Canvas.DrawRunes(pos.X, pos.Y, linkRunes[links], right, style)
+	}
+	if head := body[len(body)-1]; game.OnLayer(head) {
+		renderer.Canvas.DrawCell(head.X, head.Y, CellSnailHead, game.headStyle())
+	}
+}
@@ -0,0 +1,210 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+// Spectators sends the state of a game to every client that watches it with
+// snail watch. Watchers only receive frames, they cannot steer any snail.
+// They speak the protocol of snail host, so they can join at any time and
+// catch up with the next frame.
+type Spectators struct {
+	listener net.Listener
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	last    []byte
+	round   int
+}
+
+// ListenSpectators starts to accept watchers on addr in the background.
+func ListenSpectators(addr string) (*Spectators, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	spectators := &Spectators{
+		listener: listener,
+		clients:  make(map[chan []byte]struct{}),
+	}
+	go spectators.accept()
+	return spectators, nil
+}
+
+func (spectators *Spectators) accept() {
+	for {
+		conn, err := spectators.listener.Accept()
+		if err != nil {
+			return
+		}
+		go spectators.serve(conn)
+	}
+}
+
+// serve writes the frames to the watcher on conn until it leaves. A watcher
+// that joins sees the last frame right away instead of waiting for the next
+// tick, e.g. while the game is paused.
+func (spectators *Spectators) serve(conn net.Conn) {
+	defer conn.Close()
+	data, err := json.Marshal(NetHello{Protocol: netProtocol, Version: Version})
+	if err != nil {
+		return
+	}
+	conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return
+	}
+	frames := make(chan []byte, netFrameBuffer)
+	spectators.mu.Lock()
+	if spectators.last != nil {
+		frames <- spectators.last
+	}
+	spectators.clients[frames] = struct{}{}
+	spectators.mu.Unlock()
+	defer func() {
+		spectators.mu.Lock()
+		delete(spectators.clients, frames)
+		spectators.mu.Unlock()
+	}()
+
+	// watchers send nothing, reading notices when they leave
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		io.Copy(io.Discard, conn)
+	}()
+	for {
+		select {
+		case data := <-frames:
+			conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+			if _, err := conn.Write(data); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
+
+// Attach sends a frame to the watchers after every tick and at the end of
+// every game.
+func (spectators *Spectators) Attach(hooks *Hooks) {
+	hooks.OnTick(spectators.Send)
+	hooks.OnGameOver(func(game *Game, won bool) {
+		spectators.Send(game)
+		spectators.mu.Lock()
+		spectators.round += 1
+		spectators.mu.Unlock()
+	})
+}
+
+// Send sends the current state of game to all watchers. It does not block,
+// the oldest queued frame is dropped for watchers that fall behind.
+func (spectators *Spectators) Send(game *Game) {
+	spectators.mu.Lock()
+	defer spectators.mu.Unlock()
+	data, err := json.Marshal(game.NetFrame(spectators.round))
+	if err != nil {
+		return
+	}
+	// the line is shared by all watchers, so none of them may append to it
+	data = append(data, '\n')
+	spectators.last = data
+	for client := range spectators.clients {
+		queueFrame(client, data)
+	}
+}
+
+// Close stops accepting watchers.
+func (spectators *Spectators) Close() error {
+	return spectators.listener.Close()
+}
+
+// WatchGame watches the game on addr, which is either shared with -spectate
+// or hosted with snail host, until Escape is pressed or the game ends.
+func WatchGame(addr string, mode RuneMode) error {
+	conn, scanner, err := dialHost(addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	screen, err := InitScreen()
+	if err != nil {
+		return err
+	}
+	defer screen.Fini()
+	canvas := NewTcellCanvas(screen, engine.Pos{}, mode)
+	go postFrames(scanner, screen)
+
+	var last NetFrame
+	for {
+		switch event := screen.PollEvent().(type) {
+		case nil:
+			return nil
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if command, ok := keyCommand(event); ok && command == CommandQuit {
+				return nil
+			}
+		case *tcell.EventInterrupt:
+			switch data := event.Data().(type) {
+			case NetFrame:
+				if data.newer(last) {
+					last = data
+					DrawWatchFrame(canvas, last)
+				}
+			case netClosed:
+				if data.err != nil && !errors.Is(data.err, net.ErrClosed) {
+					return fmt.Errorf("connection to the game lost: %w", data.err)
+				}
+				return fmt.Errorf("the game has ended")
+			}
+		}
+	}
+}
+
+// DrawWatchFrame draws frame as a watcher sees it: from the side of the
+// first snail.
+func DrawWatchFrame(canvas Canvas, frame NetFrame) {
+	status := fmt.Sprintf("Score: %d", frame.Score)
+	if len(frame.Rival) > 0 {
+		status += fmt.Sprintf(" P2: %d", frame.RivalScore)
+	}
+	if frame.Result == "" {
+		frame.Result = frame.Outcome
+	}
+	drawNetFrame(canvas, frame, frame.Snail, frame.Rival, status+" (watching)", "Waiting for the player... Quit? Esc")
+}
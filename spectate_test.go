@@ -0,0 +1,79 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+func TestSpectate(t *testing.T) {
+	spectators, err := ListenSpectators("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer spectators.Close()
+	addr := spectators.listener.Addr().String()
+	first, firstScanner, err := dialHost(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer first.Close()
+
+	h := newHarness(t, func(game *Game) { spectators.Attach(&game.Hooks) })
+	h.turn(tcell.KeyDown)
+	snail := func() []engine.Pos {
+		var body []engine.Pos
+		h.do(func(game *Game) { body = game.Snail().Body })
+		return body
+	}
+	for tick := 2; tick <= 3; tick++ {
+		h.tick()
+		if frame := readFrame(t, first, firstScanner, tick); !reflect.DeepEqual(frame.Snail, snail()) {
+			t.Errorf("tick %d: watcher sees %v, want %v", tick, frame.Snail, snail())
+		}
+	}
+
+	// a watcher that joins late sees the last frame right away
+	second, secondScanner, err := dialHost(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer second.Close()
+	if frame := readFrame(t, second, secondScanner, 0); frame.Tick != 3 {
+		t.Errorf("late watcher sees tick %d first, want 3", frame.Tick)
+	}
+	h.tick()
+	if frame := readFrame(t, first, firstScanner, 4); !reflect.DeepEqual(frame.Snail, snail()) {
+		t.Errorf("first watcher sees %v, want %v", frame.Snail, snail())
+	}
+	if frame := readFrame(t, second, secondScanner, 4); !reflect.DeepEqual(frame.Snail, snail()) {
+		t.Errorf("late watcher sees %v, want %v", frame.Snail, snail())
+	}
+	if err := h.quit(); err != nil {
+		t.Fatal(err)
+	}
+}
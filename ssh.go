@@ -0,0 +1,280 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/gdamore/tcell/v2/terminfo"
+	"github.com/gliderlabs/ssh"
+)
+
+// defaultSSHAddr is the address snail serve accepts ssh connections on if
+// none is given.
+const defaultSSHAddr = ":2222"
+
+// fallbackTerm is the terminal used for sessions whose terminal is unknown.
+const fallbackTerm = "xterm-256color"
+
+// arcadeSize is the number of best scores of an Arcade that are shown.
+const arcadeSize = 5
+
+// Arcade holds the best scores of all sessions of a server, a table per
+// HighScoreKey like the high scores of a single player. With a Path the
+// tables are kept in that high score file under the mode "arcade ...", so
+// they survive a restart of the server.
+type Arcade struct {
+	Path   string
+	mu     sync.Mutex
+	scores HighScores
+}
+
+// LoadArcade returns an Arcade keeping its scores in the high score file at
+// path, an empty path keeps them in memory only.
+func LoadArcade(path string) (*Arcade, error) {
+	scores, err := LoadHighScores(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Arcade{Path: path, scores: scores}, nil
+}
+
+// arcadeKey returns the key of the arcade table of the games of key.
+func arcadeKey(key HighScoreKey) HighScoreKey {
+	key.Mode = "arcade " + key.Mode
+	return key
+}
+
+// Add records the score of the last game of player in the table of its key
+// if it is among the best.
+func (arcade *Arcade) Add(player string, game *Game) error {
+	return arcade.add(HighScore{
+		HighScoreKey: arcadeKey(game.HighScoreKey()),
+		Player:       player,
+		Score:        game.Score(),
+		Date:         game.Clock.Now(),
+		Length:       game.Length(),
+	})
+}
+
+// add enters score into its table. The file is read again first, so the
+// tables other games wrote to it in the meantime are kept.
+func (arcade *Arcade) add(score HighScore) error {
+	arcade.mu.Lock()
+	defer arcade.mu.Unlock()
+	if arcade.Path != "" {
+		scores, err := LoadHighScores(arcade.Path)
+		if err != nil {
+			return err
+		}
+		arcade.scores = scores
+	}
+	scores, rank := arcade.scores.Add(score)
+	arcade.scores = scores
+	if rank < 0 || arcade.Path == "" {
+		return nil
+	}
+	return scores.WriteFile(arcade.Path)
+}
+
+// Best returns the best arcadeSize scores of the games of key, the highest
+// first.
+func (arcade *Arcade) Best(key HighScoreKey) HighScores {
+	arcade.mu.Lock()
+	defer arcade.mu.Unlock()
+	table := arcade.scores.Table(arcadeKey(key))
+	return table[:min(len(table), arcadeSize)]
+}
+
+// SSHServer runs an independent game for every ssh session, all of them
+// configured like the game it was created with, see sessionCopy. The scores
+// of all sessions are shared in an Arcade.
+type SSHServer struct {
+	template Game
+	options  []Option
+	arcade   *Arcade
+}
+
+// NewSSHServer returns an SSHServer playing games like game, configured by
+// options. The high score file of game keeps the scores of the Arcade.
+// Outputs that only make sense for a single game or that sessions would
+// write to at the same time, like recordings, saved states, the ledger and
+// snapshots, are left out.
+func NewSSHServer(game Game, options ...Option) (*SSHServer, error) {
+	arcade, err := LoadArcade(game.HighScorePath)
+	if err != nil {
+		return nil, err
+	}
+	game.RecordPath = ""
+	game.SavePath = ""
+	game.InputLogPath = ""
+	game.HighScorePath = ""
+	game.HistoryPath = ""
+	game.LedgerPath = ""
+	game.AutosaveDir = ""
+	game.GhostDir = ""
+	game.Achievements = nil
+	game.PrintBreakdown = false
+	game.Hooks = Hooks{}
+	return &SSHServer{template: game, options: options, arcade: arcade}, nil
+}
+
+// newSession returns the game of a session of player. Its score is added to
+// the Arcade once it is over and its debug log tells the player apart.
+func (server *SSHServer) newSession(player string) *Game {
+	game := server.template.sessionCopy()
+	game.Arcade = server.arcade
+	if game.config.Logger != nil {
+		game.config.Logger = game.config.Logger.With("player", player)
+	}
+	game.OnGameOver(func(game *Game, won bool) {
+		// shown on the game over screen like a failed high score
+		game.highScoreErr = server.arcade.Add(player, game)
+	})
+	return game
+}
+
+// Serve accepts ssh connections on addr until the server fails or ctx is
+// done, which quits the games of all sessions. The host key is read from
+// hostKeyPath or, if it is empty, generated anew.
+func (server *SSHServer) Serve(ctx context.Context, addr, hostKeyPath string) error {
+	sshServer := &ssh.Server{Addr: addr, Handler: server.play}
+	if hostKeyPath != "" {
+		if err := sshServer.SetOption(ssh.HostKeyFile(hostKeyPath)); err != nil {
+			return err
+		}
+	}
+	go func() {
+		<-ctx.Done()
+		// closing the connections ends the contexts of their sessions
+		sshServer.Close()
+	}()
+	fmt.Printf("Serving snail over ssh on %s\n", addr)
+	err := sshServer.ListenAndServe()
+	if errors.Is(err, ssh.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// play runs games on the terminal of session until the player quits or
+// disconnects.
+func (server *SSHServer) play(session ssh.Session) {
+	pty, windows, ok := session.Pty()
+	if !ok {
+		fmt.Fprintln(session, "snail needs a terminal, connect with ssh -t")
+		session.Exit(1)
+		return
+	}
+	info, err := terminfo.LookupTerminfo(pty.Term)
+	if err != nil {
+		info, err = terminfo.LookupTerminfo(fallbackTerm)
+	}
+	if err != nil {
+		fmt.Fprintln(session, err)
+		session.Exit(1)
+		return
+	}
+	tty := &sessionTty{Session: session, width: pty.Window.Width, height: pty.Window.Height}
+	go tty.watch(windows)
+	screen, err := tcell.NewTerminfoScreenFromTtyTerminfo(tty, info)
+	if err != nil {
+		fmt.Fprintln(session, err)
+		session.Exit(1)
+		return
+	}
+	game := server.newSession(session.User())
+	game.Screen = screen
+	game.Input = sessionInput{KeyboardInput{Screen: screen}}
+	// the game is quit once the player disconnects, a failing game only ends
+	// its own session
+	err = game.Start(server.options...)
+	if err == nil {
+		err = game.Play(session.Context())
+	}
+	if err != nil {
+		fmt.Fprintln(session, err)
+		session.Exit(1)
+		return
+	}
+	session.Exit(0)
+}
+
+// sessionInput reads the keys of a session like KeyboardInput, but pauses
+// instead of suspending, which would stop the whole server.
+type sessionInput struct {
+	KeyboardInput
+}
+
+func (input sessionInput) NextCommand() Command {
+	command := input.KeyboardInput.NextCommand()
+	if command == CommandSuspend {
+		return CommandPause
+	}
+	return command
+}
+
+// sessionTty is the tcell.Tty of the pty of an ssh session.
+type sessionTty struct {
+	ssh.Session
+
+	mu     sync.Mutex
+	width  int
+	height int
+	resize func()
+}
+
+// watch updates the size of the tty from the window changes of the session.
+func (tty *sessionTty) watch(windows <-chan ssh.Window) {
+	for window := range windows {
+		tty.mu.Lock()
+		tty.width, tty.height = window.Width, window.Height
+		resize := tty.resize
+		tty.mu.Unlock()
+		if resize != nil {
+			resize()
+		}
+	}
+}
+
+func (tty *sessionTty) Start() error { return nil }
+
+func (tty *sessionTty) Stop() error { return nil }
+
+func (tty *sessionTty) Drain() error { return nil }
+
+func (tty *sessionTty) NotifyResize(resize func()) {
+	tty.mu.Lock()
+	defer tty.mu.Unlock()
+	tty.resize = resize
+}
+
+func (tty *sessionTty) WindowSize() (int, int, error) {
+	tty.mu.Lock()
+	defer tty.mu.Unlock()
+	return tty.width, tty.height, nil
+}
@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/q713/snail/engine"
+)
+
+func TestArcade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "highscores.json")
+	key := HighScoreKey{Mode: "classic", Width: 10, Height: 10}
+	own := HighScore{HighScoreKey: key, Score: 99}
+	if err := (HighScores{own}).WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+	arcade, err := LoadArcade(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, score := range []int{3, 7, 1, 9, 5, 8} {
+		player := string(rune('a' + index))
+		if err := arcade.add(HighScore{HighScoreKey: arcadeKey(key), Player: player, Score: score}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded, err := LoadArcade(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var players []string
+	for _, score := range reloaded.Best(key) {
+		players = append(players, score.Player)
+	}
+	if want := []string{"d", "f", "b", "e", "a"}; !reflect.DeepEqual(players, want) {
+		t.Errorf("best players %v after a restart, want %v", players, want)
+	}
+	scores, err := LoadHighScores(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table := scores.Table(key); !reflect.DeepEqual(table, HighScores{own}) {
+		t.Errorf("own high scores %+v, want %+v", table, HighScores{own})
+	}
+	if best := reloaded.Best(HighScoreKey{Mode: "walls", Width: 10, Height: 10}); len(best) != 0 {
+		t.Errorf("%d arcade scores of another mode, want none", len(best))
+	}
+}
+
+func TestSSHSession(t *testing.T) {
+	dir := t.TempDir()
+	template := NewGame(WithDimensions(10), WithConfig(engine.Config{Autopilot: &engine.Autopilot{}}),
+		WithOutputs(Outputs{
+			HighScorePath: filepath.Join(dir, "highscores.json"),
+			LedgerPath:    filepath.Join(dir, "ledger.csv"),
+			AutosaveDir:   dir,
+		}))
+	server, err := NewSSHServer(*template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := server.newSession("ann"), server.newSession("bob")
+	if first.config.Autopilot == second.config.Autopilot {
+		t.Error("the sessions share the autopilot")
+	}
+	if first.HighScorePath != "" || first.LedgerPath != "" || first.AutosaveDir != "" {
+		t.Errorf("session writes high scores %q, the ledger %q and autosaves %q, want none",
+			first.HighScorePath, first.LedgerPath, first.AutosaveDir)
+	}
+
+	first.SetDefaults()
+	if err := first.ResetState(); err != nil {
+		t.Fatal(err)
+	}
+	first.runGameOver(first, false)
+	if first.highScoreErr != nil {
+		t.Fatal(first.highScoreErr)
+	}
+	best := server.arcade.Best(first.HighScoreKey())
+	if len(best) != 1 || best[0].Player != "ann" {
+		t.Errorf("arcade %+v, want the score of ann", best)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "highscores.json")); err != nil {
+		t.Errorf("arcade not written to the high score file: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/q713/snail/engine"
+)
+
+// State is the phase a Game is in. The game loop moves the game from one
+// state to the next with enter, only along the transitions listed in
+// stateTransitions.
+type State int
+
+const (
+	// StateMenu is the state before the first game started.
+	StateMenu State = iota
+	// StatePlaying is the state of a running game.
+	StatePlaying
+	// StatePaused is the state of a paused game, no ticks happen.
+	StatePaused
+	// StateGameOver is the state once a game ended.
+	StateGameOver
+)
+
+var stateNames = map[State]string{
+	StateMenu:     "menu",
+	StatePlaying:  "playing",
+	StatePaused:   "paused",
+	StateGameOver: "game over",
+}
+
+func (state State) String() string {
+	return stateNames[state]
+}
+
+// stateTransitions lists the states each state can change into. A new game
+// starts playing from the menu or once the one before is over, a game that is
+// not over yet is abandoned by ending it first, see ResetState.
+var stateTransitions = map[State][]State{
+	StateMenu:     {StatePlaying},
+	StatePlaying:  {StatePaused, StateGameOver},
+	StatePaused:   {StatePlaying, StateGameOver},
+	StateGameOver: {StatePlaying},
+}
+
+// State returns the current state of the game.
+func (game *Game) State() State {
+	return game.state
+}
+
+// enter changes the state of the game to state and runs what belongs to the
+// transition. It fails if the current state cannot change into state.
+func (game *Game) enter(state State) error {
+	allowed := false
+	for _, next := range stateTransitions[game.state] {
+		allowed = allowed || next == state
+	}
+	if !allowed {
+		return fmt.Errorf("a game cannot change from %s to %s", game.state, state)
+	}
+	old := game.state
+	game.state = state
+	switch {
+	case state == StatePaused:
+		game.countdown.Stop()
+		if err := game.LogInput(engine.InputPause); err != nil {
+			return err
+		}
+		game.runPause(game, true)
+		game.Renderer.DrawPause(game)
+		game.Renderer.Show()
+	case old == StatePaused && state == StatePlaying:
+		game.countdown.Start()
+		game.runPause(game, false)
+	}
+	return nil
+}
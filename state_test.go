@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import "testing"
+
+func TestStateTransitions(t *testing.T) {
+	if state := NewGame().State(); state != StateMenu {
+		t.Fatalf("a new game is in state %v, want %v", state, StateMenu)
+	}
+	tests := []struct {
+		from, to State
+		ok       bool
+	}{
+		{StateMenu, StatePlaying, true},
+		{StateMenu, StatePaused, false},
+		{StateMenu, StateGameOver, false},
+		{StatePlaying, StatePaused, true},
+		{StatePlaying, StateGameOver, true},
+		{StatePlaying, StateMenu, false},
+		{StatePaused, StatePlaying, true},
+		{StatePaused, StateGameOver, true},
+		{StateGameOver, StatePlaying, true},
+		{StateGameOver, StatePaused, false},
+		{StateGameOver, StateMenu, false},
+	}
+	for _, test := range tests {
+		game := newTextGame(t, WithDimensions(10), WithSeed(3))
+		game.Renderer = HeadlessRenderer{}
+		game.state = test.from
+		err := game.enter(test.to)
+		if (err == nil) != test.ok {
+			t.Errorf("%v to %v: enter = %v, want allowed %v", test.from, test.to, err, test.ok)
+		}
+	}
+}
+
+func TestResetStateFromEveryState(t *testing.T) {
+	for _, state := range []State{StateMenu, StatePlaying, StatePaused, StateGameOver} {
+		game := newTextGame(t, WithDimensions(10), WithSeed(3))
+		game.state = state
+		if err := game.ResetState(); err != nil {
+			t.Errorf("%v: ResetState = %v", state, err)
+		}
+		if game.State() != StatePlaying {
+			t.Errorf("%v: a new game is %v, want %v", state, game.State(), StatePlaying)
+		}
+	}
+}
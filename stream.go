@@ -0,0 +1,155 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+// streamBuffer is the number of frames queued per client before frames are
+// dropped for it.
+const streamBuffer = 16
+
+// StreamFrame is the state of a game sent to the clients of a Streamer.
+type StreamFrame struct {
+	BoardSummary
+	Score      int
+	Tick       int
+	FoodsEaten int
+	Over       bool
+	Outcome    string `json:",omitempty"`
+}
+
+// Frame returns the StreamFrame of the current state of the game.
+func (game *Game) Frame() StreamFrame {
+	frame := StreamFrame{
+		BoardSummary: game.Board(),
+		Score:        game.Score(),
+		Tick:         game.Tick(),
+		FoodsEaten:   game.FoodsEaten(),
+		Over:         game.Over(),
+	}
+	if frame.Over {
+		frame.Outcome = game.Outcome()
+	}
+	return frame
+}
+
+// Streamer sends the state of a game as json frames to all clients connected
+// over WebSocket, e.g. overlays in a browser or OBS.
+type Streamer struct {
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+}
+
+// NewStreamer returns a Streamer without clients.
+func NewStreamer() *Streamer {
+	return &Streamer{
+		upgrader: websocket.Upgrader{
+			// overlays are usually local files or pages of other hosts
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		clients: make(map[chan []byte]struct{}),
+	}
+}
+
+// Listen starts to accept WebSocket connections on addr in the background.
+func (streamer *Streamer) Listen(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go http.Serve(listener, streamer)
+	return nil
+}
+
+// Attach sends a frame to the clients after every tick and at the end of
+// every game.
+func (streamer *Streamer) Attach(hooks *Hooks) {
+	hooks.OnTick(streamer.Send)
+	hooks.OnGameOver(func(game *Game, won bool) { streamer.Send(game) })
+}
+
+// Send sends the current state of game to all clients. It does not block,
+// frames are dropped for clients that fall behind.
+func (streamer *Streamer) Send(game *Game) {
+	data, err := json.Marshal(game.Frame())
+	if err != nil {
+		return
+	}
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+	for client := range streamer.clients {
+		select {
+		case client <- data:
+		default:
+		}
+	}
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and writes frames
+// to it until the client goes away.
+func (streamer *Streamer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := streamer.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	frames := make(chan []byte, streamBuffer)
+	streamer.mu.Lock()
+	streamer.clients[frames] = struct{}{}
+	streamer.mu.Unlock()
+	defer func() {
+		streamer.mu.Lock()
+		delete(streamer.clients, frames)
+		streamer.mu.Unlock()
+	}()
+
+	// clients only listen, reading notices when they close the connection
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+	for {
+		select {
+		case data := <-frames:
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
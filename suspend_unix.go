@@ -0,0 +1,71 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// NotifySuspend suspends the game whenever the process receives SIGTSTP
+// until stop is called.
+func (game *Game) NotifySuspend() (stop func()) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTSTP)
+	go func() {
+		for range signals {
+			// the game is suspended by the goroutine reading the inputs
+			game.Screen.PostEvent(tcell.NewEventInterrupt(CommandSuspend))
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(signals)
+	}
+}
+
+// stopProcess stops the process until it receives SIGCONT. SIGSTOP cannot be
+// caught, so the call only returns once the process is continued.
+var stopProcess = func() {
+	_ = syscall.Kill(os.Getpid(), syscall.SIGSTOP)
+}
+
+// Suspend pauses the game, restores the terminal and stops the process. Once
+// the process is continued the screen is taken over again and the game stays
+// paused until the player resumes it.
+func (game *Game) Suspend() {
+	game.PauseGame()
+	if err := game.Screen.Suspend(); err != nil {
+		return
+	}
+	stopProcess()
+	if err := game.Screen.Resume(); err != nil {
+		return
+	}
+	game.Screen.Sync()
+}
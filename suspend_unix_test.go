@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+//go:build !windows
+
+package main
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// suspendScreen is a simulation screen that reports when it is suspended and
+// resumed.
+type suspendScreen struct {
+	tcell.SimulationScreen
+	suspended chan struct{}
+	resumed   chan struct{}
+}
+
+func (screen suspendScreen) Suspend() error {
+	screen.suspended <- struct{}{}
+	return screen.SimulationScreen.Suspend()
+}
+
+func (screen suspendScreen) Resume() error {
+	screen.resumed <- struct{}{}
+	return screen.SimulationScreen.Resume()
+}
+
+func TestHarnessSuspend(t *testing.T) {
+	stopped, continued := make(chan struct{}), make(chan struct{})
+	defer func(stop func()) { stopProcess = stop }(stopProcess)
+	stopProcess = func() {
+		stopped <- struct{}{}
+		<-continued
+	}
+	screen := suspendScreen{
+		SimulationScreen: tcell.NewSimulationScreen(""),
+		suspended:        make(chan struct{}, 1),
+		resumed:          make(chan struct{}, 1),
+	}
+	h := newHarness(t, WithScreen(screen))
+	// SIGTSTP is caught, so it does not stop the process
+	defer h.game.NotifySuspend()()
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTSTP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the process was not stopped")
+	}
+	select {
+	case <-screen.suspended:
+	default:
+		t.Error("the screen was not suspended before stopping")
+	}
+	if state := h.state(); state != StatePaused {
+		t.Errorf("state %s while stopped, want paused", state)
+	}
+	var tick int
+	h.do(func(game *Game) { tick = game.Tick() })
+	h.clock.Advance(3 * harnessDelay)
+	h.sync()
+	close(continued)
+	select {
+	case <-screen.resumed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the screen was not resumed")
+	}
+	h.do(func(game *Game) {
+		if game.Tick() != tick || game.State() != StatePaused {
+			t.Errorf("tick %d and state %s after continuing, want tick %d and paused", game.Tick(), game.State(), tick)
+		}
+	})
+	h.key(tcell.KeyRune, 'p')
+	h.waitFor("the resume", func(game *Game) bool { return game.State() == StatePlaying })
+}
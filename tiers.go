@@ -0,0 +1,80 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+var bronzeStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.NewRGBColor(205, 127, 50))
+var silverStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorSilver)
+var goldStyle = tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorGold)
+
+// ScoreTiers are the minimum scores for bronze, silver and gold in this order.
+type ScoreTiers [3]int
+
+// DefaultScoreTiers suit the default grid of 20x20 cells.
+var DefaultScoreTiers = ScoreTiers{100, 300, 600}
+
+// TierStyles are the styles of the scores that reach bronze, silver and gold
+// in this order.
+type TierStyles [3]tcell.Style
+
+// DefaultTierStyles draw the tiers in their metal colors on black.
+var DefaultTierStyles = TierStyles{bronzeStyle, silverStyle, goldStyle}
+
+// ParseScoreTiers parses three comma separated ascending scores.
+func ParseScoreTiers(text string) (ScoreTiers, error) {
+	var tiers ScoreTiers
+	fields := strings.Split(text, ",")
+	if len(fields) != len(tiers) {
+		return tiers, fmt.Errorf("expected %d score tiers, got %q", len(tiers), text)
+	}
+	for index, field := range fields {
+		score, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return tiers, fmt.Errorf("invalid score tier %q: %w", field, err)
+		}
+		if index > 0 && score < tiers[index-1] {
+			return tiers, fmt.Errorf("score tiers %q are not ascending", text)
+		}
+		tiers[index] = score
+	}
+	return tiers, nil
+}
+
+// Style returns the style of styles of the highest tier score reaches, or
+// blackWhiteStyle below the lowest tier.
+func (tiers ScoreTiers) Style(score int, styles TierStyles) tcell.Style {
+	style := blackWhiteStyle
+	for index, threshold := range tiers {
+		if score >= threshold {
+			style = styles[index]
+		}
+	}
+	return style
+}
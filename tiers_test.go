@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/q713/snail/engine"
+)
+
+func TestScoreTiersStyle(t *testing.T) {
+	tests := []struct {
+		score int
+		want  tcell.Style
+	}{
+		{0, blackWhiteStyle},
+		{99, blackWhiteStyle},
+		{100, bronzeStyle},
+		{299, bronzeStyle},
+		{300, silverStyle},
+		{600, goldStyle},
+		{10000, goldStyle},
+	}
+	for _, test := range tests {
+		if got := DefaultScoreTiers.Style(test.score, DefaultTierStyles); got != test.want {
+			t.Errorf("score %d: style %v, want %v", test.score, got, test.want)
+		}
+	}
+}
+
+func TestParseScoreTiers(t *testing.T) {
+	tests := []struct {
+		text string
+		want ScoreTiers
+		ok   bool
+	}{
+		{"100,300,600", ScoreTiers{100, 300, 600}, true},
+		{" 1, 2 ,2", ScoreTiers{1, 2, 2}, true},
+		{"100,300", ScoreTiers{}, false},
+		{"100,300,600,900", ScoreTiers{}, false},
+		{"100,x,600", ScoreTiers{}, false},
+		{"300,100,600", ScoreTiers{}, false},
+	}
+	for _, test := range tests {
+		got, err := ParseScoreTiers(test.text)
+		if (err == nil) != test.ok || test.ok && got != test.want {
+			t.Errorf("ParseScoreTiers(%q) = %v, %v, want %v ok %t", test.text, got, err, test.want, test.ok)
+		}
+	}
+}
+
+func TestHarnessScoreTierStyle(t *testing.T) {
+	themedGold := tcell.StyleDefault.Background(tcell.ColorNavy).Foreground(tcell.ColorYellow)
+	themed := Theme{Runes: RunesOn, TierStyles: TierStyles{bronzeStyle, silverStyle, themedGold}}
+	tests := []struct {
+		name  string
+		tiers ScoreTiers
+		theme Theme
+		want  tcell.Style
+	}{
+		// the snail dies without eating, with a score of 0
+		{"below bronze", ScoreTiers{1, 2, 3}, Theme{Runes: RunesOn}, blackWhiteStyle},
+		{"gold", ScoreTiers{-3, -2, -1}, Theme{Runes: RunesOn}, goldStyle},
+		{"themed gold", ScoreTiers{-3, -2, -1}, themed, themedGold},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := newHarness(t, WithSize(20, 10), WithBounds(engine.BoundsWalls), WithScoreTiers(test.tiers), WithTheme(test.theme))
+			for i := 0; h.state() != StateGameOver; i++ {
+				if i > 20 {
+					t.Fatal("the snail did not run into a wall")
+				}
+				h.tick()
+			}
+			for row, line := range strings.Split(h.screenText(), "\n") {
+				index := strings.Index(line, "You reached a score of 0 points.")
+				if index < 0 {
+					continue
+				}
+				_, _, style, _ := h.screen.GetContent(len([]rune(line[:index])), row)
+				if style != test.want {
+					t.Errorf("score drawn in %v, want %v", style, test.want)
+				}
+				return
+			}
+			t.Fatalf("score not shown:\n%s", h.screenText())
+		})
+	}
+}
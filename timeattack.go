@@ -0,0 +1,124 @@
+// MIT License
+//
+// Copyright (c) 2023 Jakob Görgen
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Countdown is the time limit of a game. It stands still while the game is
+// paused.
+type Countdown struct {
+	clock    Clock
+	left     time.Duration
+	deadline time.Time
+	expired  <-chan time.Time
+}
+
+// StartCountdown starts a countdown of d on clock.
+func StartCountdown(clock Clock, d time.Duration) *Countdown {
+	countdown := &Countdown{clock: clock, left: d}
+	countdown.Start()
+	return countdown
+}
+
+// Start continues a stopped countdown.
+func (countdown *Countdown) Start() {
+	if countdown == nil || countdown.expired != nil {
+		return
+	}
+	countdown.deadline = countdown.clock.Now().Add(countdown.left)
+	countdown.expired = countdown.clock.After(countdown.left)
+}
+
+// Stop holds the countdown at the time that is left.
+func (countdown *Countdown) Stop() {
+	if countdown == nil || countdown.expired == nil {
+		return
+	}
+	countdown.left = countdown.Left()
+	countdown.expired = nil
+}
+
+// Left returns the time that is left.
+func (countdown *Countdown) Left() time.Duration {
+	if countdown == nil {
+		return 0
+	}
+	if countdown.expired == nil {
+		return countdown.left
+	}
+	left := countdown.deadline.Sub(countdown.clock.Now())
+	if left < 0 {
+		return 0
+	}
+	return left
+}
+
+// C returns a channel that receives once the time is up. It is nil while the
+// countdown is stopped.
+func (countdown *Countdown) C() <-chan time.Time {
+	if countdown == nil {
+		return nil
+	}
+	return countdown.expired
+}
+
+// formatClock formats d as minutes and seconds, as in 1:05. Started seconds
+// are rounded up, so the clock shows 0:00 only once the time is up.
+func formatClock(d time.Duration) string {
+	seconds := int((d + time.Second - 1) / time.Second)
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// DrawTimeAttack draws the results of a time attack.
+func (renderer *CanvasRenderer) DrawTimeAttack(game *Game) {
+	played := game.MaxDuration - game.countdown.Left()
+	first := "Time attack over!"
+	if game.Died() {
+		first = fmt.Sprintf("You died with %s left!", formatClock(game.countdown.Left()))
+	}
+	perMinute := 0
+	if played > 0 {
+		perMinute = int(float64(game.Score()) / played.Minutes())
+	}
+	texts := []string{
+		first,
+		fmt.Sprintf("You reached a score of %d points.", game.Score()),
+		fmt.Sprintf("You ate %d food in %s.", game.FoodsEaten(), formatClock(played)),
+		fmt.Sprintf("That is %d points per minute.", perMinute),
+		"Score breakdown? b",
+		"Play Again? y/n",
+	}
+	width, height := game.ViewSize()
+	for index, text := range texts {
+		row := height/2 + 1 + index
+		col := width - len(text)/2 + 1
+		style := blackWhiteStyle
+		if index == 1 {
+			style = game.ScoreTiers.Style(game.Score(), game.TierStyles)
+		}
+		renderer.Canvas.DrawText(col, row, text, style)
+	}
+}